@@ -71,6 +71,7 @@ to be run only once, unless you want to update the data.`,
 			bhlnames.OptTitleMatcher(tm),
 			bhlnames.OptNLP(bayesio.New()),
 		}
+		bnOpts = append(bnOpts, cacheOpts(cfg)...)
 
 		bn := bhlnames.New(cfg, bnOpts...)
 		defer bn.Close()
@@ -2,11 +2,14 @@ package cmd
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
+	"unicode/utf8"
 
-	"github.com/gnames/bhlnames/internal/ent/input"
 	bhlnames "github.com/gnames/bhlnames/pkg"
 	"github.com/gnames/bhlnames/pkg/config"
+	"github.com/gnames/bhlnames/pkg/ent/input"
+	"github.com/gnames/gnfmt"
 	"github.com/spf13/cobra"
 )
 
@@ -18,6 +21,19 @@ func curationFlag(cmd *cobra.Command) bool {
 	return b
 }
 
+// delimiterFlag returns the delimiter for reading CSV files.
+func delimiterFlag(cmd *cobra.Command) rune {
+	s, _ := cmd.Flags().GetString("delimiter")
+	if s == `\t` {
+		return '\t'
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	if size == 0 || r == utf8.RuneError {
+		return ','
+	}
+	return r
+}
+
 func descFlag(cmd *cobra.Command) {
 	b, _ := cmd.Flags().GetBool("sort_desc")
 	if b {
@@ -25,6 +41,50 @@ func descFlag(cmd *cobra.Command) {
 	}
 }
 
+// filterFlags sets criteria for filtering of found references.
+func filterFlags(cmd *cobra.Command) {
+	for _, v := range []struct {
+		flag string
+		opt  func(int) input.Option
+	}{
+		{"year_from", input.OptYearFrom},
+		{"year_to", input.OptYearTo},
+		{"title_id", input.OptTitleID},
+		{"item_id", input.OptItemID},
+		{"min_quality", input.OptMinRefMatchQuality},
+	} {
+		i, _ := cmd.Flags().GetInt(v.flag)
+		if i > 0 {
+			inpOpts = append(inpOpts, v.opt(i))
+		}
+	}
+
+	s, _ := cmd.Flags().GetString("kingdom")
+	if s != "" {
+		inpOpts = append(inpOpts, input.OptMainKingdom(s))
+	}
+	s, _ = cmd.Flags().GetString("annot")
+	if s != "" {
+		inpOpts = append(inpOpts, input.OptAnnotNomen(s))
+	}
+}
+
+// formatFlag returns the output format. The 'jsonl' value is an alias of
+// 'compact', because compact JSON is printed one result per line.
+func formatFlag(cmd *cobra.Command) gnfmt.Format {
+	s, _ := cmd.Flags().GetString("format")
+	if s == "jsonl" {
+		return gnfmt.CompactJSON
+	}
+	f, err := gnfmt.NewFormat(s)
+	if err != nil {
+		slog.Warn("Cannot use format, using 'compact' instead",
+			"format", s, "error", err)
+		return gnfmt.CompactJSON
+	}
+	return f
+}
+
 func jobsFlag(cmd *cobra.Command) {
 	i, _ := cmd.Flags().GetInt("jobs")
 	if i > 0 {
@@ -32,6 +92,23 @@ func jobsFlag(cmd *cobra.Command) {
 	}
 }
 
+func nameMatchFlag(cmd *cobra.Command) {
+	s, _ := cmd.Flags().GetString("name_match")
+	if s != "" {
+		nm := input.NameMatch(s)
+		if !nm.IsValid() {
+			slog.Error("Unknown name match", "name_match", s)
+			os.Exit(1)
+		}
+		inpOpts = append(inpOpts, input.OptNameMatch(nm))
+	}
+
+	i, _ := cmd.Flags().GetInt("max_edit_distance")
+	if i > 0 {
+		inpOpts = append(inpOpts, input.OptMaxEditDistance(i))
+	}
+}
+
 func nomenFlag(cmd *cobra.Command) {
 	b, _ := cmd.Flags().GetBool("nomen_event")
 	if b {
@@ -39,6 +116,13 @@ func nomenFlag(cmd *cobra.Command) {
 	}
 }
 
+func offsetFlag(cmd *cobra.Command) {
+	i, _ := cmd.Flags().GetInt("offset")
+	if i > 0 {
+		inpOpts = append(inpOpts, input.OptOffset(i))
+	}
+}
+
 func portFlag(cmd *cobra.Command) {
 	p, _ := cmd.Flags().GetInt("port")
 	opts = append(opts, config.OptPortREST(p))
@@ -65,6 +149,19 @@ func shortFlag(cmd *cobra.Command) {
 	}
 }
 
+func sortFlag(cmd *cobra.Command) {
+	s, _ := cmd.Flags().GetString("sort")
+	if s == "" {
+		return
+	}
+	so := input.SortOrder(s)
+	if !so.IsValid() {
+		slog.Error("Unknown sort order", "sort", s)
+		os.Exit(1)
+	}
+	inpOpts = append(inpOpts, input.OptSortBy(so))
+}
+
 func taxonFlag(cmd *cobra.Command) {
 	b, _ := cmd.Flags().GetBool("taxon")
 	if b {
@@ -22,12 +22,14 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"fmt"
 	"log/slog"
 	"os"
 
 	"github.com/gnames/bhlnames/internal/io/builderio"
 	bhlnames "github.com/gnames/bhlnames/pkg"
 	"github.com/gnames/bhlnames/pkg/config"
+	"github.com/gnames/gnfmt"
 	"github.com/spf13/cobra"
 )
 
@@ -36,10 +38,23 @@ var initCmd = &cobra.Command{
 	Use:   "init",
 	Short: "Creates database for bhlnames",
 	Long: `Downloads database data from BHL, BHLIndex dump and uses them to build
-additional data about names and their connection to references.`,
+additional data about names and their connection to references.
+
+The data is built in a staging schema and replaces the data used by readers
+only when the build is complete. The previous build is kept, the --rollback
+flag returns to it. Data quality checks run before the new data are
+published, their report goes to RootDir/qa-report.json. If a check exceeds
+its threshold from QAThresholds setting, the build is not published.
+
+The --update flag downloads the newest dumps, compares them with the
+current data and applies only the changes. It prints a report of what
+changed. Changed records are checked without thresholds, their report goes
+to RootDir/qa-update-report.json.`,
 	Run: func(cmd *cobra.Command, _ []string) {
 		// add rebuild option. If true, all data will be deleted and redownloaded.
 		rebuildFlag(cmd)
+		rollback, _ := cmd.Flags().GetBool("rollback")
+		update, _ := cmd.Flags().GetBool("update")
 
 		cfg := config.New(opts...)
 
@@ -51,9 +66,30 @@ additional data about names and their connection to references.`,
 		}
 		defer builder.Close()
 
-		bn := bhlnames.New(cfg)
+		// cache is only used to remove results made from the old data.
+		bn := bhlnames.New(cfg, cacheOpts(cfg)...)
 		defer bn.Close()
 
+		if rollback {
+			err = bn.Rollback(builder)
+			if err != nil {
+				slog.Error("Rollback failed.", "error", err)
+				os.Exit(1)
+			}
+			slog.Info("Returned to the previous build of the database.")
+			return
+		}
+
+		if update {
+			res, err := bn.Update(builder)
+			if err != nil {
+				slog.Error("Update failed.", "error", err)
+				os.Exit(1)
+			}
+			fmt.Println(gnfmt.GNjson{Pretty: true}.Output(res, gnfmt.PrettyJSON))
+			return
+		}
+
 		err = bn.Initialize(builder)
 		if err != nil {
 			slog.Error("Initialize failed.", "error", err)
@@ -69,4 +105,10 @@ func init() {
 	initCmd.PersistentFlags().BoolP("rebuild", "r", false,
 		"rebuild database and downloaded data from scratch",
 	)
+	initCmd.PersistentFlags().Bool("rollback", false,
+		"return to the previous build of the database",
+	)
+	initCmd.PersistentFlags().BoolP("update", "u", false,
+		"apply changes of the newest dumps to the current data",
+	)
 }
@@ -0,0 +1,143 @@
+/*
+Copyright © 2024 Dmitry Mozzherin <dmozzherin@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/gnames/bhlnames/internal/io/reffndio"
+	bhlnames "github.com/gnames/bhlnames/pkg"
+	"github.com/gnames/bhlnames/pkg/config"
+	"github.com/gnames/bhlnames/pkg/ent/input"
+	"github.com/gnames/gnfmt"
+	"github.com/spf13/cobra"
+)
+
+// itemsCmd represents the items command
+var itemsCmd = &cobra.Command{
+	Use:   "items TAXON",
+	Short: "Lists BHL items where a taxon is prevalent.",
+	Long: `The items command lists BHL items (usually volumes or books) where
+the given taxon is prevalent. Without a rank it returns items where the
+taxon contains more than 50% of names. With a rank it returns items where
+the taxon is the most prevalent one at this rank, for example the most
+common order.
+
+Examples:
+
+  bhlnames items Lepidoptera
+  bhlnames items Aves --rank class --min_percent 70 --sort year
+  bhlnames items Plantae -r kingdom --year_from 1850 --limit 20 -f pretty
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		iq, err := itemsQuery(cmd, args[0])
+		if err != nil {
+			slog.Error("Cannot create items query", "error", err)
+			os.Exit(1)
+		}
+
+		cfg := config.New(opts...)
+		rf, err := reffndio.New(cfg)
+		if err != nil {
+			slog.Error("Cannot create reference finder", "error", err)
+			os.Exit(1)
+		}
+
+		bn := bhlnames.New(cfg, bhlnames.OptRefFinder(rf))
+		defer bn.Close()
+
+		res, err := bn.ItemsByTaxon(context.Background(), iq)
+		if err != nil {
+			slog.Error("Cannot get items", "taxon", iq.Taxon, "error", err)
+			os.Exit(1)
+		}
+
+		frmt := formatFlag(cmd)
+		if frmt != gnfmt.PrettyJSON {
+			frmt = gnfmt.CompactJSON
+		}
+		fmt.Println(gnfmt.GNjson{Pretty: frmt == gnfmt.PrettyJSON}.Output(res, frmt))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(itemsCmd)
+
+	itemsCmd.Flags().StringP("rank", "r", "",
+		"Rank of the taxon: kingdom, phylum, class, order, family or genus.")
+	itemsCmd.Flags().IntP("min_percent", "p", 0,
+		"Minimal percentage of the taxon names in an item.")
+	itemsCmd.Flags().IntP("min_names", "n", 0,
+		"Minimal number of unique names in an item.")
+	itemsCmd.Flags().IntP("year_from", "y", 0,
+		"Return items published in this year or later.")
+	itemsCmd.Flags().IntP("year_to", "Y", 0,
+		"Return items published in this year or earlier.")
+	itemsCmd.Flags().StringP("sort", "s", "",
+		"Order of items: 'percent' (default), 'names' or 'year'.")
+	itemsCmd.Flags().IntP("limit", "l", 0,
+		"Maximum number of returned items, 0 means no limit.")
+	itemsCmd.Flags().IntP("offset", "o", 0, "Number of items to skip.")
+	itemsCmd.Flags().StringP("format", "f", "compact",
+		"Output format can be 'compact' or 'pretty'.")
+}
+
+// itemsQuery creates a query for ItemsByTaxon from the command flags.
+func itemsQuery(cmd *cobra.Command, taxon string) (input.ItemsQuery, error) {
+	var iqOpts []input.ItemsOption
+	for _, v := range []struct {
+		flag string
+		opt  func(int) input.ItemsOption
+	}{
+		{"min_percent", input.OptItemsMinPercent},
+		{"min_names", input.OptItemsMinNamesNum},
+		{"year_from", input.OptItemsYearFrom},
+		{"year_to", input.OptItemsYearTo},
+		{"limit", input.OptItemsLimit},
+		{"offset", input.OptItemsOffset},
+	} {
+		i, _ := cmd.Flags().GetInt(v.flag)
+		if i > 0 {
+			iqOpts = append(iqOpts, v.opt(i))
+		}
+	}
+
+	s, _ := cmd.Flags().GetString("rank")
+	rank := input.TaxonRank(s)
+	if !rank.IsValid() {
+		return input.ItemsQuery{}, fmt.Errorf("unknown rank '%s'", s)
+	}
+	iqOpts = append(iqOpts, input.OptItemsRank(rank))
+
+	s, _ = cmd.Flags().GetString("sort")
+	sortBy := input.ItemsSort(s)
+	if !sortBy.IsValid() {
+		return input.ItemsQuery{}, fmt.Errorf("unknown sort order '%s'", s)
+	}
+	iqOpts = append(iqOpts, input.OptItemsSortBy(sortBy))
+
+	return input.NewItemsQuery(taxon, iqOpts...), nil
+}
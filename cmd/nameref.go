@@ -22,18 +22,24 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"os"
 
-	"github.com/gnames/bhlnames/internal/ent/input"
+	"github.com/gnames/bhlnames/internal/ent/output"
+	"github.com/gnames/bhlnames/internal/io/batchio"
 	"github.com/gnames/bhlnames/internal/io/bayesio"
 	"github.com/gnames/bhlnames/internal/io/reffndio"
 	"github.com/gnames/bhlnames/internal/io/ttlmchio"
 	bhlnames "github.com/gnames/bhlnames/pkg"
 	"github.com/gnames/bhlnames/pkg/config"
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/input"
 	"github.com/gnames/gnfmt"
+	"github.com/gnames/gnsys"
 	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
 )
 
 var inpOpts []input.Option
@@ -49,12 +55,22 @@ var namerefCmd = &cobra.Command{
 	There are additional options that allow to return not only results for a
 	particular name-string, but for all synonyms of a taxon. It is also possible
 	to find nomenclatural events.
+
+	Instead of a name-string it is possible to provide a path to a file
+	(or '-' for STDIN). The file can be in CSV, TSV, JSON Lines format, or
+	contain one name-string per line. CSV and TSV files must have a header
+	with a name column (nameString). Other recognized columns are id,
+	refString, yearStart, yearEnd, volume and pages. Each line of a JSON
+	Lines file is an input like the one used by the POST /name_refs API.
+	Results are returned in the same order as the input. By default they
+	are JSON Lines, with '--format csv' or '--format tsv' every found
+	reference becomes one row.
 	`,
 
 	Run: func(cmd *cobra.Command, args []string) {
 		for _, flag := range []flagFunc{
 			jobsFlag, descFlag, shortFlag, taxonFlag, refsLimitFlag,
-			nomenFlag,
+			nomenFlag, offsetFlag, filterFlags, sortFlag, nameMatchFlag,
 		} {
 			flag(cmd)
 		}
@@ -78,12 +94,17 @@ var namerefCmd = &cobra.Command{
 			bhlnames.OptTitleMatcher(tm),
 			bhlnames.OptNLP(bayesio.New()),
 		}
+		bnOpts = append(bnOpts, cacheOpts(cfg)...)
 
 		bn := bhlnames.New(cfg, bnOpts...)
 		defer bn.Close()
 
 		argData := readArgs(cmd, args)
-		name(bn, argData)
+		if argData.path != "" {
+			nameBatch(bn, argData.path, delimiterFlag(cmd), formatFlag(cmd))
+			return
+		}
+		name(bn, argData, formatFlag(cmd))
 	},
 }
 
@@ -92,7 +113,7 @@ func init() {
 
 	namerefCmd.PersistentFlags().MarkHidden("rebuild")
 	namerefCmd.Flags().StringP("format", "f", "compact",
-		"JSON output format can be 'compact' or 'pretty.")
+		"Output format can be 'compact', 'pretty', 'jsonl', 'csv' or 'tsv'.")
 
 	namerefCmd.Flags().IntP("jobs", "j", 0,
 		"Number of parallel jobs to get references.")
@@ -100,6 +121,9 @@ func init() {
 	namerefCmd.Flags().BoolP("sort_desc", "d", false,
 		"Sort references by year in descending order.")
 
+	namerefCmd.Flags().String("sort", "",
+		"Sort references by 'year', 'year_desc', 'odds', 'title' or 'page'.")
+
 	namerefCmd.Flags().BoolP("short_output", "s", false,
 		"Return only summary (no references data).")
 
@@ -109,21 +133,62 @@ func init() {
 	namerefCmd.Flags().BoolP("nomen_event", "n", false,
 		"Find nomenclatural events.")
 
+	namerefCmd.Flags().String("name_match", "",
+		"Compare names as 'exact' (default), 'stem' or 'fuzzy'.")
+
+	namerefCmd.Flags().Int("max_edit_distance", 0,
+		"Maximum edit distance for 'fuzzy' name match (1-3, default 1).")
+
 	namerefCmd.Flags().IntP("refs_limit", "l", 0,
 		"Limit number of returned references")
 
+	namerefCmd.Flags().Int("offset", 0,
+		"Skip the given number of references, use with refs_limit for paging.")
+
+	namerefCmd.Flags().Int("year_from", 0,
+		"Return references published in this year or later.")
+
+	namerefCmd.Flags().Int("year_to", 0,
+		"Return references published in this year or earlier.")
+
+	namerefCmd.Flags().Int("title_id", 0,
+		"Return references only from a BHL title with this ID.")
+
+	namerefCmd.Flags().Int("item_id", 0,
+		"Return references only from a BHL item with this ID.")
+
+	namerefCmd.Flags().String("kingdom", "",
+		"Return references from items where the kingdom is the most prevalent.")
+
+	namerefCmd.Flags().String("annot", "",
+		"Return references with nomenclatural annotation (e.g. SP_NOV, or ANY).")
+
+	namerefCmd.Flags().Int("min_quality", 0,
+		"Keep references with RefMatchQuality from this value (1-5), needs -n.")
+
 	namerefCmd.Flags().StringP("delimiter", "D", ",",
-		"Delimiter for reading CSV files, default is comma.")
+		"Delimiter for reading CSV files, default is comma, use '\\t' for tab.")
 }
 
 type data struct {
 	name, ref string
+
+	// path is a path to a file with many inputs, or '-' for STDIN.
+	path string
 }
 
 func readArgs(cmd *cobra.Command, args []string) data {
 	var res data
 	switch len(args) {
 	case 1:
+		if args[0] == "-" {
+			res.path = args[0]
+			break
+		}
+		if exists, _ := gnsys.FileExists(args[0]); exists {
+			res.path = args[0]
+			break
+		}
 		res.name = args[0]
 	case 2:
 		res.name = args[0]
@@ -136,19 +201,141 @@ func readArgs(cmd *cobra.Command, args []string) data {
 	return res
 }
 
-func name(bn bhlnames.BHLnames, args data) {
+func name(bn bhlnames.BHLnames, args data, frmt gnfmt.Format) {
 	inpOpts = append(inpOpts, input.OptNameString(args.name))
 	if args.ref != "" {
 		inpOpts = append(inpOpts, input.OptRefString(args.ref))
 	}
 
 	inp := input.New(bn.ParserPool(), inpOpts...)
-	enc := gnfmt.GNjson{}
-	res, err := bn.NameRefs(inp)
+	res, err := bn.NameRefs(context.Background(), inp)
+	if err != nil {
+		slog.Error("Cannot get names with references", "error", err)
+		os.Exit(1)
+	}
+	if frmt == gnfmt.CSV || frmt == gnfmt.TSV {
+		fmt.Println(output.CSVHeader(frmt))
+	}
+	fmt.Println(output.Output(res, frmt))
+}
+
+// nameBatch reads inputs from a file or STDIN and sends them to
+// NameRefsStream. The results are printed in the same order as the inputs.
+func nameBatch(
+	bn bhlnames.BHLnames,
+	path string,
+	sep rune,
+	frmt gnfmt.Format,
+) {
+	f := os.Stdin
+	if path != "-" {
+		var err error
+		f, err = os.Open(path)
+		if err != nil {
+			slog.Error("Cannot open file", "path", path, "error", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+	}
+
+	rd, err := batchio.New(f, sep, bn.ParserPool(), inpOpts)
 	if err != nil {
+		slog.Error("Cannot read batch input", "path", path, "error", err)
+		os.Exit(1)
+	}
+
+	chRead := make(chan input.Input)
+	chIn := make(chan input.Input)
+	chIDs := make(chan string, bn.Config().JobsNum)
+	chOut := make(chan *bhl.RefsByName)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	g, ctx := errgroup.WithContext(ctx)
+
+	g.Go(func() error {
+		defer close(chRead)
+		return rd.Read(ctx, chRead)
+	})
+
+	// register the order of inputs before sending them for processing
+	g.Go(func() error {
+		defer close(chIn)
+		defer close(chIDs)
+		for inp := range chRead {
+			select {
+			case <-ctx.Done():
+				for range chRead {
+				}
+				return ctx.Err()
+			case chIDs <- inp.ID:
+			}
+			select {
+			case <-ctx.Done():
+				for range chRead {
+				}
+				return ctx.Err()
+			case chIn <- inp:
+			}
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		return bn.NameRefsStream(ctx, chIn, chOut)
+	})
+
+	g.Go(func() error {
+		if frmt == gnfmt.CSV || frmt == gnfmt.TSV {
+			fmt.Println(output.CSVHeader(frmt))
+		}
+		writeOrdered(chIDs, chOut, func(res *bhl.RefsByName) {
+			fmt.Println(output.Output(res, frmt))
+		})
+		return nil
+	})
+
+	if err = g.Wait(); err != nil {
 		slog.Error("Cannot get names with references", "error", err)
 		os.Exit(1)
 	}
-	out := enc.Output(res, gnfmt.CompactJSON)
-	fmt.Println(out)
+}
+
+// writeOrdered receives results in any order and writes them out in the
+// order of IDs that come from chIDs.
+func writeOrdered(
+	chIDs <-chan string,
+	chOut <-chan *bhl.RefsByName,
+	write func(*bhl.RefsByName),
+) {
+	var order []string
+	pending := make(map[string][]*bhl.RefsByName)
+
+	for chIDs != nil || chOut != nil {
+		select {
+		case id, ok := <-chIDs:
+			if !ok {
+				chIDs = nil
+				continue
+			}
+			order = append(order, id)
+		case res, ok := <-chOut:
+			if !ok {
+				chOut = nil
+				continue
+			}
+			id := res.Input.ID
+			pending[id] = append(pending[id], res)
+		}
+
+		for len(order) > 0 && len(pending[order[0]]) > 0 {
+			id := order[0]
+			write(pending[id][0])
+			pending[id] = pending[id][1:]
+			if len(pending[id]) == 0 {
+				delete(pending, id)
+			}
+			order = order[1:]
+		}
+	}
 }
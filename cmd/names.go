@@ -0,0 +1,117 @@
+/*
+Copyright © 2024 Dmitry Mozzherin <dmozzherin@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+
+	"github.com/gnames/bhlnames/internal/io/reffndio"
+	bhlnames "github.com/gnames/bhlnames/pkg"
+	"github.com/gnames/bhlnames/pkg/config"
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
+	"github.com/gnames/gnfmt"
+	"github.com/spf13/cobra"
+)
+
+// namesCmd represents the names command
+var namesCmd = &cobra.Command{
+	Use:   "names",
+	Short: "Lists unique names detected in a BHL item or part.",
+	Long: `The names command lists unique scientific names detected in a BHL
+item (usually a volume or a book) or in a BHL part (usually a paper).
+For every name it shows the number of occurrences, the first and the last
+page, classification and nomenclatural annotations.
+
+Examples:
+
+  bhlnames names item 73397
+  bhlnames names part 39371 -f pretty
+`,
+}
+
+// namesItemCmd represents the 'names item' command
+var namesItemCmd = &cobra.Command{
+	Use:   "item ITEM_ID",
+	Short: "Lists unique names detected in a BHL item.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		nameList(cmd, args[0], "item", bhlnames.BHLnames.ItemNames)
+	},
+}
+
+// namesPartCmd represents the 'names part' command
+var namesPartCmd = &cobra.Command{
+	Use:   "part PART_ID",
+	Short: "Lists unique names detected in a BHL part.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		nameList(cmd, args[0], "part", bhlnames.BHLnames.PartNames)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(namesCmd)
+	namesCmd.AddCommand(namesItemCmd, namesPartCmd)
+
+	namesCmd.PersistentFlags().MarkHidden("rebuild")
+	namesCmd.PersistentFlags().StringP("format", "f", "compact",
+		"Output format can be 'compact' or 'pretty'.")
+}
+
+// nameList prints names for an item or a part with the given ID.
+func nameList(
+	cmd *cobra.Command,
+	idStr, kind string,
+	get func(bhlnames.BHLnames, context.Context, int) (*bhl.NameList, error),
+) {
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		slog.Error("ID is not an integer", kind+"_id", idStr)
+		os.Exit(1)
+	}
+
+	cfg := config.New(opts...)
+	rf, err := reffndio.New(cfg)
+	if err != nil {
+		slog.Error("Cannot create reference finder", "error", err)
+		os.Exit(1)
+	}
+
+	bn := bhlnames.New(cfg, bhlnames.OptRefFinder(rf))
+	defer bn.Close()
+
+	res, err := get(bn, context.Background(), id)
+	if err != nil {
+		slog.Error("Cannot get names", kind+"_id", id, "error", err)
+		os.Exit(1)
+	}
+
+	frmt := formatFlag(cmd)
+	if frmt != gnfmt.PrettyJSON {
+		frmt = gnfmt.CompactJSON
+	}
+	fmt.Println(gnfmt.GNjson{Pretty: frmt == gnfmt.PrettyJSON}.Output(res, frmt))
+}
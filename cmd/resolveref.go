@@ -0,0 +1,97 @@
+/*
+Copyright © 2024 Dmitry Mozzherin <dmozzherin@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/gnames/bhlnames/internal/io/bayesio"
+	"github.com/gnames/bhlnames/internal/io/reffndio"
+	"github.com/gnames/bhlnames/internal/io/ttlmchio"
+	bhlnames "github.com/gnames/bhlnames/pkg"
+	"github.com/gnames/bhlnames/pkg/config"
+	"github.com/gnames/bhlnames/pkg/ent/input"
+	"github.com/gnames/gnfmt"
+	"github.com/spf13/cobra"
+)
+
+// resolverefCmd represents the resolveref command
+var resolverefCmd = &cobra.Command{
+	Use:   "resolveref REFERENCE",
+	Short: "Finds BHL pages for a citation that has no scientific name.",
+	Long: `The resolveref command finds BHL titles that match abbreviations of
+a citation, selects their items by volume and year, and pages by page
+numbers. The candidates are ranked by year, volume, page and title
+scores, the best match goes first.
+
+Examples:
+
+  bhlnames resolveref "Docums Mycol. 34:50-51. (2008)."
+  bhlnames resolveref "Bull. Amer. Mus. Nat. Hist. 12: 188-189 (1899)" -f pretty
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.New(opts...)
+
+		rf, err := reffndio.New(cfg)
+		if err != nil {
+			slog.Error("Cannot create reference finder", "error", err)
+			os.Exit(1)
+		}
+
+		tm, err := ttlmchio.New(cfg)
+		if err != nil {
+			slog.Error("Cannot create title matcher", "error", err)
+			os.Exit(1)
+		}
+
+		bn := bhlnames.New(cfg,
+			bhlnames.OptRefFinder(rf),
+			bhlnames.OptTitleMatcher(tm),
+			bhlnames.OptNLP(bayesio.New()),
+		)
+		defer bn.Close()
+
+		ref := input.Reference{RefString: args[0]}
+		res, err := bn.ResolveReference(context.Background(), ref)
+		if err != nil {
+			slog.Error("Cannot resolve reference", "reference", args[0], "error", err)
+			os.Exit(1)
+		}
+
+		frmt := formatFlag(cmd)
+		if frmt != gnfmt.PrettyJSON {
+			frmt = gnfmt.CompactJSON
+		}
+		fmt.Println(gnfmt.GNjson{Pretty: frmt == gnfmt.PrettyJSON}.Output(res, frmt))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(resolverefCmd)
+
+	resolverefCmd.Flags().StringP("format", "f", "compact",
+		"Output format can be 'compact' or 'pretty'.")
+}
@@ -59,6 +59,7 @@ var restCmd = &cobra.Command{
 			bhlnames.OptTitleMatcher(tm),
 			bhlnames.OptNLP(bayesio.New()),
 		}
+		bnOpts = append(bnOpts, cacheOpts(cfg)...)
 
 		bn := bhlnames.New(cfg, bnOpts...)
 		defer bn.Close()
@@ -29,6 +29,8 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/gnames/bhlnames/internal/io/cacheio"
+	bhlnames "github.com/gnames/bhlnames/pkg"
 	"github.com/gnames/bhlnames/pkg/config"
 	"github.com/gnames/gnsys"
 	"github.com/spf13/viper"
@@ -44,16 +46,32 @@ var (
 // fConfig purpose is to achieve automatic import of data from the
 // configuration file, if it exists.
 type fConfig struct {
-	BHLDumpURL  string
-	BHLNamesURL string
-	CoLDataURL  string
-	DbDatabase  string
-	DbHost      string
-	DbUser      string
-	DbPass      string
-	JobsNum     int
-	PortREST    int
-	RootDir     string
+	BHLDumpURL         string
+	BHLDumpSHA256      string
+	BHLNamesURL        string
+	BHLNamesSHA256     string
+	CacheType          string
+	CacheSize          int
+	CacheTTL           int
+	CoLDataURL         string
+	CoLDataSHA256      string
+	DbDatabase         string
+	DbDSN              string
+	DbHost             string
+	DbPort             int
+	DbSSLMode          string
+	DbSSLRootCert      string
+	DbMaxConns         int
+	DbMinConns         int
+	DbStatementTimeout int
+	DbUser             string
+	DbPass             string
+	DbQueryTimeout     int
+	JobsNum            int
+	MaxBatchSize       int
+	PortREST           int
+	QAThresholds       map[string]float64
+	RootDir            string
 }
 
 // rootCmd represents the base command when called without any subcommands
@@ -100,13 +118,28 @@ func initConfig() {
 	viper.SetConfigName(configFile)
 
 	viper.BindEnv("BHLDumpURL", "BHL_NAMES_DUMP_URL")
+	viper.BindEnv("BHLDumpSHA256", "BHL_NAMES_DUMP_SHA256")
 	viper.BindEnv("BHLNamesURL", "BHL_NAMES_URL")
+	viper.BindEnv("BHLNamesSHA256", "BHL_NAMES_SHA256")
+	viper.BindEnv("CacheType", "BHL_NAMES_CACHE_TYPE")
+	viper.BindEnv("CacheSize", "BHL_NAMES_CACHE_SIZE")
+	viper.BindEnv("CacheTTL", "BHL_NAMES_CACHE_TTL")
 	viper.BindEnv("ColDataURL", "BHL_NAMES_COL_DATA_URL")
+	viper.BindEnv("CoLDataSHA256", "BHL_NAMES_COL_DATA_SHA256")
 	viper.BindEnv("DbDatabase", "BHL_NAMES_DB_DATABASE")
+	viper.BindEnv("DbDSN", "BHL_NAMES_DB_DSN")
 	viper.BindEnv("DbHost", "BHL_NAMES_DB_HOST")
+	viper.BindEnv("DbPort", "BHL_NAMES_DB_PORT")
+	viper.BindEnv("DbSSLMode", "BHL_NAMES_DB_SSL_MODE")
+	viper.BindEnv("DbSSLRootCert", "BHL_NAMES_DB_SSL_ROOT_CERT")
+	viper.BindEnv("DbMaxConns", "BHL_NAMES_DB_MAX_CONNS")
+	viper.BindEnv("DbMinConns", "BHL_NAMES_DB_MIN_CONNS")
+	viper.BindEnv("DbStatementTimeout", "BHL_NAMES_DB_STATEMENT_TIMEOUT")
 	viper.BindEnv("DbUser", "BHL_NAMES_DB_USER")
 	viper.BindEnv("DbPass", "BHL_NAMES_DB_PASS")
+	viper.BindEnv("DbQueryTimeout", "BHL_NAMES_DB_QUERY_TIMEOUT")
 	viper.BindEnv("JobsNum", "BHL_NAMES_JOBS_NUM")
+	viper.BindEnv("MaxBatchSize", "BHL_NAMES_MAX_BATCH_SIZE")
 	viper.BindEnv("PortREST", "BHL_NAMES_PORT_REST")
 	viper.BindEnv("RootDir", "BHL_NAMES_ROOT_DIR")
 	viper.AutomaticEnv()
@@ -136,36 +169,99 @@ func getOpts() []config.Option {
 	if cfg.BHLDumpURL != "" {
 		opts = append(opts, config.OptBHLDumpURL(cfg.BHLDumpURL))
 	}
+	if cfg.BHLDumpSHA256 != "" {
+		opts = append(opts, config.OptBHLDumpSHA256(cfg.BHLDumpSHA256))
+	}
 	if cfg.BHLNamesURL != "" {
 		opts = append(opts, config.OptBHLNamesURL(cfg.BHLNamesURL))
 	}
+	if cfg.BHLNamesSHA256 != "" {
+		opts = append(opts, config.OptBHLNamesSHA256(cfg.BHLNamesSHA256))
+	}
+	if cfg.CacheType != "" {
+		opts = append(opts, config.OptCacheType(cfg.CacheType))
+	}
+	if cfg.CacheSize != 0 {
+		opts = append(opts, config.OptCacheSize(cfg.CacheSize))
+	}
+	// zero TTL means that results do not expire
+	if viper.IsSet("CacheTTL") {
+		opts = append(opts, config.OptCacheTTL(cfg.CacheTTL))
+	}
 	if cfg.CoLDataURL != "" {
 		opts = append(opts, config.OptCoLDataURL(cfg.CoLDataURL))
 	}
+	if cfg.CoLDataSHA256 != "" {
+		opts = append(opts, config.OptCoLDataSHA256(cfg.CoLDataSHA256))
+	}
 	if cfg.DbDatabase != "" {
 		opts = append(opts, config.OptDbDatabase(cfg.DbDatabase))
 	}
+	if cfg.DbDSN != "" {
+		opts = append(opts, config.OptDbDSN(cfg.DbDSN))
+	}
 	if cfg.DbHost != "" {
 		opts = append(opts, config.OptDbHost(cfg.DbHost))
 	}
+	if cfg.DbPort != 0 {
+		opts = append(opts, config.OptDbPort(cfg.DbPort))
+	}
+	if cfg.DbSSLMode != "" {
+		opts = append(opts, config.OptDbSSLMode(cfg.DbSSLMode))
+	}
+	if cfg.DbSSLRootCert != "" {
+		opts = append(opts, config.OptDbSSLRootCert(cfg.DbSSLRootCert))
+	}
+	if cfg.DbMaxConns != 0 {
+		opts = append(opts, config.OptDbMaxConns(cfg.DbMaxConns))
+	}
+	if viper.IsSet("DbMinConns") {
+		opts = append(opts, config.OptDbMinConns(cfg.DbMinConns))
+	}
+	if viper.IsSet("DbStatementTimeout") {
+		opts = append(opts, config.OptDbStatementTimeout(cfg.DbStatementTimeout))
+	}
 	if cfg.DbUser != "" {
 		opts = append(opts, config.OptDbUser(cfg.DbUser))
 	}
 	if cfg.DbPass != "" {
 		opts = append(opts, config.OptDbPass(cfg.DbPass))
 	}
+	if viper.IsSet("DbQueryTimeout") {
+		opts = append(opts, config.OptDbQueryTimeout(cfg.DbQueryTimeout))
+	}
 	if cfg.JobsNum != 0 {
 		opts = append(opts, config.OptJobsNum(cfg.JobsNum))
 	}
+	if cfg.MaxBatchSize != 0 {
+		opts = append(opts, config.OptMaxBatchSize(cfg.MaxBatchSize))
+	}
 	if cfg.PortREST != 0 {
 		opts = append(opts, config.OptPortREST(cfg.PortREST))
 	}
+	if len(cfg.QAThresholds) > 0 {
+		opts = append(opts, config.OptQAThresholds(cfg.QAThresholds))
+	}
 	if cfg.RootDir != "" {
 		opts = append(opts, config.OptRootDir(cfg.RootDir))
 	}
 	return opts
 }
 
+// cacheOpts creates the NameRefs cache according to the configuration.
+// It returns no options if the cache is disabled.
+func cacheOpts(cfg config.Config) []bhlnames.Option {
+	c, err := cacheio.New(cfg)
+	if err != nil {
+		slog.Error("Cannot create cache.", "error", err)
+		os.Exit(1)
+	}
+	if c == nil {
+		return nil
+	}
+	return []bhlnames.Option{bhlnames.OptCache(c)}
+}
+
 // touchConfigFile checks if config file exists, and if not, it gets created.
 func touchConfigFile(configPath string) {
 	exists, _ := gnsys.FileExists(configPath)
@@ -0,0 +1,82 @@
+/*
+Copyright © 2024 Dmitry Mozzherin <dmozzherin@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/gnames/bhlnames/internal/io/reffndio"
+	bhlnames "github.com/gnames/bhlnames/pkg"
+	"github.com/gnames/bhlnames/pkg/config"
+	"github.com/gnames/gnfmt"
+	"github.com/spf13/cobra"
+)
+
+// statusCmd represents the status command
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Shows versions of datasets the database was built from.",
+	Long: `The status command shows provenance of the data in the database:
+source URLs, file sizes, SHA-256 checksums and dump dates of BHL, bhlindex
+and Catalogue of Life datasets, as well as the latest builds and updates
+with the bhlnames version, duration and numbers of rows in tables.
+
+Examples:
+
+  bhlnames status
+  bhlnames status -f compact
+`,
+	Run: func(cmd *cobra.Command, _ []string) {
+		cfg := config.New(opts...)
+
+		rf, err := reffndio.New(cfg)
+		if err != nil {
+			slog.Error("Cannot create reference finder", "error", err)
+			os.Exit(1)
+		}
+
+		bn := bhlnames.New(cfg, bhlnames.OptRefFinder(rf))
+		defer bn.Close()
+
+		res, err := bn.DataInfo(context.Background())
+		if err != nil {
+			slog.Error("Cannot get data info", "error", err)
+			os.Exit(1)
+		}
+
+		frmt := formatFlag(cmd)
+		if frmt != gnfmt.CompactJSON {
+			frmt = gnfmt.PrettyJSON
+		}
+		fmt.Println(gnfmt.GNjson{Pretty: frmt == gnfmt.PrettyJSON}.Output(res, frmt))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+
+	statusCmd.Flags().StringP("format", "f", "pretty",
+		"Output format can be 'compact' or 'pretty'.")
+}
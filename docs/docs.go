@@ -41,6 +41,24 @@ const docTemplate = `{
                 }
             }
         },
+        "/cache_stats": {
+            "get": {
+                "description": "Returns the type of the name-references cache and the number of its hits and misses since the start of the service.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get statistics of the results cache",
+                "operationId": "get-cache-stats",
+                "responses": {
+                    "200": {
+                        "description": "Cache statistics",
+                        "schema": {
+                            "$ref": "#/definitions/cache.Stats"
+                        }
+                    }
+                }
+            }
+        },
         "/cached_refs/{external_id}": {
             "get": {
                 "consumes": [
@@ -67,6 +85,13 @@ const docTemplate = `{
                         "name": "all_refs",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "ID of the data source of the external ID, 1 (Catalogue of Life) by default.",
+                        "name": "data_source_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -75,6 +100,54 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/bhl.RefsByName"
                         }
+                    },
+                    "400": {
+                        "description": "Invalid data source ID",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "External ID not found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Database is unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/data_info": {
+            "get": {
+                "description": "Returns datasets the data were built from (source URLs, file sizes, SHA-256 checksums and dates of dumps), the latest builds with the bhlnames version, duration and numbers of rows in tables, and the version of the running service. It allows to cite the exact version of the data behind a result.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get versions of datasets behind the data",
+                "operationId": "get-data-info",
+                "responses": {
+                    "200": {
+                        "description": "Provenance of the data",
+                        "schema": {
+                            "$ref": "#/definitions/bhl.DataInfo"
+                        }
+                    },
+                    "404": {
+                        "description": "Data were built by an older version",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Database is unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
                     }
                 }
             }
@@ -97,6 +170,13 @@ const docTemplate = `{
                         "name": "item_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 10,
+                        "description": "Maximum number of taxa for each rank in the taxonomic distribution, all taxa are returned by default.",
+                        "name": "taxa_limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -105,6 +185,73 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/bhl.Item"
                         }
+                    },
+                    "400": {
+                        "description": "Invalid item ID or taxa limit",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Item not found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Database is unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/{item_id}/names": {
+            "get": {
+                "description": "Lists unique names of an item with the number of their occurrences, first and last pages, classification and nomenclatural annotations. Names are sorted by their first appearance.",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get unique scientific names detected in a BHL item.",
+                "operationId": "get-item-names",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 73397,
+                        "description": "Item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Unique names of the item",
+                        "schema": {
+                            "$ref": "#/definitions/bhl.NameList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid item ID",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Item not found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Database is unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
                     }
                 }
             }
@@ -137,6 +284,18 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/bhl.RefsByName"
                         }
+                    },
+                    "400": {
+                        "description": "Malformed input",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Database is unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
                     }
                 }
             }
@@ -174,6 +333,97 @@ const docTemplate = `{
                         "description": "If true, tries to find nomenclatural event reference.",
                         "name": "nomen_event",
                         "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "description": "If true, includes references of the taxon's synonyms.",
+                        "name": "taxon",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"stem\"",
+                        "description": "How to compare names: exact, stem or fuzzy.",
+                        "name": "name_match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Maximum edit distance for the fuzzy name match (1-3).",
+                        "name": "max_edit_distance",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"year\"",
+                        "description": "Order of references: year, year_desc, odds, title or page.",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 20,
+                        "description": "Maximum number of references to return.",
+                        "name": "refs_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 20,
+                        "description": "Number of references to skip.",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1890,
+                        "description": "Return references published in this year or later.",
+                        "name": "year_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1920,
+                        "description": "Return references published in this year or earlier.",
+                        "name": "year_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 7928,
+                        "description": "Return references only from this BHL title.",
+                        "name": "title_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 32506,
+                        "description": "Return references only from this BHL item.",
+                        "name": "item_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"Animalia\"",
+                        "description": "Return references from items where this kingdom is the most prevalent.",
+                        "name": "kingdom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"SP_NOV\"",
+                        "description": "Return references with this nomenclatural annotation, ANY for any annotation.",
+                        "name": "annot",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 3,
+                        "description": "Return references with RefMatchQuality of at least this value. Needs a reference or nomen_event, otherwise the request is rejected.",
+                        "name": "min_quality",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -182,6 +432,208 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/bhl.RefsByName"
                         }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Database is unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/name_refs_batch": {
+            "post": {
+                "description": "Takes a JSON array or newline-delimited JSON (NDJSON) of inputs and returns the results as NDJSON, one line per input. Results are sent as soon as they are ready, so their order might differ from the order of inputs. Use the ` + "`" + `id` + "`" + ` field of the inputs to correlate results. If an input has no ` + "`" + `id` + "`" + `, it gets its position in the batch (starting from 1) as an ID. If the search fails for one of the inputs, its result has the ` + "`" + `error` + "`" + ` field set, and other inputs are still processed. The number of inputs is limited by the ` + "`" + `MaxBatchSize` + "`" + ` setting of the service.",
+                "consumes": [
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "produces": [
+                    "application/x-ndjson"
+                ],
+                "summary": "Finds BHL references for a batch of names",
+                "operationId": "post-name-refs-batch",
+                "parameters": [
+                    {
+                        "description": "A list of inputs",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/input.Input"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Matched references for one of the inputs, one JSON object per line",
+                        "schema": {
+                            "$ref": "#/definitions/bhl.RefsByName"
+                        }
+                    },
+                    "400": {
+                        "description": "Malformed or empty batch, or an input with unknown parameters",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Batch exceeds the maximum size",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pages/{page_id}/names": {
+            "get": {
+                "description": "Lists every name detected on a page with its offsets in the page text, matched and current canonical forms, classification, match type and nomenclatural annotation. Offsets allow to highlight names in OCR text and page images.",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get scientific names detected on a BHL page",
+                "operationId": "get-page-names",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 6589171,
+                        "description": "BHL page ID.",
+                        "name": "page_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Names detected on the page",
+                        "schema": {
+                            "$ref": "#/definitions/bhl.PageNames"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid page ID",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Page not found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Database is unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/parse_ref": {
+            "post": {
+                "description": "Extracts years, volume and pages from a reference string, creates its abbreviation and finds BHL titles with matching abbreviations. Only the refString field of the input is used. It helps to find out why a reference was not resolved.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Parses a reference string and finds matching BHL titles.",
+                "operationId": "post-parse-ref",
+                "parameters": [
+                    {
+                        "description": "Reference with refString",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/input.Reference"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Parsed reference and matched titles",
+                        "schema": {
+                            "$ref": "#/definitions/bhl.ParsedRef"
+                        }
+                    },
+                    "400": {
+                        "description": "Malformed input or empty reference string",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Database is unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/parts/{part_id}/names": {
+            "get": {
+                "description": "Lists unique names of a part (usually a scientific paper) with the number of their occurrences, first and last pages, classification and nomenclatural annotations. Names are sorted by their first appearance.",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get unique scientific names detected in a BHL part.",
+                "operationId": "get-part-names",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 39371,
+                        "description": "Part ID",
+                        "name": "part_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Unique names of the part",
+                        "schema": {
+                            "$ref": "#/definitions/bhl.NameList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid part ID",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Part not found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Database is unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
                     }
                 }
             }
@@ -231,12 +683,75 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/bhl.Reference"
                         }
+                    },
+                    "400": {
+                        "description": "Invalid page ID",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Page not found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Database is unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/resolve_ref": {
+            "post": {
+                "description": "Finds candidate BHL titles by abbreviations of the citation, selects their items by volume and year, and pages by page numbers. Candidates are ranked by year, volume, page and title scores, the best match goes first. Years, volume and pages are parsed from refString if they are not given.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Finds BHL pages for a bibliographic citation.",
+                "operationId": "post-resolve-ref",
+                "parameters": [
+                    {
+                        "description": "Reference with refString",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/input.Reference"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ranked references for the citation",
+                        "schema": {
+                            "$ref": "#/definitions/bhl.RefsByName"
+                        }
+                    },
+                    "400": {
+                        "description": "Malformed input or empty reference string",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Database is unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
                     }
                 }
             }
         },
         "/taxon_items/{taxon_name}": {
             "get": {
+                "description": "Without a rank returns items where the taxon contains more than 50% of names. With a rank returns items where the taxon is the most prevalent one at this rank.",
                 "consumes": [
                     "text/plain"
                 ],
@@ -253,6 +768,80 @@ const docTemplate = `{
                         "name": "taxon_name",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "kingdom",
+                            "phylum",
+                            "class",
+                            "order",
+                            "family",
+                            "genus"
+                        ],
+                        "type": "string",
+                        "description": "Rank of the taxon.",
+                        "name": "rank",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 70,
+                        "description": "Return items where the taxon has at least this percentage of names.",
+                        "name": "min_percent",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 100,
+                        "description": "Return items with at least this number of unique names.",
+                        "name": "min_names",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1890,
+                        "description": "Return items published in this year or later.",
+                        "name": "year_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1920,
+                        "description": "Return items published in this year or earlier.",
+                        "name": "year_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"v.12\"",
+                        "description": "Return items which volume contains this string not surrounded by digits.",
+                        "name": "volume",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "percent",
+                            "names",
+                            "year"
+                        ],
+                        "type": "string",
+                        "description": "Order of items, default is percent.",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 20,
+                        "description": "Maximum number of items.",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 40,
+                        "description": "Number of items to skip.",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -264,6 +853,140 @@ const docTemplate = `{
                                 "$ref": "#/definitions/bhl.Item"
                             }
                         }
+                    },
+                    "400": {
+                        "description": "Empty taxon name or invalid query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Database is unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/titles/{title_id}": {
+            "get": {
+                "description": "Returns metadata of a title (a journal or a book) and its taxonomic profile aggregated from statistics of the title's items: numbers of names per kingdom and the most frequent main taxa of items from phylum to genus.",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get metadata and taxonomic profile of a BHL title.",
+                "operationId": "get-title",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 7928,
+                        "description": "Title ID",
+                        "name": "title_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "BHL title metadata and taxonomic profile",
+                        "schema": {
+                            "$ref": "#/definitions/bhl.Title"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid title ID",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Title not found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Database is unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/titles/{title_id}/items": {
+            "get": {
+                "description": "Returns items of a title with their metadata and taxonomic statistics, sorted by year.",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get items (volumes) of a BHL title.",
+                "operationId": "get-title-items",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 7928,
+                        "description": "Title ID",
+                        "name": "title_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1890,
+                        "description": "Return items published in this year or later.",
+                        "name": "year_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1920,
+                        "description": "Return items published in this year or earlier.",
+                        "name": "year_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"v.12\"",
+                        "description": "Return items which volume contains this string not surrounded by digits.",
+                        "name": "volume",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "BHL items of the title",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/bhl.Item"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid title ID or query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Title not found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Database is unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
                     }
                 }
             }
@@ -285,9 +1008,103 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
+        }
+    },
+    "definitions": {
+        "bhl.Build": {
+            "description": "Build describes a full build, an update or an import of CoL data.",
+            "type": "object",
+            "properties": {
+                "durationSec": {
+                    "description": "Duration of the build in seconds.",
+                    "type": "integer",
+                    "example": 5400
+                },
+                "kind": {
+                    "description": "Kind of the build: \"init\", \"update\" or \"col\".",
+                    "type": "string",
+                    "example": "init"
+                },
+                "startedAt": {
+                    "description": "StartedAt is the time when the build started.",
+                    "type": "string",
+                    "example": "2024-12-02T10:00:00Z"
+                },
+                "tableRows": {
+                    "description": "TableRows are numbers of rows in database tables after the build.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "version": {
+                    "description": "Version of bhlnames that made the build.",
+                    "type": "string",
+                    "example": "v0.2.6"
+                }
+            }
+        },
+        "bhl.DataInfo": {
+            "description": "DataInfo describes the version of the data: datasets they were built from and builds that created or changed them. It allows to cite the exact data behind a result.",
+            "type": "object",
+            "properties": {
+                "builds": {
+                    "description": "Builds are the latest builds, updates and CoL imports, the newest\nbuild goes first.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/bhl.Build"
+                    }
+                },
+                "sources": {
+                    "description": "Sources are datasets the data were built from.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/bhl.DataSource"
+                    }
+                },
+                "version": {
+                    "description": "Version of bhlnames that serves the data.",
+                    "type": "string",
+                    "example": "v0.2.6"
+                }
+            }
+        },
+        "bhl.DataSource": {
+            "description": "DataSource describes a dataset the data were built from.",
+            "type": "object",
+            "properties": {
+                "dumpDate": {
+                    "description": "DumpDate is the modification time of the newest file in the archive.",
+                    "type": "string",
+                    "example": "2024-11-30T10:00:00Z"
+                },
+                "fileSize": {
+                    "description": "FileSize is the size of the downloaded archive in bytes.",
+                    "type": "integer",
+                    "example": 2147483648
+                },
+                "importedAt": {
+                    "description": "ImportedAt is the time when the dataset was imported.",
+                    "type": "string",
+                    "example": "2024-12-02T12:00:00Z"
+                },
+                "name": {
+                    "description": "Name of the dataset: \"bhl\" for the BHL dump, \"bhlindex\" for names\nfound in BHL, \"col\" for the Catalogue of Life data.",
+                    "type": "string",
+                    "example": "bhl"
+                },
+                "sha256": {
+                    "description": "SHA256 is the checksum of the downloaded archive.",
+                    "type": "string",
+                    "example": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+                },
+                "url": {
+                    "description": "URL is the source of the dataset.",
+                    "type": "string",
+                    "example": "http://opendata.globalnames.org/bhlnames/bhl-data.zip"
+                }
+            }
+        },
         "bhl.Item": {
             "description": "Item represents a BHL item, usually a journal volume of a journal or a book. It includes metadata about the item and statistics about the taxonomic groups mentioned in the item.",
             "type": "object",
@@ -397,6 +1214,13 @@ const docTemplate = `{
                     "type": "string",
                     "example": "phylum"
                 },
+                "taxa": {
+                    "description": "Taxa is the taxonomic distribution of the names of the Item from\nkingdom to genus. It is sorted by rank, and then by the number of\nnames. It is only provided by ItemStats methods.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/bhl.ItemTaxon"
+                    }
+                },
                 "titleId": {
                     "description": "TitleID is the BHL database ID for the Title (book or journal).",
                     "type": "integer",
@@ -528,90 +1352,450 @@ const docTemplate = `{
                     "type": "string",
                     "example": "Arthropoda"
                 },
-                "mainTaxonPercent": {
-                    "description": "MainTaxonPercent indicates the percentage of names that belong\nto the main taxon.",
+                "mainTaxonPercent": {
+                    "description": "MainTaxonPercent indicates the percentage of names that belong\nto the main taxon.",
+                    "type": "integer",
+                    "example": 45
+                },
+                "mainTaxonRank": {
+                    "description": "MainTaxonRank is the rank of the main taxon.",
+                    "type": "string",
+                    "example": "phylum"
+                },
+                "taxa": {
+                    "description": "Taxa is the taxonomic distribution of the names of the Item from\nkingdom to genus. It is sorted by rank, and then by the number of\nnames. It is only provided by ItemStats methods.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/bhl.ItemTaxon"
+                    }
+                },
+                "uniqNamesNum": {
+                    "description": "UniqNamesNum is the number of unique names in the Item.",
+                    "type": "integer",
+                    "example": 1234
+                }
+            }
+        },
+        "bhl.ItemTaxon": {
+            "description": "ItemTaxon shows how many names of an Item belong to a taxon.",
+            "type": "object",
+            "properties": {
+                "namesNum": {
+                    "description": "NamesNum is the number of names of the Item that belong to the taxon.",
+                    "type": "integer",
+                    "example": 120
+                },
+                "percent": {
+                    "description": "Percent is the percentage of these names among names used for\nstatistics of the Item.",
+                    "type": "integer",
+                    "example": 14
+                },
+                "rank": {
+                    "description": "Rank is the rank of the taxon.",
+                    "type": "string",
+                    "example": "order"
+                },
+                "taxon": {
+                    "description": "Taxon is the name of the taxon.",
+                    "type": "string",
+                    "example": "Coleoptera"
+                }
+            }
+        },
+        "bhl.Meta": {
+            "description": "Meta provides metadata for the results of a name-string search.",
+            "type": "object",
+            "properties": {
+                "canonical": {
+                    "description": "Canonical is a full canonical form of the input name-string.",
+                    "type": "string"
+                },
+                "currentCanonical": {
+                    "description": "CurrentCanonical is a full canonical form of a currently accepted\nname for the taxon of the input name-string.",
+                    "type": "string"
+                },
+                "error": {
+                    "description": "Error is a message of an error that happened during the search.",
+                    "type": "string",
+                    "example": "database is unavailable"
+                },
+                "input": {
+                    "description": "Input of a name and/or reference",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/input.Input"
+                        }
+                    ]
+                },
+                "inputReferenceFrom": {
+                    "description": "InputReferenceFrom indicates that input references were taked from\na data source.",
+                    "type": "string"
+                },
+                "nextOffset": {
+                    "description": "NextOffset is the offset of the next page of references. It is\nempty if there are no more references.",
+                    "type": "integer",
+                    "example": 20
+                },
+                "nomenEventFromCache": {
+                    "description": "NomenEventFromCache indicates that nomenclatural event was taken from\na pre-cached data.",
+                    "type": "boolean"
+                },
+                "nomenEventLive": {
+                    "description": "NomenEventLive indicates that the name was not found in the\npre-cached data and its nomenclatural event was calculated\nfrom occurrences of the name and its variants.",
+                    "type": "boolean"
+                },
+                "sortOrder": {
+                    "description": "SortOrder is the order of references in the results.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/input.SortOrder"
+                        }
+                    ],
+                    "example": "year"
+                },
+                "synonyms": {
+                    "description": "Synonyms is a list of synonyms for the name-string.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "totalRefsNum": {
+                    "description": "ReferenceNumber is the number of references found for the name-string.",
+                    "type": "integer"
+                }
+            }
+        },
+        "bhl.NameData": {
+            "description": "NameData contains details about a scientific name in the BHL reference.",
+            "type": "object",
+            "properties": {
+                "annotNomen": {
+                    "description": "AnnotNomen is a nomenclatural annotation located near the matchted name.",
+                    "type": "string",
+                    "example": "sp. nov."
+                },
+                "editDistance": {
+                    "description": "EditDistance is the number of differences (edit events)\nbetween Name and MatchName according to Levenshtein algorithm.",
+                    "type": "integer",
+                    "example": 0
+                },
+                "matchName": {
+                    "description": "MatchedName is a scientific name match from the reference's text.",
+                    "type": "string",
+                    "example": "Pardosa moesta Banks, 1892"
+                },
+                "name": {
+                    "description": "Name is a scientific name from the query.",
+                    "type": "string",
+                    "example": "Pardosa moesta"
+                },
+                "nameMatch": {
+                    "description": "NameMatch is the kind of match between the name from the query and\nMatchedName: ` + "`" + `exact` + "`" + `, ` + "`" + `stem` + "`" + ` or ` + "`" + `fuzzy` + "`" + `.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/input.NameMatch"
+                        }
+                    ],
+                    "example": "exact"
+                },
+                "queryEditDistance": {
+                    "description": "QueryEditDistance is the number of edits between the name from the\nquery and MatchedName for the ` + "`" + `fuzzy` + "`" + ` match.",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "bhl.NameList": {
+            "description": "NameList provides unique scientific names detected in a BHL item or part.",
+            "type": "object",
+            "properties": {
+                "itemId": {
+                    "description": "ItemID is the BHL database ID for the Item, it is empty for a part.",
+                    "type": "integer",
+                    "example": 73397
+                },
+                "kingdoms": {
+                    "description": "Kingdoms is the number of unique names for each kingdom, according\nto the classification of the names.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "names": {
+                    "description": "Names are the unique names in the order of their first appearance.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/bhl.NameStats"
+                    }
+                },
+                "namesNum": {
+                    "description": "NamesNum is the number of unique names.",
+                    "type": "integer",
+                    "example": 120
+                },
+                "occurrencesNum": {
+                    "description": "OccurrencesNum is the number of all occurrences of the names.",
+                    "type": "integer",
+                    "example": 431
+                },
+                "partId": {
+                    "description": "PartID is the BHL database ID for the Part, it is empty for an item.",
+                    "type": "integer",
+                    "example": 39371
+                }
+            }
+        },
+        "bhl.NameStats": {
+            "description": "NameStats provides data about a unique name detected in a BHL item or part.",
+            "type": "object",
+            "properties": {
+                "annotNomen": {
+                    "description": "AnnotNomen are nomenclatural annotations found near the name\noccurrences, for example ` + "`" + `SP_NOV` + "`" + `.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "SP_NOV"
+                    ]
+                },
+                "classification": {
+                    "description": "Classification is the pipe-delimited classification of the taxon.",
+                    "type": "string",
+                    "example": "Animalia|Arthropoda|Arachnida|Araneae|Lycosidae|Pardosa|Pardosa moesta"
+                },
+                "classificationIds": {
+                    "description": "ClassificationIDs are the pipe-delimited IDs of the classification\nin the data source.",
+                    "type": "string",
+                    "example": "N|CH2|6Z4|9JQ|623|64N|6V7VB"
+                },
+                "classificationRanks": {
+                    "description": "ClassificationRanks are the pipe-delimited ranks of the\nclassification.",
+                    "type": "string",
+                    "example": "kingdom|phylum|class|order|family|genus|species"
+                },
+                "currentCanonical": {
+                    "description": "CurrentCanonical is the canonical form of the currently accepted\nname.",
+                    "type": "string",
+                    "example": "Pardosa moesta"
+                },
+                "dataSourceId": {
+                    "description": "DataSourceID is the ID of the data source of the match.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "firstPageId": {
+                    "description": "FirstPageID is the BHL ID of the first page where the name was found.",
+                    "type": "integer",
+                    "example": 6589171
+                },
+                "lastPageId": {
+                    "description": "LastPageID is the BHL ID of the last page where the name was found.",
+                    "type": "integer",
+                    "example": 6589175
+                },
+                "matchType": {
+                    "description": "MatchType is the type of the match of the name to the Catalogue\nof Life, for example ` + "`" + `Exact` + "`" + ` or ` + "`" + `Fuzzy` + "`" + `.",
+                    "type": "string",
+                    "example": "Exact"
+                },
+                "matchedCanonical": {
+                    "description": "MatchedCanonical is the canonical form of the matched name.",
+                    "type": "string",
+                    "example": "Pardosa moesta"
+                },
+                "name": {
+                    "description": "Name is the name-string as it was detected in the text.",
+                    "type": "string",
+                    "example": "Pardosa moesta Banks, 1892"
+                },
+                "occurrencesNum": {
+                    "description": "OccurrencesNum is the number of times the name was detected.",
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "bhl.PageName": {
+            "description": "PageName is an occurrence of a scientific name on a BHL page.",
+            "type": "object",
+            "properties": {
+                "annotNomen": {
+                    "description": "AnnotNomen is a nomenclatural annotation located near the name.",
+                    "type": "string",
+                    "example": "SP_NOV"
+                },
+                "classification": {
+                    "description": "Classification is the pipe-delimited classification of the taxon.",
+                    "type": "string",
+                    "example": "Animalia|Arthropoda|Arachnida|Araneae|Lycosidae|Pardosa|Pardosa moesta"
+                },
+                "classificationIds": {
+                    "description": "ClassificationIDs are the pipe-delimited IDs of the classification\nin the data source.",
+                    "type": "string",
+                    "example": "N|CH2|6Z4|9JQ|623|64N|6V7VB"
+                },
+                "classificationRanks": {
+                    "description": "ClassificationRanks are the pipe-delimited ranks of the\nclassification.",
+                    "type": "string",
+                    "example": "kingdom|phylum|class|order|family|genus|species"
+                },
+                "currentCanonical": {
+                    "description": "CurrentCanonical is the canonical form of the current name.",
+                    "type": "string",
+                    "example": "Pardosa moesta"
+                },
+                "currentName": {
+                    "description": "CurrentName is the currently accepted name for the taxon of the\nmatched name.",
+                    "type": "string",
+                    "example": "Pardosa moesta Banks, 1892"
+                },
+                "dataSourceId": {
+                    "description": "DataSourceID is the ID of the data source of the match.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "dataSourceTitle": {
+                    "description": "DataSourceTitle is the title of the data source of the match.",
+                    "type": "string",
+                    "example": "Catalogue of Life"
+                },
+                "editDistance": {
+                    "description": "EditDistance is the number of differences between the name and the\nmatched name for fuzzy matches.",
+                    "type": "integer",
+                    "example": 0
+                },
+                "matchType": {
+                    "description": "MatchType is the type of the match of the name to the Catalogue\nof Life, for example ` + "`" + `Exact` + "`" + ` or ` + "`" + `Fuzzy` + "`" + `.",
+                    "type": "string",
+                    "example": "Exact"
+                },
+                "matchedCanonical": {
+                    "description": "MatchedCanonical is the canonical form of the matched name.",
+                    "type": "string",
+                    "example": "Pardosa moesta"
+                },
+                "matchedName": {
+                    "description": "MatchedName is the name from the Catalogue of Life that matched the\ndetected name.",
+                    "type": "string",
+                    "example": "Pardosa moesta Banks, 1892"
+                },
+                "name": {
+                    "description": "Name is the name-string as it was detected in the text.",
+                    "type": "string",
+                    "example": "Pardosa moesta Banks, 1892"
+                },
+                "oddsLog10": {
+                    "description": "OddsLog10 is a logarithm with base 10 of odds that the detected\nstring is a scientific name.",
+                    "type": "number",
+                    "example": 5.3
+                },
+                "offsetEnd": {
+                    "description": "OffsetEnd is the ending position of the name in the page text.\nIt is calculated using UTF-8 characters.",
+                    "type": "integer",
+                    "example": 1050
+                },
+                "offsetStart": {
+                    "description": "OffsetStart is the starting position of the name in the page text.\nIt is calculated using UTF-8 characters.",
+                    "type": "integer",
+                    "example": 1024
+                }
+            }
+        },
+        "bhl.PageNames": {
+            "description": "PageNames provides all scientific names detected on a BHL page.",
+            "type": "object",
+            "properties": {
+                "itemId": {
+                    "description": "ItemID is the BHL database ID for the Item of the page.",
+                    "type": "integer",
+                    "example": 12345
+                },
+                "names": {
+                    "description": "Names are the name occurrences on the page, sorted by their offsets.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/bhl.PageName"
+                    }
+                },
+                "namesNum": {
+                    "description": "NamesNum is the number of name occurrences on the page.",
+                    "type": "integer",
+                    "example": 12
+                },
+                "pageId": {
+                    "description": "PageID is the BHL database ID for the page.",
+                    "type": "integer",
+                    "example": 12345
+                },
+                "pageNum": {
+                    "description": "PageNum is the page number provided by the hard copy of the publication.",
                     "type": "integer",
-                    "example": 45
+                    "example": 123
                 },
-                "mainTaxonRank": {
-                    "description": "MainTaxonRank is the rank of the main taxon.",
+                "url": {
+                    "description": "URL is the URL of the page in BHL.",
                     "type": "string",
-                    "example": "phylum"
-                },
-                "uniqNamesNum": {
-                    "description": "UniqNamesNum is the number of unique names in the Item.",
-                    "type": "integer",
-                    "example": 1234
+                    "example": "https://www.biodiversitylibrary.org/page/12345"
                 }
             }
         },
-        "bhl.Meta": {
-            "description": "Meta provides metadata for the results of a name-string search.",
+        "bhl.ParsedRef": {
+            "description": "ParsedRef shows data extracted from a reference string and BHL titles that match the reference. It helps to find out why a reference did or did not resolve.",
             "type": "object",
             "properties": {
-                "canonical": {
-                    "description": "Canonical is a full canonical form of the input name-string.",
-                    "type": "string"
-                },
-                "currentCanonical": {
-                    "description": "CurrentCanonical is a full canonical form of a currently accepted\nname for the taxon of the input name-string.",
-                    "type": "string"
-                },
-                "error": {
-                    "description": "Error in results"
+                "abbr": {
+                    "description": "Abbr is the abbreviation of the reference string made of the first\nletters of its words.",
+                    "type": "string",
+                    "example": "pdmmn"
                 },
-                "input": {
-                    "description": "Input of a name and/or reference",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/input.Input"
-                        }
+                "abbrMatches": {
+                    "description": "AbbrMatches are abbreviations of BHL titles found inside Abbr.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "dmm"
                     ]
                 },
-                "inputReferenceFrom": {
-                    "description": "InputReferenceFrom indicates that input references were taked from\na data source.",
-                    "type": "string"
+                "pageEnd": {
+                    "description": "PageEnd is the last page found in the reference.",
+                    "type": "integer",
+                    "example": 51
                 },
-                "nomenEventFromCache": {
-                    "description": "NomenEventFromCache indicates that nomenclatural event was taken from\na pre-cached data.",
-                    "type": "boolean"
+                "pageStart": {
+                    "description": "PageStart is the first page found in the reference.",
+                    "type": "integer",
+                    "example": 50
                 },
-                "synonyms": {
-                    "description": "Synonyms is a list of synonyms for the name-string.",
+                "refString": {
+                    "description": "RefString is the reference string as it was given.",
+                    "type": "string",
+                    "example": "Docums Mycol. 34(nos 135-136):50-51. (2008)."
+                },
+                "titles": {
+                    "description": "Titles are BHL titles that have abbreviations from AbbrMatches.",
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/bhl.TitleMatch"
                     }
                 },
-                "totalRefsNum": {
-                    "description": "ReferenceNumber is the number of references found for the name-string.",
-                    "type": "integer"
-                }
-            }
-        },
-        "bhl.NameData": {
-            "description": "NameData contains details about a scientific name in the BHL reference.",
-            "type": "object",
-            "properties": {
-                "annotNomen": {
-                    "description": "AnnotNomen is a nomenclatural annotation located near the matchted name.",
-                    "type": "string",
-                    "example": "sp. nov."
-                },
-                "editDistance": {
-                    "description": "EditDistance is the number of differences (edit events)\nbetween Name and MatchName according to Levenshtein algorithm.",
+                "volume": {
+                    "description": "Volume is the volume found in the reference.",
                     "type": "integer",
-                    "example": 0
+                    "example": 34
                 },
-                "matchName": {
-                    "description": "MatchedName is a scientific name match from the reference's text.",
-                    "type": "string",
-                    "example": "Pardosa moesta Banks, 1892"
+                "yearEnd": {
+                    "description": "YearEnd is the last year of a range of years found in the reference.",
+                    "type": "integer",
+                    "example": 2009
                 },
-                "name": {
-                    "description": "Name is a scientific name from the query.",
-                    "type": "string",
-                    "example": "Pardosa moesta"
+                "yearStart": {
+                    "description": "YearStart is the year of publication found in the reference.",
+                    "type": "integer",
+                    "example": 2008
                 }
             }
         },
@@ -849,6 +2033,186 @@ const docTemplate = `{
                 }
             }
         },
+        "bhl.TaxonItems": {
+            "description": "TaxonItems shows how many items of a title have the taxon as the most prevalent one at its rank.",
+            "type": "object",
+            "properties": {
+                "itemsNum": {
+                    "description": "ItemsNum is the number of items where the taxon is the most\nprevalent at its rank.",
+                    "type": "integer",
+                    "example": 42
+                },
+                "itemsPercent": {
+                    "description": "ItemsPercent is the percentage of such items among all items of\nthe title.",
+                    "type": "integer",
+                    "example": 35
+                },
+                "rank": {
+                    "description": "Rank is the rank of the taxon.",
+                    "type": "string",
+                    "example": "order"
+                },
+                "taxon": {
+                    "description": "Taxon is the name of the taxon.",
+                    "type": "string",
+                    "example": "Coleoptera"
+                }
+            }
+        },
+        "bhl.Title": {
+            "description": "Title represents a BHL title, usually a journal or a book, with the aggregated taxonomic profile of its items.",
+            "type": "object",
+            "properties": {
+                "doiTitle": {
+                    "description": "TitleDOI provides DOI for the title.",
+                    "type": "string",
+                    "example": "10.1234/5678"
+                },
+                "itemsNum": {
+                    "description": "ItemsNum is the number of items (volumes) of the title in BHL.",
+                    "type": "integer",
+                    "example": 120
+                },
+                "itemsYearEnd": {
+                    "description": "ItemsYearEnd is the latest year of the title's items.",
+                    "type": "integer",
+                    "example": 1921
+                },
+                "itemsYearStart": {
+                    "description": "ItemsYearStart is the earliest year of the title's items.",
+                    "type": "integer",
+                    "example": 1881
+                },
+                "language": {
+                    "description": "Language is the language of the title.",
+                    "type": "string",
+                    "example": "English"
+                },
+                "profile": {
+                    "description": "Profile is the taxonomic profile of the title aggregated from the\nstatistics of its items.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/bhl.TitleProfile"
+                        }
+                    ]
+                },
+                "titleId": {
+                    "description": "TitleID is the BHL database ID for the Title.",
+                    "type": "integer",
+                    "example": 7928
+                },
+                "titleName": {
+                    "description": "TitleName is the name of the title.",
+                    "type": "string",
+                    "example": "Bulletin of the American Museum of Natural History"
+                },
+                "titleYearEnd": {
+                    "description": "TitleYearEnd is the year when the journal ceased publication.",
+                    "type": "integer",
+                    "example": 1922
+                },
+                "titleYearStart": {
+                    "description": "TitleYearStart is the year the when book is published, or\na journal started publication.",
+                    "type": "integer",
+                    "example": 1881
+                }
+            }
+        },
+        "bhl.TitleMatch": {
+            "description": "TitleMatch is a BHL title matched to a reference string.",
+            "type": "object",
+            "properties": {
+                "abbrs": {
+                    "description": "Abbrs are the abbreviations of the title that matched the reference.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "dm"
+                    ]
+                },
+                "titleId": {
+                    "description": "TitleID is the BHL database ID of the title.",
+                    "type": "integer",
+                    "example": 7928
+                },
+                "titleName": {
+                    "description": "TitleName is the name of the title.",
+                    "type": "string",
+                    "example": "Documents mycologiques"
+                }
+            }
+        },
+        "bhl.TitleProfile": {
+            "description": "TitleProfile aggregates taxonomic statistics of the items of a title.",
+            "type": "object",
+            "properties": {
+                "kingdomAnimaliaNum": {
+                    "description": "AnimaliaNum is the number of names that belong to the Animalia kingdom.",
+                    "type": "integer",
+                    "example": 1234
+                },
+                "kingdomBacteriaNum": {
+                    "description": "BacteriaNum is the number of names that belong to the Bacteria kingdom.",
+                    "type": "integer",
+                    "example": 1234
+                },
+                "kingdomFungiNum": {
+                    "description": "FungiNum is the number of names that belong to the Fungi kingdom.",
+                    "type": "integer",
+                    "example": 1234
+                },
+                "kingdomPlantaeNum": {
+                    "description": "PlantaeNum is the number of names that belong to the Plantae kingdom.",
+                    "type": "integer",
+                    "example": 1234
+                },
+                "mainKingdom": {
+                    "description": "MainKingdom is the most prevalent kingdom in the title.",
+                    "type": "string",
+                    "example": "Animalia"
+                },
+                "mainKingdomPercent": {
+                    "description": "MainKingdomPercent is the percentage of names of the main kingdom\namong names of the four kingdoms.",
+                    "type": "integer",
+                    "example": 87
+                },
+                "mainTaxa": {
+                    "description": "MainTaxa are the most frequent main taxa of the items for each rank\nfrom phylum to genus.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/bhl.TaxonItems"
+                    }
+                },
+                "namesNum": {
+                    "description": "NamesNum is the sum of numbers of unique names of the items.",
+                    "type": "integer",
+                    "example": 23456
+                }
+            }
+        },
+        "cache.Stats": {
+            "description": "Stats provides usage statistics of the results cache.",
+            "type": "object",
+            "properties": {
+                "hits": {
+                    "description": "Hits is the number of requests answered from the cache.",
+                    "type": "integer",
+                    "example": 1024
+                },
+                "misses": {
+                    "description": "Misses is the number of requests that were not found in the cache.",
+                    "type": "integer",
+                    "example": 256
+                },
+                "type": {
+                    "description": "Type is the type of the cache (memory or disk).",
+                    "type": "string",
+                    "example": "memory"
+                }
+            }
+        },
         "gnvers.Version": {
             "description": "Version provides information about the version of an application.",
             "type": "object",
@@ -865,6 +2229,47 @@ const docTemplate = `{
                 }
             }
         },
+        "input.Filter": {
+            "description": "Filter contains criteria that limit found references. Empty fields are ignored.",
+            "type": "object",
+            "properties": {
+                "annotNomen": {
+                    "description": "AnnotNomen keeps only references with a given nomenclatural\nannotation (for example ` + "`" + `SP_NOV` + "`" + `). The ` + "`" + `ANY` + "`" + ` value keeps references\nwith any annotation.",
+                    "type": "string",
+                    "example": "SP_NOV"
+                },
+                "itemId": {
+                    "description": "ItemID keeps only references from a BHL item (usually a volume).",
+                    "type": "integer",
+                    "example": 32506
+                },
+                "mainKingdom": {
+                    "description": "MainKingdom keeps only references from items where the given\nkingdom is the most prevalent one.",
+                    "type": "string",
+                    "example": "Animalia"
+                },
+                "minRefMatchQuality": {
+                    "description": "MinRefMatchQuality removes references with RefMatchQuality lower\nthan the given value. It is used only when references are scored,\nthat is for nomenclatural events or if a reference is provided,\nother inputs with this filter are rejected.",
+                    "type": "integer",
+                    "example": 3
+                },
+                "titleId": {
+                    "description": "TitleID keeps only references from a BHL title (book or journal).",
+                    "type": "integer",
+                    "example": 7928
+                },
+                "yearFrom": {
+                    "description": "YearFrom removes references published before this year.",
+                    "type": "integer",
+                    "example": 1890
+                },
+                "yearTo": {
+                    "description": "YearTo removes references published after this year.",
+                    "type": "integer",
+                    "example": 1920
+                }
+            }
+        },
         "input.Input": {
             "description": "Input is used to pass data to the BHLnames API. It contains infromation about a name and a reference where the name was mentioned. Reference can point to a name usage or a nomenclatural event.",
             "type": "object",
@@ -926,15 +2331,55 @@ const docTemplate = `{
                 }
             }
         },
+        "input.NameMatch": {
+            "type": "string",
+            "enum": [
+                "exact",
+                "stem",
+                "fuzzy"
+            ],
+            "x-enum-varnames": [
+                "MatchExact",
+                "MatchStem",
+                "MatchFuzzy"
+            ]
+        },
         "input.Params": {
             "description": "Params contain options used in the search and output.",
             "type": "object",
             "properties": {
+                "filter": {
+                    "description": "Filter contains criteria that limit found references.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/input.Filter"
+                        }
+                    ]
+                },
+                "maxEditDistance": {
+                    "description": "MaxEditDistance is the maximum number of edits for the ` + "`" + `fuzzy` + "`" + ` name\nmatch. The default is 1, the maximum is 3.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "nameMatch": {
+                    "description": "NameMatch determines how the name is compared to names in BHL:\n` + "`" + `exact` + "`" + ` (default), ` + "`" + `stem` + "`" + ` or ` + "`" + `fuzzy` + "`" + `. It is ignored for taxon\nsearches.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/input.NameMatch"
+                        }
+                    ],
+                    "example": "stem"
+                },
                 "nomenEvent": {
                     "description": "WithNomenEvent is true when the result tries to get a nomenclatural event\nfor the name.",
                     "type": "boolean",
                     "example": false
                 },
+                "offset": {
+                    "description": "Offset is the number of references to skip. Together with RefsLimit\nit allows to get results page by page.",
+                    "type": "integer",
+                    "example": 20
+                },
                 "refsLimit": {
                     "description": "RefsLimit provides the maximum number of references to return for each\nname.",
                     "type": "integer",
@@ -950,6 +2395,15 @@ const docTemplate = `{
                     "type": "boolean",
                     "example": false
                 },
+                "sortBy": {
+                    "description": "SortBy sets the order of references: ` + "`" + `year` + "`" + `, ` + "`" + `year_desc` + "`" + `, ` + "`" + `odds` + "`" + `,\n` + "`" + `title` + "`" + ` or ` + "`" + `page` + "`" + `. If it is empty, references are sorted by odds\nwhen they are scored, otherwise by year according to SortDesc.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/input.SortOrder"
+                        }
+                    ],
+                    "example": "year"
+                },
                 "sortDesc": {
                     "description": "SortDesc determines the order of sorting the output data. If ` + "`" + `true` + "`" + `\ndata are sorted by year from latest to earliest. If ` + "`" + `false` + "`" + ` then from\nearliest to latest.",
                     "type": "boolean",
@@ -1008,11 +2462,54 @@ const docTemplate = `{
                 }
             }
         },
+        "input.SortOrder": {
+            "type": "string",
+            "enum": [
+                "year",
+                "year_desc",
+                "odds",
+                "title",
+                "page"
+            ],
+            "x-enum-varnames": [
+                "SortYear",
+                "SortYearDesc",
+                "SortOdds",
+                "SortTitle",
+                "SortPage"
+            ]
+        },
         "output.OddsDetails": {
             "type": "object",
             "additionalProperties": {
                 "type": "number"
             }
+        },
+        "rest.ErrorResponse": {
+            "description": "ErrorResponse is returned by the API when a request fails.",
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code is a machine-readable code of the error, derived from the HTTP\nstatus.",
+                    "type": "string",
+                    "example": "not_found"
+                },
+                "message": {
+                    "description": "Message describes the error.",
+                    "type": "string",
+                    "example": "page 123 is not found"
+                },
+                "requestId": {
+                    "description": "RequestID is the ID of the request, it helps to find the request in\nthe server logs.",
+                    "type": "string",
+                    "example": "Hq3Xcc1bwsRZQVVvDnJmkgbgjEkZaaYL"
+                },
+                "status": {
+                    "description": "Status is the HTTP status code of the response.",
+                    "type": "integer",
+                    "example": 404
+                }
+            }
         }
     },
     "externalDocs": {
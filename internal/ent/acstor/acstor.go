@@ -2,5 +2,9 @@ package acstor
 
 type AhoCorasickStore interface {
 	Setup() error
+
+	// Abbrs returns abbreviations of titles with IDs of titles that
+	// contain them.
+	Abbrs() (map[string][]int, error)
 	Get(abbr string) ([]int, error)
 }
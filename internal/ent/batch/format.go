@@ -0,0 +1,35 @@
+package batch
+
+// Format is the format of a batch input.
+type Format int
+
+const (
+	// FormatNone is used when the format is not detected yet.
+	FormatNone Format = iota
+
+	// CSV is comma-separated (or other delimiter) values with a header.
+	CSV
+
+	// TSV is tab-separated values with a header.
+	TSV
+
+	// JSONL is JSON Lines format, where every line is a JSON-encoded
+	// input.Input.
+	JSONL
+
+	// Text is a plain text file with one name-string per line.
+	Text
+)
+
+var formatMap = map[Format]string{
+	FormatNone: "",
+	CSV:        "CSV",
+	TSV:        "TSV",
+	JSONL:      "JSONL",
+	Text:       "Text",
+}
+
+// String returns a string representation of the format.
+func (f Format) String() string {
+	return formatMap[f]
+}
@@ -0,0 +1,20 @@
+// package batch provides interface to methods for reading collections of
+// names and references from files or streams.
+package batch
+
+import (
+	"context"
+
+	"github.com/gnames/bhlnames/pkg/ent/input"
+)
+
+// Reader reads name/reference data from CSV, TSV, JSON Lines or plain text
+// sources and converts them into inputs for BHLnames.
+type Reader interface {
+	// Format returns the format detected for the source.
+	Format() Format
+
+	// Read sends inputs to the channel in the same order they appear in the
+	// source. It does not close the channel.
+	Read(ctx context.Context, chIn chan<- input.Input) error
+}
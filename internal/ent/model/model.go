@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"gorm.io/gorm"
@@ -159,6 +160,30 @@ type ItemStats struct {
 	MainGenusPercent uint
 }
 
+// ItemTaxon is the number of names of an item that belong to a taxon.
+// Together these records provide the full taxonomic distribution of the
+// item from kingdom to genus, while ItemStats only keeps the most
+// prevalent taxa.
+type ItemTaxon struct {
+	// ItemID is the Item identifier autogenerated by BHL database.
+	ItemID uint `gorm:"index:item_taxon_item;not null"`
+
+	// Rank is the rank of the taxon (kingdom, phylum, class, order, family
+	// or genus).
+	Rank string `gorm:"type:varchar(20);not null"`
+
+	// Taxon is the name of the taxon.
+	Taxon string `gorm:"type:varchar(100);index:item_taxon_taxon;not null"`
+
+	// NamesNum is the number of names in the item (rank genus and lower)
+	// that belong to the taxon.
+	NamesNum uint `gorm:"not null"`
+
+	// Percent is the percentage of the names of the taxon among all names
+	// used for the item statistics.
+	Percent uint `gorm:"not null"`
+}
+
 // Page contains metadata about a page file from BHL archive.
 type Page struct {
 	// ID is the identifier autogenerated by BHL database.
@@ -190,7 +215,7 @@ type Part struct {
 
 	// ItemID is an automatically generated identifier for an item. It comes
 	// from BHL database.
-	ItemID sql.NullInt32
+	ItemID sql.NullInt32 `gorm:"index:part_item"`
 
 	// Length is the length of a part in pages.
 	Length sql.NullInt32
@@ -305,6 +330,10 @@ type NameString struct {
 	// MatchedCanonical provides canonical form of the matched name-string.
 	MatchedCanonical string `gorm:"type:varchar(255);index:canonical;not null"`
 
+	// MatchedCanonicalStem is the stemmed version of MatchedCanonical. It
+	// allows to find names with different suffixes of epithets.
+	MatchedCanonicalStem string `gorm:"type:varchar(255);index:matched_canonical_stem"`
+
 	// CurrentName is the full currently accepted name of the match
 	// provided by the DataSource.
 	CurrentName string `gorm:"type:varchar(255)"`
@@ -365,7 +394,7 @@ type NameOccurrence struct {
 
 	// PageID corresponds to ID field in Page. It is a number automatically
 	// generated by BHL database.
-	PageID uint
+	PageID uint `gorm:"index:page"`
 
 	// OffsetStart is the starting position of a detected name on the page.
 	// It is calculated using UTF-8 characters.
@@ -384,6 +413,33 @@ type NameOccurrence struct {
 	AnnotNomen string `gorm:"type:varchar(50);index:annot"`
 }
 
+// ItemHash contains hashes of records of an item in BHL and bhlindex
+// dumps. An update compares them with hashes of the new dumps to find
+// changed items, so only the changed items are imported again.
+type ItemHash struct {
+	// ID is the ID of the item.
+	ID uint `gorm:"primary_key;auto_increment:false"`
+
+	// Item is a hash of the item row with data of its title.
+	Item int64 `gorm:"not null"`
+
+	// Pages is a hash of all pages of the item.
+	Pages int64 `gorm:"not null"`
+
+	// Occurrences is a hash of all imported names' occurrences of the item.
+	Occurrences int64 `gorm:"not null"`
+}
+
+// PartHash contains a hash of a part record in BHL dump. An update
+// compares it with a hash from the new dump to find changed parts.
+type PartHash struct {
+	// ID is the ID of the part.
+	ID uint `gorm:"primary_key;auto_increment:false"`
+
+	// Hash is a hash of the part row with its DOI.
+	Hash int64 `gorm:"not null"`
+}
+
 // Abbr contains all abbreviations strings generated from titles.
 type Abbr struct {
 	Abbr string `gorm:"type:varchar(10);primary_key"`
@@ -481,15 +537,67 @@ type ColBhlResult struct {
 	Result []byte
 }
 
+// DataSource describes a dataset the data were built from.
+type DataSource struct {
+	// Name of the dataset: "bhl", "bhlindex" or "col".
+	Name string `gorm:"type:varchar(20);primary_key"`
+
+	// URL is the source of the dataset.
+	URL string `gorm:"not null;default:''"`
+
+	// FileSize is the size of the downloaded archive in bytes.
+	FileSize int64
+
+	// SHA256 is the checksum of the downloaded archive.
+	SHA256 string `gorm:"column:sha256;type:varchar(64);not null;default:''"`
+
+	// DumpDate is the modification time of the newest file in the archive.
+	DumpDate time.Time
+
+	// ImportedAt is the time when the dataset was imported.
+	ImportedAt time.Time
+}
+
+// Build records a full build, an update or an import of CoL data.
+type Build struct {
+	// ID is automatically generated.
+	ID uint `gorm:"primary_key"`
+
+	// Kind of the build: "init", "update" or "col".
+	Kind string `gorm:"type:varchar(20);not null"`
+
+	// Version of bhlnames that made the build.
+	Version string `gorm:"type:varchar(50);not null;default:''"`
+
+	// StartedAt is the time when the build started.
+	StartedAt time.Time
+
+	// Duration of the build in seconds.
+	Duration int
+
+	// TableRows contains serialized numbers of rows in tables after the
+	// build.
+	TableRows []byte
+}
+
 func Migrate(grm *gorm.DB) error {
-	err := grm.AutoMigrate(
+	// fuzzystrmatch provides edit distance functions for fuzzy name search.
+	err := grm.Exec("CREATE EXTENSION IF NOT EXISTS fuzzystrmatch").Error
+	if err != nil {
+		return err
+	}
+
+	err = grm.AutoMigrate(
 		&Item{},
 		&ItemStats{},
+		&ItemTaxon{},
 		&Page{},
 		&Part{},
 		&PagePart{},
 		&NameString{},
 		&NameOccurrence{},
+		&ItemHash{},
+		&PartHash{},
 		&Abbr{},
 		&AbbrTitle{},
 		&ColName{},
@@ -500,7 +608,13 @@ func Migrate(grm *gorm.DB) error {
 		return err
 	}
 
-	return nil
+	return MigrateInfo(grm)
+}
+
+// MigrateInfo creates tables that describe sources and builds of the
+// data.
+func MigrateInfo(grm *gorm.DB) error {
+	return grm.AutoMigrate(&DataSource{}, &Build{})
 }
 
 func SetCollation(db *pgxpool.Pool) error {
@@ -513,6 +627,7 @@ func SetCollation(db *pgxpool.Pool) error {
 		{"name_strings", "name", 255},
 		{"name_strings", "matched_name", 255},
 		{"name_strings", "current_name", 255},
+		{"name_strings", "matched_canonical_stem", 255},
 		{"col_names", "name", 500},
 	}
 	qStr := `
@@ -9,6 +9,13 @@ type NameBHL interface {
 	// ImportNames imports unique verified names from BHLindex.
 	ImportNames() (*bloom.BloomFilter, error)
 
-	// ImportOccurrences imports occurrences of names found by BHLindex.
-	ImportOccurrences(*bloom.BloomFilter) error
+	// ImportOccurrences imports occurrences of names found by BHLindex
+	// for items accepted by keep, or for all items if keep is nil. It
+	// returns hashes of occurrences of every item by item IDs, and the
+	// number of ignored occurrences of imported items, their names are not
+	// among imported names.
+	ImportOccurrences(
+		blf *bloom.BloomFilter,
+		keep func(itemID int) bool,
+	) (map[int]uint64, int, error)
 }
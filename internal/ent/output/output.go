@@ -0,0 +1,92 @@
+// package output provides rendering of BHLnames results into
+// JSON, CSV and TSV formats.
+package output
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
+	"github.com/gnames/gnfmt"
+)
+
+var header = []string{
+	"Id", "NameString", "Canonical", "MatchedName", "PageId", "PageNum",
+	"ItemId", "PartId", "PartDoi", "Year", "TitleId", "TitleName", "Volume",
+	"RefMatchQuality", "Odds", "Url",
+}
+
+// CSVHeader returns the header line for CSV and TSV formats.
+func CSVHeader(f gnfmt.Format) string {
+	return gnfmt.ToCSV(header, sep(f))
+}
+
+// Output renders results of a name-reference search according to the
+// format. JSON formats return the whole result. CSV and TSV formats return
+// one line per reference, or one line with input data only, if no
+// references were found.
+func Output(res *bhl.RefsByName, f gnfmt.Format) string {
+	switch f {
+	case gnfmt.CSV, gnfmt.TSV:
+		return csvOutput(res, sep(f))
+	case gnfmt.PrettyJSON:
+		return gnfmt.GNjson{Pretty: true}.Output(res, f)
+	default:
+		return gnfmt.GNjson{}.Output(res, gnfmt.CompactJSON)
+	}
+}
+
+func sep(f gnfmt.Format) rune {
+	if f == gnfmt.TSV {
+		return '\t'
+	}
+	return ','
+}
+
+func csvOutput(res *bhl.RefsByName, sep rune) string {
+	inp := res.Input
+	canonical := res.Canonical
+	if canonical == "" {
+		canonical = inp.CanonicalSimple
+	}
+
+	if len(res.References) == 0 {
+		row := make([]string, len(header))
+		row[0] = inp.ID
+		row[1] = inp.NameString
+		row[2] = canonical
+		return gnfmt.ToCSV(row, sep)
+	}
+
+	rows := make([]string, len(res.References))
+	for i, v := range res.References {
+		var matchedName, partID, partDOI, odds string
+		if v.NameData != nil {
+			matchedName = v.MatchedName
+		}
+		if v.Part != nil && v.Part.ID > 0 {
+			partID = strconv.Itoa(v.Part.ID)
+			partDOI = v.Part.DOI
+		}
+		if v.Score != nil {
+			odds = strconv.FormatFloat(v.Odds, 'g', 6, 64)
+		}
+
+		row := []string{
+			inp.ID, inp.NameString, canonical, matchedName,
+			strconv.Itoa(v.PageID), intStr(v.PageNum), strconv.Itoa(v.ItemID),
+			partID, partDOI, intStr(v.YearAggr), strconv.Itoa(v.TitleID),
+			v.TitleName, v.Volume, strconv.Itoa(v.RefMatchQuality), odds, v.URL,
+		}
+		rows[i] = gnfmt.ToCSV(row, sep)
+	}
+	return strings.Join(rows, "\n")
+}
+
+// intStr converts int to string, returning empty string for 0.
+func intStr(i int) string {
+	if i == 0 {
+		return ""
+	}
+	return strconv.Itoa(i)
+}
@@ -0,0 +1,68 @@
+package output_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gnames/bhlnames/internal/ent/output"
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/input"
+	"github.com/gnames/gnfmt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutput(t *testing.T) {
+	assert := assert.New(t)
+	res := &bhl.RefsByName{
+		Meta: bhl.Meta{
+			Input: input.Input{
+				ID:   "1",
+				Name: input.Name{NameString: "Pardosa moesta Banks, 1892"},
+			},
+			Canonical: "Pardosa moesta",
+		},
+		References: []*bhl.ReferenceName{
+			{
+				NameData: &bhl.NameData{MatchedName: "Pardosa moesta"},
+				Reference: bhl.Reference{
+					PageID:    1,
+					ItemID:    2,
+					YearAggr:  1892,
+					TitleID:   3,
+					TitleName: "Proceedings, Academy",
+					Volume:    "v.5",
+					Part:      &bhl.Part{ID: 4, DOI: "10.1234/5678"},
+				},
+				RefMatchQuality: 4,
+				Score:           &bhl.Score{Odds: 1.5},
+			},
+			{
+				Reference: bhl.Reference{PageID: 5, ItemID: 6, TitleID: 7},
+			},
+		},
+	}
+
+	hdr := output.CSVHeader(gnfmt.CSV)
+	assert.True(strings.HasPrefix(hdr, "Id,NameString,Canonical,"))
+	assert.Equal(16, len(strings.Split(hdr, ",")))
+
+	rows := strings.Split(output.Output(res, gnfmt.CSV), "\n")
+	assert.Equal(2, len(rows))
+	assert.Equal(`1,"Pardosa moesta Banks, 1892",Pardosa moesta,Pardosa moesta,`+
+		`1,,2,4,10.1234/5678,1892,3,"Proceedings, Academy",v.5,4,1.5,`, rows[0])
+	assert.Equal(`1,"Pardosa moesta Banks, 1892",Pardosa moesta,,`+
+		`5,,6,,,,7,,,0,,`, rows[1])
+
+	rows = strings.Split(output.Output(res, gnfmt.TSV), "\n")
+	assert.Equal(16, len(strings.Split(rows[0], "\t")))
+
+	res.References = nil
+	row := output.Output(res, gnfmt.TSV)
+	assert.Equal("1\tPardosa moesta Banks, 1892\tPardosa moesta"+
+		strings.Repeat("\t", 13), row)
+
+	json := output.Output(res, gnfmt.CompactJSON)
+	assert.True(strings.HasPrefix(json, `{"meta":`))
+	assert.NotContains(json, "\n")
+	assert.Contains(output.Output(res, gnfmt.PrettyJSON), "\n")
+}
@@ -1,7 +0,0 @@
-package rest
-
-// REST interface provides methods to run API server of BHLnames.
-type REST interface {
-	// Run starts the API server.
-	Run()
-}
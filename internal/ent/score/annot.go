@@ -1,7 +1,7 @@
 package score
 
 import (
-	"github.com/gnames/bhlnames/internal/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
 	"github.com/gnames/gnparser"
 )
 
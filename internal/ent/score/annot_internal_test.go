@@ -3,7 +3,7 @@ package score
 import (
 	"testing"
 
-	"github.com/gnames/bhlnames/internal/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -1,11 +1,12 @@
 package score
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/gnames/bayes"
-	"github.com/gnames/bhlnames/internal/ent/bhl"
-	"github.com/gnames/bhlnames/internal/ent/ttlmch"
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/ttlmch"
 )
 
 // Score interface provides methods to calculate scores that is used to
@@ -14,5 +15,11 @@ type Score interface {
 	fmt.Stringer
 	// Calculate calculates scores for a given set using heuristic and
 	// machine learning methods.
-	Calculate(*bhl.RefsByName, ttlmch.TitleMatcher, bayes.Bayes, bool) error
+	Calculate(
+		context.Context,
+		*bhl.RefsByName,
+		ttlmch.TitleMatcher,
+		bayes.Bayes,
+		bool,
+	) error
 }
@@ -4,7 +4,7 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/gnames/bhlnames/internal/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
 )
 
 func getPageScore(pageStart, pageEnd int, ref *bhl.ReferenceName) (int, string) {
@@ -14,7 +14,13 @@ func getPageScore(pageStart, pageEnd int, ref *bhl.ReferenceName) (int, string)
 		return pagesLabel(score)
 	}
 
-	if ref.PageNum == 0 && ref.Pages == "" {
+	// pages outside of parts have no part data
+	var pages string
+	if ref.Part != nil {
+		pages = ref.Pages
+	}
+
+	if ref.PageNum == 0 && pages == "" {
 		return pagesLabel(score)
 	}
 
@@ -26,8 +32,8 @@ func getPageScore(pageStart, pageEnd int, ref *bhl.ReferenceName) (int, string)
 		}
 	}
 
-	if ref.Pages != "" {
-		partPages := strings.Split(ref.Pages, "-")
+	if pages != "" {
+		partPages := strings.Split(pages, "-")
 		var partPageStart, partPageEnd int
 		partPageStart, _ = strconv.Atoi(partPages[0])
 
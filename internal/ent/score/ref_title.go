@@ -1,6 +1,6 @@
 package score
 
-import "github.com/gnames/bhlnames/internal/ent/bhl"
+import "github.com/gnames/bhlnames/pkg/ent/bhl"
 
 func getRefTitleScore(
 	titleIDs map[int][]string,
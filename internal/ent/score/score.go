@@ -1,6 +1,7 @@
 package score
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 
@@ -8,9 +9,9 @@ import (
 	ft "github.com/gnames/bayes/ent/feature"
 	bout "github.com/gnames/bayes/ent/output"
 	"github.com/gnames/bayes/ent/posterior"
-	"github.com/gnames/bhlnames/internal/ent/bhl"
-	"github.com/gnames/bhlnames/internal/ent/input"
-	"github.com/gnames/bhlnames/internal/ent/ttlmch"
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/input"
+	"github.com/gnames/bhlnames/pkg/ent/ttlmch"
 )
 
 type score struct {
@@ -26,6 +27,7 @@ func New(prec map[ScoreType]int) Score {
 }
 
 func (s *score) Calculate(
+	ctx context.Context,
 	nr *bhl.RefsByName,
 	tm ttlmch.TitleMatcher,
 	nb bayes.Bayes,
@@ -40,8 +42,9 @@ func (s *score) Calculate(
 		refString = nr.Input.RefString
 	}
 	var titleIDs map[int][]string
-	if refString != "" {
-		titleIDs, err = tm.TitlesBHL(refString)
+	// TitleMatcher is optional, without it titles are not scored.
+	if refString != "" && tm != nil {
+		titleIDs, err = tm.TitlesBHL(ctx, refString)
 		if err != nil {
 			return err
 		}
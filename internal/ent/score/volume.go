@@ -4,7 +4,7 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/gnames/bhlnames/internal/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
 )
 
 func getVolumeScore(volume int, ref *bhl.ReferenceName) (int, string) {
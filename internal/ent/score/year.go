@@ -4,7 +4,7 @@ import (
 	"math"
 	"time"
 
-	"github.com/gnames/bhlnames/internal/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
 )
 
 var maxYearScore int = 3
@@ -0,0 +1,98 @@
+package txstats
+
+import (
+	"math"
+	"slices"
+
+	gnstats "github.com/gnames/gnstats/ent/stats"
+)
+
+// DistRanks are the ranks of the taxonomic distribution of an item, from
+// the highest to the lowest.
+var DistRanks = []gnstats.Rank{
+	gnstats.Kingdom, gnstats.Phylum, gnstats.Class,
+	gnstats.Order, gnstats.Family, gnstats.Genus,
+}
+
+// TaxonDist is the number and the percentage of names of an item that
+// belong to a taxon.
+type TaxonDist struct {
+	// Rank is the rank of the taxon.
+	Rank string
+
+	// Taxon is the name of the taxon.
+	Taxon string
+
+	// NamesNum is the number of names that belong to the taxon.
+	NamesNum int
+
+	// Percent is the rounded percentage of the names among all names used
+	// for statistics.
+	Percent int
+}
+
+// Distribution counts names for every taxon of DistRanks. Like gnstats,
+// it only uses names of genus rank or lower, so percentages are compatible
+// with the main taxa of the item. Results are sorted by rank, then by the
+// number of names in descending order.
+func Distribution(hs []gnstats.Hierarchy) []TaxonDist {
+	var namesNum int
+	counts := make(map[gnstats.Rank]map[string]int)
+	for _, h := range hs {
+		taxons := h.Taxons()
+		if !genusOrLess(taxons) {
+			continue
+		}
+		namesNum++
+		for _, v := range taxons {
+			if !slices.Contains(DistRanks, v.Rank) || v.Name == "" {
+				continue
+			}
+			if counts[v.Rank] == nil {
+				counts[v.Rank] = make(map[string]int)
+			}
+			counts[v.Rank][v.Name]++
+		}
+	}
+
+	var res []TaxonDist
+	for _, rank := range DistRanks {
+		var dist []TaxonDist
+		for k, v := range counts[rank] {
+			dist = append(dist, TaxonDist{
+				Rank:     rank.String(),
+				Taxon:    k,
+				NamesNum: v,
+				Percent:  int(math.Round(float64(v) * 100 / float64(namesNum))),
+			})
+		}
+		slices.SortFunc(dist, func(a, b TaxonDist) int {
+			if a.NamesNum != b.NamesNum {
+				return b.NamesNum - a.NamesNum
+			}
+			if a.Taxon < b.Taxon {
+				return -1
+			}
+			if a.Taxon > b.Taxon {
+				return 1
+			}
+			return 0
+		})
+		res = append(res, dist...)
+	}
+	return res
+}
+
+// genusOrLess returns true if the classification reaches genus or a lower
+// rank.
+func genusOrLess(taxons []gnstats.Taxon) bool {
+	for i := range taxons {
+		if taxons[i].Rank == gnstats.Empty {
+			taxons[i].Rank = gnstats.NewRank(taxons[i].RankStr)
+		}
+		if taxons[i].Rank != gnstats.Unknown && taxons[i].Rank <= gnstats.Genus {
+			return true
+		}
+	}
+	return false
+}
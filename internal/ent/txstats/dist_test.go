@@ -0,0 +1,65 @@
+package txstats_test
+
+import (
+	"testing"
+
+	"github.com/gnames/bhlnames/internal/ent/txstats"
+	gnstats "github.com/gnames/gnstats/ent/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistribution(t *testing.T) {
+	assert := assert.New(t)
+	ranks := "kingdom|phylum|class|order|family|genus|species"
+	ids := "1|2|3|4|5|6|7"
+	hs := []gnstats.Hierarchy{
+		txstats.TxStats{
+			Classification: "Animalia|Arthropoda|Insecta|Coleoptera|Carabidae|Carabus|Carabus nemoralis",
+			Ranks:          ranks, IDs: ids,
+		},
+		txstats.TxStats{
+			Classification: "Animalia|Arthropoda|Insecta|Coleoptera|Buprestidae|Agrilus|Agrilus viridis",
+			Ranks:          ranks, IDs: ids,
+		},
+		txstats.TxStats{
+			Classification: "Animalia|Arthropoda|Insecta|Lepidoptera|Pieridae|Pieris|Pieris rapae",
+			Ranks:          ranks, IDs: ids,
+		},
+		txstats.TxStats{
+			Classification: "Plantae|Tracheophyta|Magnoliopsida|Rosales|Rosaceae|Rosa|Rosa canina",
+			Ranks:          ranks, IDs: ids,
+		},
+		// higher taxa are not used for statistics
+		txstats.TxStats{
+			Classification: "Animalia|Chordata",
+			Ranks:          "kingdom|phylum", IDs: "1|2",
+		},
+	}
+
+	res := txstats.Distribution(hs)
+	assert.Equal(2+2+2+3+4+4, len(res))
+	assert.Equal(
+		txstats.TaxonDist{Rank: "kingdom", Taxon: "Animalia", NamesNum: 3, Percent: 75},
+		res[0],
+	)
+	assert.Equal(
+		txstats.TaxonDist{Rank: "kingdom", Taxon: "Plantae", NamesNum: 1, Percent: 25},
+		res[1],
+	)
+
+	var coleoptera txstats.TaxonDist
+	for _, v := range res {
+		if v.Taxon == "Coleoptera" {
+			coleoptera = v
+		}
+	}
+	assert.Equal("order", coleoptera.Rank)
+	assert.Equal(2, coleoptera.NamesNum)
+	assert.Equal(50, coleoptera.Percent)
+
+	last := res[len(res)-1]
+	assert.Equal("genus", last.Rank)
+	assert.Equal("Rosa", last.Taxon)
+
+	assert.Nil(txstats.Distribution(nil))
+}
@@ -47,25 +47,31 @@ func New(
 // Setup prepares the AhoCorasickStore for use.
 func (a *acstorio) Setup() error {
 	slog.Info("Setting up AhoCorasickStore.")
-	// abbrMap maps abbreviation strings to the list of
-	// title IDs that contain the abbreviation
-	abbrMap := make(map[string][]int)
-
-	if a.titles == nil {
-		err := errors.New("titles data is nil")
+	abbrMap, err := a.Abbrs()
+	if err != nil {
 		slog.Error("Cannot setup AhoCorasickStore.", "error", err)
 		return err
 	}
+	return a.save(abbrMap)
+}
+
+// Abbrs maps abbreviation strings to the list of title IDs that contain
+// the abbreviation.
+func (a *acstorio) Abbrs() (map[string][]int, error) {
+	if a.titles == nil {
+		return nil, errors.New("titles data is nil")
+	}
 
+	res := make(map[string][]int)
 	for k, v := range a.titles {
 		abbrs := abbr.Patterns(v.Name, a.shortWords)
 		for i := range abbrs {
 			if len(abbrs[i]) > 2 {
-				abbrMap[abbrs[i]] = append(abbrMap[abbrs[i]], k)
+				res[abbrs[i]] = append(res[abbrs[i]], k)
 			}
 		}
 	}
-	return a.save(abbrMap)
+	return res, nil
 }
 
 // Get returns a list of title IDs that contain the key.
@@ -0,0 +1,279 @@
+package batchio
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/gnames/bhlnames/internal/ent/batch"
+	"github.com/gnames/bhlnames/pkg/ent/input"
+	"github.com/gnames/gnfmt"
+	"github.com/gnames/gnparser"
+)
+
+const (
+	// maxLineSize is the maximum size of a line in JSON Lines, TSV and
+	// plain text sources. Some references are very long.
+	maxLineSize = 1_000_000
+
+	// peekSize is the size of the beginning of the source used for the
+	// format detection.
+	peekSize = 64 * 1024
+)
+
+type batchio struct {
+	// r is a buffered reader of the source.
+	r *bufio.Reader
+
+	// format is the detected format of the source.
+	format batch.Format
+
+	// sep is the delimiter used for CSV sources.
+	sep rune
+
+	// gnpPool is a pool of gnparser instances used to create inputs.
+	gnpPool chan gnparser.GNparser
+
+	// opts are input options shared by all inputs (search parameters).
+	opts []input.Option
+
+	// enc decodes JSON Lines.
+	enc gnfmt.Encoder
+}
+
+// New creates a new batch Reader. It detects the format of the source
+// by its content. The sep is used as a delimiter for CSV sources, opts
+// are applied to every input before the data from the source.
+func New(
+	r io.Reader,
+	sep rune,
+	gnpPool chan gnparser.GNparser,
+	opts []input.Option,
+) (batch.Reader, error) {
+	res := batchio{
+		r:       bufio.NewReaderSize(r, peekSize),
+		sep:     sep,
+		gnpPool: gnpPool,
+		opts:    opts,
+		enc:     gnfmt.GNjson{},
+	}
+
+	var err error
+	res.format, err = res.detectFormat()
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("Detected batch input format", "format", res.format.String())
+	return &res, nil
+}
+
+// Format returns the format detected for the source.
+func (b *batchio) Format() batch.Format {
+	return b.format
+}
+
+// Read sends inputs to the channel in the same order they appear in the
+// source.
+func (b *batchio) Read(ctx context.Context, chIn chan<- input.Input) error {
+	switch b.format {
+	case batch.CSV:
+		return b.readCSV(ctx, chIn)
+	case batch.TSV:
+		return b.readTSV(ctx, chIn)
+	case batch.JSONL:
+		return b.readJSONL(ctx, chIn)
+	case batch.Text:
+		return b.readText(ctx, chIn)
+	default:
+		return errors.New("unknown batch input format")
+	}
+}
+
+// detectFormat peeks at the first non-empty line of the source to decide
+// what format it has.
+func (b *batchio) detectFormat() (batch.Format, error) {
+	bs, err := b.r.Peek(peekSize)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return batch.FormatNone, err
+	}
+	line := strings.TrimLeft(string(bs), " \t\r\n\ufeff")
+	if idx := strings.IndexByte(line, '\n'); idx > -1 {
+		line = line[:idx]
+	}
+	line = strings.TrimRight(line, "\r")
+
+	switch {
+	case line == "":
+		return batch.FormatNone, errors.New("batch input is empty")
+	case strings.HasPrefix(line, "{"):
+		return batch.JSONL, nil
+	case strings.Contains(line, "\t"):
+		return batch.TSV, nil
+	case strings.ContainsRune(line, b.sep):
+		return batch.CSV, nil
+	case fieldOf(line) == nameF, fieldOf(line) == canonicalF:
+		// a single-column CSV file with a header
+		return batch.CSV, nil
+	default:
+		return batch.Text, nil
+	}
+}
+
+func (b *batchio) readCSV(ctx context.Context, chIn chan<- input.Input) error {
+	r := csv.NewReader(b.r)
+	r.Comma = b.sep
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+
+	header, err := r.Read()
+	if err != nil {
+		slog.Error("Cannot read header of CSV input", "error", err)
+		return err
+	}
+	fields, err := headerFields(header)
+	if err != nil {
+		return err
+	}
+
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			slog.Error("Cannot read CSV row", "error", err)
+			return err
+		}
+		err = b.send(ctx, chIn, b.rowInput(fields, row))
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (b *batchio) readTSV(ctx context.Context, chIn chan<- input.Input) error {
+	scan := b.scanner()
+	if !scan.Scan() {
+		return scan.Err()
+	}
+	header := strings.Split(strings.TrimRight(scan.Text(), "\r"), "\t")
+	fields, err := headerFields(header)
+	if err != nil {
+		return err
+	}
+
+	for scan.Scan() {
+		l := strings.TrimRight(scan.Text(), "\r")
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		row := strings.Split(l, "\t")
+		err = b.send(ctx, chIn, b.rowInput(fields, row))
+		if err != nil {
+			return err
+		}
+	}
+	return scan.Err()
+}
+
+func (b *batchio) readJSONL(ctx context.Context, chIn chan<- input.Input) error {
+	scan := b.scanner()
+	var count int
+	for scan.Scan() {
+		count++
+		l := strings.TrimSpace(scan.Text())
+		if l == "" {
+			continue
+		}
+
+		var inp input.Input
+		err := b.enc.Decode([]byte(l), &inp)
+		if err != nil {
+			err = fmt.Errorf("batchio.readJSONL line %d: %w", count, err)
+			slog.Error("Cannot decode JSON line", "error", err)
+			return err
+		}
+		err = b.send(ctx, chIn, b.jsonInput(inp))
+		if err != nil {
+			return err
+		}
+	}
+	return scan.Err()
+}
+
+func (b *batchio) readText(ctx context.Context, chIn chan<- input.Input) error {
+	scan := b.scanner()
+	for scan.Scan() {
+		name := strings.TrimSpace(scan.Text())
+		if name == "" {
+			continue
+		}
+		opts := append(b.baseOpts(), input.OptNameString(name))
+		err := b.send(ctx, chIn, input.New(b.gnpPool, opts...))
+		if err != nil {
+			return err
+		}
+	}
+	return scan.Err()
+}
+
+func (b *batchio) scanner() *bufio.Scanner {
+	scan := bufio.NewScanner(b.r)
+	buf := make([]byte, 0, 64*1024)
+	scan.Buffer(buf, maxLineSize)
+	return scan
+}
+
+func (b *batchio) send(
+	ctx context.Context,
+	chIn chan<- input.Input,
+	inp input.Input,
+) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case chIn <- inp:
+		return nil
+	}
+}
+
+// baseOpts returns a copy of options shared by all inputs.
+func (b *batchio) baseOpts() []input.Option {
+	res := make([]input.Option, len(b.opts), len(b.opts)+8)
+	copy(res, b.opts)
+	return res
+}
+
+// jsonInput converts decoded JSON line to an input. Search parameters
+// are taken from the shared options, the name and reference data from
+// the line.
+func (b *batchio) jsonInput(inp input.Input) input.Input {
+	opts := b.baseOpts()
+	if inp.ID != "" {
+		opts = append(opts, input.OptID(inp.ID))
+	}
+	name := inp.NameString
+	if name == "" {
+		name = inp.CanonicalSimple
+	}
+	opts = append(opts, input.OptNameString(name))
+	if inp.NameYear > 0 {
+		opts = append(opts, input.OptNameYear(inp.NameYear))
+	}
+
+	if ref := inp.Reference; ref != nil {
+		opts = append(opts,
+			input.OptRefString(ref.RefString),
+			input.OptRefYearStart(ref.RefYearStart),
+			input.OptRefYearEnd(ref.RefYearEnd),
+			input.OptRefVolume(ref.Volume),
+			input.OptRefPages(ref.PageStart, ref.PageEnd),
+		)
+	}
+	return input.New(b.gnpPool, opts...)
+}
@@ -0,0 +1,148 @@
+package batchio_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gnames/bhlnames/internal/ent/batch"
+	"github.com/gnames/bhlnames/internal/io/batchio"
+	"github.com/gnames/bhlnames/pkg/ent/input"
+	"github.com/gnames/gnparser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRead(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		msg, data string
+		sep       rune
+		format    batch.Format
+		ids       []string
+		names     []string
+		vols      []int
+		pages     [][]int
+	}{
+		{
+			"csv",
+			"Id,NameString,RefString,Volume,Pages\n" +
+				`1,"Bubo bubo (Linnaeus, 1758)",,,` + "\n" +
+				`2,Achenium lusitanicum Skalitzky,"Wiener Ent. Zeit., 3 (4): 97-99. (1884).",,` + "\n" +
+				"3,Pardosa moesta,,12,188-189\n",
+			',', batch.CSV,
+			[]string{"1", "2", "3"},
+			[]string{"Bubo bubo", "Achenium lusitanicum", "Pardosa moesta"},
+			[]int{0, 3, 12},
+			[][]int{{0, 0}, {97, 99}, {188, 189}},
+		},
+		{
+			"csv semicolon",
+			"id;name_string\na;Bubo bubo\nb;Pardosa moesta\n",
+			';', batch.CSV,
+			[]string{"a", "b"},
+			[]string{"Bubo bubo", "Pardosa moesta"},
+			[]int{0, 0},
+			[][]int{{0, 0}, {0, 0}},
+		},
+		{
+			"tsv",
+			"id\tnameString\tyearStart\tpageStart\tpageEnd\n" +
+				"10\tBubo bubo\t1758\t24\t25\n",
+			',', batch.TSV,
+			[]string{"10"},
+			[]string{"Bubo bubo"},
+			[]int{0},
+			[][]int{{24, 25}},
+		},
+		{
+			"jsonl",
+			`{"id":"x1","name":{"nameString":"Bubo bubo"}}` + "\n\n" +
+				`{"id":"x2","name":{"nameString":"Pardosa moesta"},` +
+				`"reference":{"refString":"Docums Mycol. 34:50-51. (2008)."}}` + "\n",
+			',', batch.JSONL,
+			[]string{"x1", "x2"},
+			[]string{"Bubo bubo", "Pardosa moesta"},
+			[]int{0, 34},
+			[][]int{{0, 0}, {50, 51}},
+		},
+		{
+			"text",
+			"Bubo bubo\n\nPardosa moesta Banks, 1892\n",
+			',', batch.Text,
+			nil,
+			[]string{"Bubo bubo", "Pardosa moesta"},
+			[]int{0, 0},
+			[][]int{{0, 0}, {0, 0}},
+		},
+	}
+
+	gnpPool := gnparser.NewPool(gnparser.NewConfig(), 1)
+	for _, v := range tests {
+		rd, err := batchio.New(strings.NewReader(v.data), v.sep, gnpPool, nil)
+		assert.Nil(err, v.msg)
+		assert.Equal(v.format, rd.Format(), v.msg)
+
+		res := readAll(t, rd)
+		assert.Equal(len(v.names), len(res), v.msg)
+		for i := range res {
+			if v.ids != nil {
+				assert.Equal(v.ids[i], res[i].ID, v.msg)
+			} else {
+				assert.Equal(36, len(res[i].ID), v.msg)
+			}
+			assert.Equal(v.names[i], res[i].CanonicalSimple, v.msg)
+			if res[i].Reference == nil {
+				assert.Equal(0, v.vols[i], v.msg)
+				continue
+			}
+			assert.Equal(v.vols[i], res[i].Volume, v.msg)
+			assert.Equal(v.pages[i][0], res[i].PageStart, v.msg)
+			assert.Equal(v.pages[i][1], res[i].PageEnd, v.msg)
+		}
+	}
+}
+
+func TestReadOpts(t *testing.T) {
+	assert := assert.New(t)
+	gnpPool := gnparser.NewPool(gnparser.NewConfig(), 1)
+	opts := []input.Option{input.OptWithTaxon(true), input.OptRefsLimit(2)}
+	data := "nameString\nBubo bubo\n"
+
+	rd, err := batchio.New(strings.NewReader(data), ',', gnpPool, opts)
+	assert.Nil(err)
+	assert.Equal(batch.CSV, rd.Format())
+	res := readAll(t, rd)
+	assert.Equal(1, len(res))
+	assert.True(res[0].WithTaxon)
+	assert.Equal(2, res[0].RefsLimit)
+}
+
+func TestBadInput(t *testing.T) {
+	assert := assert.New(t)
+	gnpPool := gnparser.NewPool(gnparser.NewConfig(), 1)
+
+	_, err := batchio.New(strings.NewReader("  \n"), ',', gnpPool, nil)
+	assert.NotNil(err)
+
+	rd, err := batchio.New(strings.NewReader("id,ref\n1,abc\n"), ',', gnpPool, nil)
+	assert.Nil(err)
+	err = rd.Read(context.Background(), make(chan input.Input, 1))
+	assert.NotNil(err)
+}
+
+func readAll(t *testing.T, rd batch.Reader) []input.Input {
+	var res []input.Input
+	ch := make(chan input.Input)
+	done := make(chan struct{})
+	go func() {
+		for inp := range ch {
+			res = append(res, inp)
+		}
+		close(done)
+	}()
+	err := rd.Read(context.Background(), ch)
+	close(ch)
+	<-done
+	assert.Nil(t, err)
+	return res
+}
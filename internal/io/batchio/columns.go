@@ -0,0 +1,147 @@
+package batchio
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gnames/bhlnames/pkg/ent/input"
+)
+
+// field is an input field that can be provided by a CSV/TSV column.
+type field int
+
+const (
+	unknownF field = iota
+	idF
+	nameF
+	canonicalF
+	nameYearF
+	refF
+	yearStartF
+	yearEndF
+	volumeF
+	pagesF
+	pageStartF
+	pageEndF
+)
+
+// columnFields maps normalized column names to input fields.
+var columnFields = map[string]field{
+	"id":             idF,
+	"namestring":     nameF,
+	"name":           nameF,
+	"scientificname": nameF,
+	"namecanonical":  canonicalF,
+	"canonical":      canonicalF,
+	"nameyear":       nameYearF,
+	"refstring":      refF,
+	"ref":            refF,
+	"reference":      refF,
+	"yearstart":      yearStartF,
+	"refyearstart":   yearStartF,
+	"refyear":        yearStartF,
+	"year":           yearStartF,
+	"yearend":        yearEndF,
+	"refyearend":     yearEndF,
+	"volume":         volumeF,
+	"vol":            volumeF,
+	"pages":          pagesF,
+	"pagestart":      pageStartF,
+	"pageend":        pageEndF,
+}
+
+// fieldOf normalizes a column name and returns the corresponding field.
+func fieldOf(col string) field {
+	col = strings.TrimSpace(strings.TrimPrefix(col, "\ufeff"))
+	col = strings.Trim(col, `"`)
+	col = strings.ToLower(col)
+	col = strings.NewReplacer("_", "", "-", "", " ", "").Replace(col)
+	return columnFields[col]
+}
+
+// headerFields maps column indices to input fields. It returns an error
+// if there is no column with name-strings.
+func headerFields(header []string) (map[field]int, error) {
+	res := make(map[field]int)
+	for i, v := range header {
+		f := fieldOf(v)
+		if f == unknownF {
+			continue
+		}
+		// the first column wins if there are duplicates
+		if _, ok := res[f]; !ok {
+			res[f] = i
+		}
+	}
+	if _, ok := res[nameF]; !ok {
+		if idx, ok := res[canonicalF]; ok {
+			res[nameF] = idx
+			return res, nil
+		}
+		return nil, errors.New(
+			"batch input header must contain a name column (nameString)",
+		)
+	}
+	return res, nil
+}
+
+// rowInput creates an input from a CSV/TSV row.
+func (b *batchio) rowInput(fields map[field]int, row []string) input.Input {
+	val := func(f field) string {
+		idx, ok := fields[f]
+		if !ok || idx >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[idx])
+	}
+
+	opts := b.baseOpts()
+	if id := val(idF); id != "" {
+		opts = append(opts, input.OptID(id))
+	}
+	opts = append(opts, input.OptNameString(val(nameF)))
+	if yr := toInt(val(nameYearF)); yr > 0 {
+		opts = append(opts, input.OptNameYear(yr))
+	}
+
+	var refOpts []input.Option
+	if ref := val(refF); ref != "" {
+		refOpts = append(refOpts, input.OptRefString(ref))
+	}
+	if yr := toInt(val(yearStartF)); yr > 0 {
+		refOpts = append(refOpts, input.OptRefYearStart(yr))
+	}
+	if yr := toInt(val(yearEndF)); yr > 0 {
+		refOpts = append(refOpts, input.OptRefYearEnd(yr))
+	}
+	if vol := toInt(val(volumeF)); vol > 0 {
+		refOpts = append(refOpts, input.OptRefVolume(vol))
+	}
+	start, end := parsePages(val(pagesF))
+	if start == 0 {
+		start, end = toInt(val(pageStartF)), toInt(val(pageEndF))
+	}
+	if start > 0 {
+		refOpts = append(refOpts, input.OptRefPages(start, end))
+	}
+
+	opts = append(opts, refOpts...)
+	return input.New(b.gnpPool, opts...)
+}
+
+// parsePages converts a page range like "188-189" or a single page "188"
+// to the start and end pages.
+func parsePages(s string) (int, int) {
+	s = strings.NewReplacer("–", "-", "—", "-", "--", "-").Replace(s)
+	start, end, _ := strings.Cut(s, "-")
+	return toInt(start), toInt(end)
+}
+
+func toInt(s string) int {
+	res, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return 0
+	}
+	return res
+}
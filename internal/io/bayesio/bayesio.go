@@ -4,7 +4,7 @@ import (
 	_ "embed"
 
 	"github.com/gnames/bayes"
-	"github.com/gnames/bhlnames/internal/ent/nlp"
+	"github.com/gnames/bhlnames/pkg/ent/nlp"
 )
 
 //go:embed data/bayes.json
@@ -2,16 +2,26 @@ package bhlsys
 
 import (
 	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
+	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"code.cloudfoundry.org/bytefmt"
 	"github.com/gnames/gnsys"
 )
 
+// partExt is added to names of files that are not fully downloaded yet.
+const partExt = ".part"
+
 var files = map[string]struct{}{
 	// BHL data files
 	"Data/doi.txt":   {},
@@ -92,25 +102,268 @@ func unzip(path, dlDir string, rebuild bool) error {
 	return nil
 }
 
-// Download will Download a dump of BHL metadata using provided URL to a
-// local file. It's efficient because it will write as it downloads and not
-// load the whole file into memory. We pass an io.TeeReader into Copy() to
-// report progress on the Download.
-func Download(path, url string, rebuild bool) error {
+// Download gets a file from a source to a local path. The source can be an
+// HTTP(S) URL, a file:// URL or a path to a local file. HTTP downloads are
+// written to a partial file first, and an interrupted download is resumed
+// from where it stopped. If checksum is not empty, the file must have the
+// given SHA-256 checksum. The file must be a readable zip archive, so
+// corrupt or partial downloads are detected before extraction.
+//
+// If rebuild is false and a valid file already exists, the download is
+// skipped.
+func Download(path, url, checksum string, rebuild bool) error {
 	exists, _ := gnsys.FileExists(path)
-	if !rebuild && exists {
-		slog.Info("File already exists, skipping download.", "file", path)
-		return nil
+	if exists && !rebuild {
+		err := Verify(path, checksum)
+		if err == nil {
+			slog.Info("File already exists, skipping download.", "file", path)
+			return nil
+		}
+		slog.Warn(
+			"Existing file is invalid, downloading it again.",
+			"file", path, "error", err,
+		)
+	}
+	if exists {
+		err := os.Remove(path)
+		if err != nil {
+			return err
+		}
 	}
 
-	dir := filepath.Dir(path)
+	err := gnsys.MakeDir(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+
+	if src, ok := localSource(url); ok {
+		err = copyLocal(src, path)
+	} else {
+		err = downloadHTTP(path, url)
+	}
+	if err != nil {
+		return err
+	}
 
-	localPath, err := gnsys.Download(url, dir, true)
+	err = Verify(path, checksum)
 	if err != nil {
+		_ = os.Remove(path)
 		return err
 	}
-	os.Rename(localPath, path)
 
-	slog.Info("Download finished.")
+	slog.Info("Download finished.", "file", path)
 	return nil
 }
+
+// Verify checks that a file is a readable zip archive. If checksum is not
+// empty, it also checks that SHA-256 checksum of the file matches it.
+func Verify(path, checksum string) error {
+	if checksum != "" {
+		sum, err := fileSHA256(path)
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(sum, strings.TrimSpace(checksum)) {
+			return fmt.Errorf(
+				"checksum mismatch for %s: expected %s, got %s",
+				path, checksum, sum,
+			)
+		}
+	}
+
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Errorf("corrupt or incomplete archive %s: %w", path, err)
+	}
+	defer r.Close()
+	if len(r.File) == 0 {
+		return fmt.Errorf("archive %s is empty", path)
+	}
+	return nil
+}
+
+// localSource returns a path to a local file if the source is a file://
+// URL or does not have a scheme.
+func localSource(src string) (string, bool) {
+	if strings.HasPrefix(src, "file://") {
+		u, err := url.Parse(src)
+		if err != nil || u.Path == "" {
+			return strings.TrimPrefix(src, "file://"), true
+		}
+		return u.Path, true
+	}
+	if !strings.Contains(src, "://") {
+		return src, true
+	}
+	return "", false
+}
+
+// copyLocal makes a local source file available at path. It tries a hard
+// link first and copies the file if linking is not possible.
+func copyLocal(src, path string) error {
+	exists, _ := gnsys.FileExists(src)
+	if !exists {
+		return fmt.Errorf("cannot find local source %s", src)
+	}
+	slog.Info("Using local file.", "source", src, "file", path)
+	if err := os.Link(src, path); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	tmp := path + partExt
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if err != nil {
+		out.Close()
+		return err
+	}
+	err = out.Close()
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// downloadHTTP downloads a file to a partial file and moves it to the path
+// when the download is complete. If the partial file exists, the download
+// continues from its end. The modification time of the partial file keeps
+// the Last-Modified time of the remote file, so the download starts from
+// scratch if the remote file changed in the meantime.
+func downloadHTTP(path, url string) error {
+	tmp := path + partExt
+	var offset int64
+	var modTime time.Time
+	if fi, err := os.Stat(tmp); err == nil {
+		offset = fi.Size()
+		modTime = fi.ModTime()
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		req.Header.Set("If-Range", modTime.UTC().Format(http.TimeFormat))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	flag := os.O_WRONLY | os.O_CREATE
+	var total int64 = -1
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		slog.Info(
+			"Resuming download.",
+			"url", url, "bytes-done", bytefmt.ByteSize(uint64(offset)),
+		)
+		flag |= os.O_APPEND
+		if resp.ContentLength >= 0 {
+			total = offset + resp.ContentLength
+		}
+	case http.StatusOK:
+		// the server ignored the range, the download starts from scratch
+		offset = 0
+		flag |= os.O_TRUNC
+		total = resp.ContentLength
+	case http.StatusRequestedRangeNotSatisfiable:
+		// the partial file already has all the data
+		if offset > 0 {
+			return os.Rename(tmp, path)
+		}
+		fallthrough
+	default:
+		return fmt.Errorf("cannot download %s: %s", url, resp.Status)
+	}
+
+	out, err := os.OpenFile(tmp, flag, 0644)
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(out, resp.Body)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if lm, perr := http.ParseTime(resp.Header.Get("Last-Modified")); perr == nil {
+		_ = os.Chtimes(tmp, lm, lm)
+	}
+	if err != nil {
+		return fmt.Errorf("download of %s was interrupted: %w", url, err)
+	}
+
+	size := offset + n
+	if total >= 0 && size != total {
+		return fmt.Errorf(
+			"download of %s is incomplete: got %d of %d bytes", url, size, total,
+		)
+	}
+	return os.Rename(tmp, path)
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Archive describes a downloaded archive.
+type Archive struct {
+	// Size is the size of the archive in bytes.
+	Size int64
+
+	// SHA256 is the checksum of the archive.
+	SHA256 string
+
+	// DumpDate is the modification time of the newest file in the archive.
+	// It shows when the dump was created.
+	DumpDate time.Time
+}
+
+// Info returns the size, checksum and date of the dump of an archive.
+func Info(path string) (Archive, error) {
+	var res Archive
+	fi, err := os.Stat(path)
+	if err != nil {
+		return res, err
+	}
+	res.Size = fi.Size()
+
+	res.SHA256, err = fileSHA256(path)
+	if err != nil {
+		return res, err
+	}
+
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return res, err
+	}
+	defer r.Close()
+	for _, f := range r.File {
+		if f.Modified.After(res.DumpDate) {
+			res.DumpDate = f.Modified
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,170 @@
+package bhlsys_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gnames/bhlnames/internal/io/bhlsys"
+	"github.com/stretchr/testify/assert"
+)
+
+var modTime = time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+func zipData(t *testing.T) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	f, err := w.CreateHeader(&zip.FileHeader{
+		Name: "names.csv", Method: zip.Deflate, Modified: modTime,
+	})
+	assert.Nil(t, err)
+	_, err = f.Write(bytes.Repeat([]byte("Pardosa moesta\n"), 1000))
+	assert.Nil(t, err)
+	assert.Nil(t, w.Close())
+	return buf.Bytes()
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// server serves data and counts requests with Range header.
+func server(data []byte, ranges *int32) *httptest.Server {
+	return httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Range") != "" {
+				atomic.AddInt32(ranges, 1)
+			}
+			http.ServeContent(w, r, "names.zip", modTime, bytes.NewReader(data))
+		}),
+	)
+}
+
+func TestDownload(t *testing.T) {
+	assert := assert.New(t)
+	data := zipData(t)
+	var ranges int32
+	ts := server(data, &ranges)
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "names.zip")
+	err := bhlsys.Download(path, ts.URL, checksum(data), false)
+	assert.Nil(err)
+	res, err := os.ReadFile(path)
+	assert.Nil(err)
+	assert.Equal(data, res)
+	assert.Equal(int32(0), ranges)
+
+	_, err = os.Stat(path + ".part")
+	assert.True(os.IsNotExist(err))
+}
+
+func TestDownloadResume(t *testing.T) {
+	assert := assert.New(t)
+	data := zipData(t)
+	var ranges int32
+	ts := server(data, &ranges)
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "names.zip")
+	part := path + ".part"
+	err := os.WriteFile(part, data[:len(data)/2], 0644)
+	assert.Nil(err)
+	assert.Nil(os.Chtimes(part, modTime, modTime))
+
+	err = bhlsys.Download(path, ts.URL, checksum(data), true)
+	assert.Nil(err)
+	res, err := os.ReadFile(path)
+	assert.Nil(err)
+	assert.Equal(data, res)
+	assert.Equal(int32(1), ranges)
+
+	// partial file from a different version of the remote file
+	assert.Nil(os.WriteFile(part, []byte("garbage"), 0644))
+	err = bhlsys.Download(path, ts.URL, checksum(data), true)
+	assert.Nil(err)
+	res, err = os.ReadFile(path)
+	assert.Nil(err)
+	assert.Equal(data, res)
+}
+
+func TestDownloadInvalid(t *testing.T) {
+	assert := assert.New(t)
+	data := zipData(t)
+	var ranges int32
+	ts := server(data, &ranges)
+	defer ts.Close()
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "names.zip")
+	err := bhlsys.Download(path, ts.URL, checksum([]byte("other")), false)
+	assert.ErrorContains(err, "checksum mismatch")
+	_, err = os.Stat(path)
+	assert.True(os.IsNotExist(err))
+
+	// a truncated archive is not used and is downloaded again
+	assert.Nil(os.WriteFile(path, data[:len(data)-10], 0644))
+	assert.NotNil(bhlsys.Verify(path, ""))
+	err = bhlsys.Download(path, ts.URL, "", false)
+	assert.Nil(err)
+	assert.Nil(bhlsys.Verify(path, checksum(data)))
+
+	notZip := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("<html>Not Found</html>"))
+		}),
+	)
+	defer notZip.Close()
+	path = filepath.Join(dir, "bad.zip")
+	err = bhlsys.Download(path, notZip.URL, "", false)
+	assert.ErrorContains(err, "corrupt or incomplete archive")
+}
+
+func TestDownloadLocal(t *testing.T) {
+	assert := assert.New(t)
+	data := zipData(t)
+	dir := t.TempDir()
+	src := filepath.Join(dir, "staged.zip")
+	assert.Nil(os.WriteFile(src, data, 0644))
+
+	tests := []struct {
+		msg, url string
+	}{
+		{"file url", "file://" + src},
+		{"path", src},
+	}
+	for _, v := range tests {
+		path := filepath.Join(dir, "dl", "names.zip")
+		err := bhlsys.Download(path, v.url, checksum(data), true)
+		assert.Nil(err, v.msg)
+		res, err := os.ReadFile(path)
+		assert.Nil(err, v.msg)
+		assert.Equal(data, res, v.msg)
+	}
+
+	path := filepath.Join(dir, "missing.zip")
+	err := bhlsys.Download(path, filepath.Join(dir, "none.zip"), "", false)
+	assert.ErrorContains(err, "cannot find local source")
+}
+
+func TestInfo(t *testing.T) {
+	assert := assert.New(t)
+	data := zipData(t)
+	path := filepath.Join(t.TempDir(), "names.zip")
+	assert.Nil(os.WriteFile(path, data, 0644))
+
+	res, err := bhlsys.Info(path)
+	assert.Nil(err)
+	assert.Equal(int64(len(data)), res.Size)
+	assert.Equal(checksum(data), res.SHA256)
+	assert.True(modTime.Equal(res.DumpDate))
+}
@@ -1,20 +1,44 @@
 package builderio
 
 import (
+	"context"
+	"fmt"
 	"log/slog"
+	"slices"
+	"time"
 
 	"github.com/bits-and-blooms/bloom/v3"
-	"github.com/gnames/bhlnames/internal/ent/builder"
+	"github.com/gnames/bhlnames/internal/ent/model"
 	"github.com/gnames/bhlnames/internal/io/bhlsys"
 	"github.com/gnames/bhlnames/internal/io/dbio"
 	"github.com/gnames/bhlnames/internal/io/namesbhlio"
+	"github.com/gnames/bhlnames/internal/io/provio"
 	"github.com/gnames/bhlnames/pkg/config"
+	"github.com/gnames/bhlnames/pkg/ent/builder"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"gorm.io/gorm"
 )
 
 type builderio struct {
 	cfg config.Config
+
+	// schema is the staging schema where the new build is created.
+	schema string
+
+	// start is the time when the build started.
+	start time.Time
+
+	// sources describe downloaded datasets of the build.
+	sources []model.DataSource
+
+	// ignoredOccurs is the number of occurrences that were not imported,
+	// because their names were not imported.
+	ignoredOccurs int
+
+	// admin uses the default search path and manages schemas.
+	admin *pgxpool.Pool
+
+	// db and grm work with tables of the staging schema.
 	db  *pgxpool.Pool
 	grm *gorm.DB
 }
@@ -23,20 +47,27 @@ type builderio struct {
 // connections to the database.
 func New(cfg config.Config) (builder.Builder, error) {
 	var err error
-	var db *pgxpool.Pool
+	var admin, db *pgxpool.Pool
 	var grm *gorm.DB
 
-	res := builderio{cfg: cfg}
-	db, err = dbio.NewDB(cfg)
+	start := time.Now()
+	res := builderio{cfg: cfg, schema: stagingSchema(start), start: start}
+	admin, err = dbio.NewDB(cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	grm, err = dbio.NewGORM(cfg)
+	db, err = dbio.NewSchemaDB(cfg, res.schema)
 	if err != nil {
 		return nil, err
 	}
 
+	grm, err = dbio.NewSchemaGORM(cfg, res.schema)
+	if err != nil {
+		return nil, err
+	}
+
+	res.admin = admin
 	res.db = db
 	res.grm = grm
 	return &res, nil
@@ -51,7 +82,7 @@ func (b *builderio) ResetData() error {
 		return err
 	}
 
-	err = b.resetDB()
+	err = b.dropStaging(context.Background())
 	if err != nil {
 		return err
 	}
@@ -61,19 +92,20 @@ func (b *builderio) ResetData() error {
 
 func (b *builderio) ImportData() error {
 	// Download and Extract
-	err := b.downloadAndExtract()
+	err := b.downloadAndExtract(b.cfg.WithRebuild)
 	if err != nil {
 		return err
 	}
 
-	// Reset Database
-	err = b.resetDB()
+	// Create an empty build in the staging schema, the data used by readers
+	// stays untouched until PublishData.
+	err = b.prepareStaging()
 	if err != nil {
 		return err
 	}
 
 	// Import data coming from BHL dump
-	err = b.importDataBHL()
+	hs, err := b.importDataBHL()
 	if err != nil {
 		return err
 	}
@@ -89,40 +121,90 @@ func (b *builderio) ImportData() error {
 	}
 
 	// Import occurrences of name-strings
-	err = n.ImportOccurrences(blf)
+	hs.occurs, b.ignoredOccurs, err = n.ImportOccurrences(blf, nil)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	// Hashes of the dumps' records let an update find changed records
+	// without importing unchanged ones.
+	return b.saveHashes(hs, mapIDs(hs.items), mapIDs(hs.parts))
+}
+
+func (b *builderio) PublishData() error {
+	ctx := context.Background()
+	err := b.copyKeptData(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = b.validateStaging(ctx, validTables)
+	if err != nil {
+		return err
+	}
+
+	err = provio.SaveSources(ctx, b.db, b.sources...)
+	if err != nil {
+		return err
+	}
+
+	err = provio.SaveBuild(ctx, b.db, provio.KindInit, b.start)
+	if err != nil {
+		return err
+	}
+
+	return b.switchSchema(ctx, b.schema)
+}
+
+func (b *builderio) Rollback() error {
+	ctx := context.Background()
+	schemas, err := b.schemas(ctx)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(schemas, prevSchema) {
+		err = fmt.Errorf("there is no previous build in %s schema", prevSchema)
+		slog.Error("Cannot roll back.", "error", err)
+		return err
+	}
+
+	slog.Info("Rolling back to the previous build.")
+	return b.switchSchema(ctx, prevSchema)
 }
 
 func (b *builderio) Close() {
+	b.admin.Close()
 	b.db.Close()
 	db, _ := b.grm.DB()
 	db.Close()
 }
 
-func (b *builderio) downloadAndExtract() error {
+// downloadAndExtract gets BHL and bhlindex dumps. If rebuild is false,
+// files that are already downloaded or extracted are reused.
+func (b *builderio) downloadAndExtract(rebuild bool) error {
 	slog.Info(
 		"Downloading database dump from BHL.",
 		"url", b.cfg.BHLDumpURL,
 		"file", b.cfg.DownloadBHLFile,
 	)
 	err := bhlsys.Download(
-		b.cfg.DownloadBHLFile, b.cfg.BHLDumpURL, b.cfg.WithRebuild,
+		b.cfg.DownloadBHLFile, b.cfg.BHLDumpURL, b.cfg.BHLDumpSHA256, rebuild,
 	)
 	if err != nil {
 		slog.Error("Cannot download BHL data.", "error", err)
 		return err
 	}
+	err = b.addSource("bhl", b.cfg.BHLDumpURL, b.cfg.DownloadBHLFile)
+	if err != nil {
+		return err
+	}
 
 	slog.Info(
 		"Extracting BHL database dump data.",
 		"file", b.cfg.DownloadBHLFile,
 	)
 	err = bhlsys.Extract(
-		b.cfg.DownloadBHLFile, b.cfg.ExtractDir, b.cfg.WithRebuild,
+		b.cfg.DownloadBHLFile, b.cfg.ExtractDir, rebuild,
 	)
 	if err != nil {
 		slog.Error("Cannot extract BHL data.",
@@ -136,18 +218,24 @@ func (b *builderio) downloadAndExtract() error {
 		"url", b.cfg.BHLNamesURL,
 		"file", b.cfg.DownloadNamesFile,
 	)
-	err = bhlsys.Download(b.cfg.DownloadNamesFile, b.cfg.BHLNamesURL, b.cfg.WithRebuild)
+	err = bhlsys.Download(
+		b.cfg.DownloadNamesFile, b.cfg.BHLNamesURL, b.cfg.BHLNamesSHA256, rebuild,
+	)
 	if err != nil {
 		slog.Error("Cannot download names data.", "error", err)
 		return err
 	}
+	err = b.addSource("bhlindex", b.cfg.BHLNamesURL, b.cfg.DownloadNamesFile)
+	if err != nil {
+		return err
+	}
 
 	slog.Info(
 		"Extracting names data from bhlindex.",
 		"file", b.cfg.DownloadNamesFile,
 	)
 	err = bhlsys.Extract(
-		b.cfg.DownloadNamesFile, b.cfg.ExtractDir, b.cfg.WithRebuild,
+		b.cfg.DownloadNamesFile, b.cfg.ExtractDir, rebuild,
 	)
 	if err != nil {
 		slog.Error("Cannot extract names data.",
@@ -156,3 +244,13 @@ func (b *builderio) downloadAndExtract() error {
 	}
 	return nil
 }
+
+// addSource keeps provenance of a downloaded dataset for the build.
+func (b *builderio) addSource(name, url, path string) error {
+	src, err := provio.Source(name, url, path)
+	if err != nil {
+		return err
+	}
+	b.sources = append(b.sources, src)
+	return nil
+}
@@ -3,6 +3,7 @@ package builderio
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"log/slog"
 	"math"
 
@@ -25,53 +26,35 @@ func (b builderio) maxItemID() (int, error) {
 	return res, nil
 }
 
-func (b builderio) addStatsToItems(
-	ctx context.Context,
-	chIn <-chan []txstats.ItemTaxa,
-) error {
-	columns := []string{
+var (
+	statsColumns = []string{
 		"id", "names_total", "main_taxon", "main_taxon_rank", "main_taxon_percent",
 		"main_kingdom", "main_kingdom_percent", "animalia_num", "plantae_num",
 		"fungi_num", "bacteria_num", "main_phylum", "main_phylum_percent",
 		"main_class", "main_class_percent", "main_order", "main_order_percent",
 		"main_family", "main_family_percent", "main_genus", "main_genus_percent",
 	}
+	taxonColumns = []string{"item_id", "rank", "taxon", "names_num", "percent"}
+)
 
+func (b builderio) addStatsToItems(
+	ctx context.Context,
+	chIn <-chan []txstats.ItemTaxa,
+) error {
 	var count int
 	for taxa := range chIn {
-		rows := make([][]any, 0, len(taxa))
 		count += len(taxa)
-
-		var taxon, taxonRank, kingdom, phylum, class, order,
-			family, genus string
-		var taxonPcnt, kingdomPcnt, phylumPcnt, classPcnt, orderPcnt,
-			familyPcnt, genusPcnt sql.NullInt16
-		var total, animNum, plantNum, fungiNum, bactNum uint
-		var st gnstats.Stats
-
-		for _, v := range taxa {
-			st = gnstats.New(v.Taxa, 0.5)
-			total = uint(st.NamesNum)
-			animNum, plantNum, fungiNum, bactNum = kingdomDistribution(st)
-			taxon, taxonRank, kingdom, phylum, class, order, family,
-				genus = statStrings(st)
-			taxonPcnt, kingdomPcnt, phylumPcnt, classPcnt, orderPcnt,
-				familyPcnt, genusPcnt = statInts(st)
-
-			row := []any{
-				v.ItemID, total, taxon, taxonRank, taxonPcnt,
-				kingdom, kingdomPcnt, animNum, plantNum,
-				fungiNum, bactNum, phylum, phylumPcnt,
-				class, classPcnt, order, orderPcnt,
-				family, familyPcnt, genus, genusPcnt,
-			}
-			rows = append(rows, row)
-		}
-		_, err := dbio.InsertRows(b.db, "item_stats", columns, rows)
+		rows, taxonRows := statsRows(taxa)
+		_, err := dbio.InsertRows(b.db, "item_stats", statsColumns, rows)
 		if err != nil {
 			slog.Error("Cannot insert rows to item_stats table", "error", err)
 			return err
 		}
+		_, err = dbio.InsertRows(b.db, "item_taxons", taxonColumns, taxonRows)
+		if err != nil {
+			slog.Error("Cannot insert rows to item_taxons table", "error", err)
+			return err
+		}
 
 		select {
 		case <-ctx.Done():
@@ -82,6 +65,57 @@ func (b builderio) addStatsToItems(
 	return nil
 }
 
+// statsRows creates rows of item_stats and item_taxons tables from
+// classifications of names found in items.
+func statsRows(taxa []txstats.ItemTaxa) ([][]any, [][]any) {
+	rows := make([][]any, 0, len(taxa))
+	var taxonRows [][]any
+
+	var taxon, taxonRank, kingdom, phylum, class, order,
+		family, genus string
+	var taxonPcnt, kingdomPcnt, phylumPcnt, classPcnt, orderPcnt,
+		familyPcnt, genusPcnt sql.NullInt16
+	var total, animNum, plantNum, fungiNum, bactNum uint
+	var st gnstats.Stats
+
+	for _, v := range taxa {
+		st = gnstats.New(v.Taxa, 0.5)
+		total = uint(st.NamesNum)
+		animNum, plantNum, fungiNum, bactNum = kingdomDistribution(st)
+		taxon, taxonRank, kingdom, phylum, class, order, family,
+			genus = statStrings(st)
+		taxonPcnt, kingdomPcnt, phylumPcnt, classPcnt, orderPcnt,
+			familyPcnt, genusPcnt = statInts(st)
+
+		row := []any{
+			v.ItemID, total, taxon, taxonRank, taxonPcnt,
+			kingdom, kingdomPcnt, animNum, plantNum,
+			fungiNum, bactNum, phylum, phylumPcnt,
+			class, classPcnt, order, orderPcnt,
+			family, familyPcnt, genus, genusPcnt,
+		}
+		rows = append(rows, row)
+
+		if st.NamesNum > 0 {
+			taxonRows = append(taxonRows, taxonDistRows(v)...)
+		}
+	}
+	return rows, taxonRows
+}
+
+// taxonDistRows creates rows of the item_taxons table with the full
+// taxonomic distribution of an item.
+func taxonDistRows(it txstats.ItemTaxa) [][]any {
+	dist := txstats.Distribution(it.Taxa)
+	res := make([][]any, len(dist))
+	for i, v := range dist {
+		res[i] = []any{
+			it.ItemID, v.Rank, v.Taxon, uint(v.NamesNum), uint(v.Percent),
+		}
+	}
+	return res
+}
+
 func statInts(st gnstats.Stats) (
 	sql.NullInt16, sql.NullInt16, sql.NullInt16,
 	sql.NullInt16, sql.NullInt16, sql.NullInt16, sql.NullInt16) {
@@ -148,28 +182,43 @@ func kingdomDistribution(st gnstats.Stats) (uint, uint, uint, uint) {
 	return anim, plant, fungi, bact
 }
 
-func (b builderio) getItemsTaxa(id, limit int) ([]txstats.ItemTaxa, error) {
-	res := make([]txstats.ItemTaxa, 0, limit)
-	var rows pgx.Rows
-	var err error
-
-	q := `
+const itemsTaxaQuery = `
 SELECT
   i.id, n.classification, n.classification_ranks, n.classification_ids
   FROM items i
     JOIN pages p on i.id = p.item_id
     JOIN name_occurrences o on p.id = o.page_id
     JOIN name_strings n on n.id = o.name_string_id
-  where i.id >= $1 and i.id < $2
+  where %s
 GROUP BY i.id, n.classification, n.classification_ranks, n.classification_ids
 ORDER BY i.id
 `
-	rows, err = b.db.Query(context.Background(), q, id, id+limit)
+
+func (b builderio) getItemsTaxa(id, limit int) ([]txstats.ItemTaxa, error) {
+	q := fmt.Sprintf(itemsTaxaQuery, "i.id >= $1 and i.id < $2")
+	rows, err := b.db.Query(context.Background(), q, id, id+limit)
+	if err != nil {
+		slog.Error("Cannot get Items data", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+	return scanItemsTaxa(rows, limit)
+}
+
+// getItemsTaxaByIDs returns classifications of names for the given items.
+func (b builderio) getItemsTaxaByIDs(ids []int) ([]txstats.ItemTaxa, error) {
+	q := fmt.Sprintf(itemsTaxaQuery, "i.id = ANY($1)")
+	rows, err := b.db.Query(context.Background(), q, ids)
 	if err != nil {
 		slog.Error("Cannot get Items data", "error", err)
 		return nil, err
 	}
 	defer rows.Close()
+	return scanItemsTaxa(rows, len(ids))
+}
+
+func scanItemsTaxa(rows pgx.Rows, size int) ([]txstats.ItemTaxa, error) {
+	res := make([]txstats.ItemTaxa, 0, size)
 
 	var curItemID int
 	var hs []gnstats.Hierarchy
@@ -25,10 +25,10 @@ const (
 
 var yrRe = regexp.MustCompile(`\b[c]?([\d]{4})\b\s*([,/-]\s*([\d]{4})\b)?`)
 
-// importItem reads item.txt file and imports data to the items table.
+// readItems reads item.txt file and prepares items for the items table.
 // It takes a map of titles as input, and uses it to add title data to the item.
 // the key of the map is title id, the value contains a title data.
-func (b builderio) importItem(titles map[int]*model.Title) error {
+func (b builderio) readItems(titles map[int]*model.Title) ([]*model.Item, error) {
 	slog.Info("Preparing item.txt data for db.")
 	iMap := make(map[int]struct{})
 	var res []*model.Item
@@ -36,7 +36,7 @@ func (b builderio) importItem(titles map[int]*model.Title) error {
 	f, err := os.Open(path)
 	if err != nil {
 		slog.Error("Cannot open item.txt.", "path", path, "error", err)
-		return err
+		return nil, err
 	}
 	defer f.Close()
 	scanner := bufio.NewScanner(f)
@@ -54,7 +54,7 @@ func (b builderio) importItem(titles map[int]*model.Title) error {
 		id, err = strconv.Atoi(fields[itemIDF])
 		if err != nil {
 			slog.Error("Cannot convert item id to int.", "id", fields[itemIDF])
-			return err
+			return nil, err
 		}
 		if _, ok := iMap[id]; ok {
 			continue
@@ -64,7 +64,7 @@ func (b builderio) importItem(titles map[int]*model.Title) error {
 		titleID, err = strconv.Atoi(fields[itemTitleIDF])
 		if err != nil {
 			slog.Error("Cannot convert title id to int.", "id", fields[itemTitleIDF])
-			return err
+			return nil, err
 		}
 
 		barCode := fields[itemBarCodeF]
@@ -83,30 +83,30 @@ func (b builderio) importItem(titles map[int]*model.Title) error {
 
 	if err = scanner.Err(); err != nil {
 		slog.Error("Error reading item.txt.", "error", err)
-		return err
+		return nil, err
 	}
+	return res, nil
+}
 
-	err = b.importItems(res)
-	if err != nil {
-		return err
-	}
-	return nil
+// itemColumns are columns of the items table in the order of itemRow.
+var itemColumns = []string{"id", "bar_code", "vol", "year_start", "year_end",
+	"title_id", "title_doi", "title_name", "title_year_start", "title_year_end",
+	"title_lang"}
+
+// itemRow returns values of an item for itemColumns.
+func itemRow(v *model.Item) []any {
+	return []any{v.ID, v.BarCode, v.Vol, v.YearStart, v.YearEnd,
+		v.TitleID, v.TitleDOI, v.TitleName, v.TitleYearStart, v.TitleYearEnd,
+		v.TitleLang}
 }
 
 func (b builderio) importItems(items []*model.Item) error {
 	slog.Info("Importing records to items table", "records-num", humanize.Comma(int64(len(items))))
-	columns := []string{"id", "bar_code", "vol", "year_start", "year_end",
-		"title_id", "title_doi", "title_name", "title_year_start", "title_year_end",
-		"title_lang"}
-
 	rows := make([][]any, len(items))
 	for i, v := range items {
-		row := []any{v.ID, v.BarCode, v.Vol, v.YearStart, v.YearEnd,
-			v.TitleID, v.TitleDOI, v.TitleName, v.TitleYearStart, v.TitleYearEnd,
-			v.TitleLang}
-		rows[i] = row
+		rows[i] = itemRow(v)
 	}
-	_, err := dbio.InsertRows(b.db, "items", columns, rows)
+	_, err := dbio.InsertRows(b.db, "items", itemColumns, rows)
 	if err != nil {
 		slog.Error("Cannot insert items.", "error", err)
 		return err
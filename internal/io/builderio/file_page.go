@@ -33,19 +33,23 @@ const (
 
 const BatchSize = 100_000
 
-func (b builderio) importPage() error {
+// importPage reads page.txt file and imports pages accepted by keep to the
+// pages table, all pages are imported if keep is nil. It returns sums of
+// hashes of pages by item IDs for all pages of the file.
+func (b builderio) importPage(keep func(*model.Page) bool) (map[int]uint64, error) {
 	var err error
 	var id, itemID, fileNum, pageNum int
 	slog.Info("Importing page.txt data to db.")
 
 	total := 0
+	hashes := make(map[int]uint64)
 	pMap := make(map[int]struct{})
 	res := make([]*model.Page, 0, BatchSize)
 	path := filepath.Join(b.cfg.ExtractDir, "page.txt")
 	f, err := os.Open(path)
 	if err != nil {
 		slog.Error("Cannot open page.txt.", "path", path, "error", err)
-		return err
+		return nil, err
 	}
 	defer f.Close()
 
@@ -63,7 +67,7 @@ func (b builderio) importPage() error {
 		id, err = strconv.Atoi(fields[pageIDF])
 		if err != nil {
 			slog.Error("Cannot convert page id to int.", "id", fields[pageIDF])
-			return err
+			return nil, err
 		}
 
 		if _, ok := pMap[id]; ok {
@@ -71,13 +75,12 @@ func (b builderio) importPage() error {
 		} else {
 			pMap[id] = struct{}{}
 		}
-		count++
 		page := &model.Page{ID: uint(id)}
 
 		itemID, err = strconv.Atoi(fields[pageItemIDF])
 		if err != nil {
 			slog.Error("Cannot convert item id to int.", "id", fields[pageItemIDF])
-			return err
+			return nil, err
 		}
 		page.ItemID = uint(itemID)
 
@@ -87,7 +90,7 @@ func (b builderio) importPage() error {
 				"Cannot convert file number to int.",
 				"file number", fields[pageFileNumF],
 			)
-			return err
+			return nil, err
 		}
 		page.SequenceOrder = uint(fileNum)
 
@@ -95,6 +98,13 @@ func (b builderio) importPage() error {
 		if err == nil {
 			page.PageNum = sql.NullInt64{Int64: int64(pageNum), Valid: true}
 		}
+
+		// the sum does not depend on the order of pages
+		hashes[itemID] += rowHash(pageRow(page))
+		if keep != nil && !keep(page) {
+			continue
+		}
+		count++
 		res = append(res, page)
 
 		if count >= BatchSize {
@@ -104,15 +114,19 @@ func (b builderio) importPage() error {
 			copy(pages, res)
 			err = b.processPages(pages, total)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			res = make([]*model.Page, 0, BatchSize)
 		}
 	}
+	if err = scanner.Err(); err != nil {
+		slog.Error("Error reading page.txt.", "error", err)
+		return nil, err
+	}
 	total += len(res)
 	err = b.processPages(res, total)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	fmt.Fprintf(os.Stderr, "\r%s\r", strings.Repeat(" ", 35))
@@ -120,7 +134,7 @@ func (b builderio) importPage() error {
 		"Imported page.txt data to db.",
 		"records-num", humanize.Comma(int64(total)),
 	)
-	return nil
+	return hashes, nil
 }
 
 func (b builderio) processPages(
@@ -128,15 +142,12 @@ func (b builderio) processPages(
 	total int,
 ) error {
 	var err error
-	columns := []string{"id", "item_id", "sequence_order", "page_num"}
 	rows := make([][]any, len(pages))
-
 	for i, v := range pages {
-		row := []any{v.ID, v.ItemID, v.SequenceOrder, v.PageNum}
-		rows[i] = row
+		rows[i] = pageRow(v)
 	}
 
-	_, err = dbio.InsertRows(b.db, "pages", columns, rows)
+	_, err = dbio.InsertRows(b.db, "pages", pageColumns, rows)
 	if err != nil {
 		slog.Error("Error inserting rows to pages table.", "error", err)
 		return err
@@ -146,3 +157,11 @@ func (b builderio) processPages(
 	fmt.Fprintf(os.Stderr, "\rImported %s pages to db", humanize.Comma(int64(total)))
 	return nil
 }
+
+// pageColumns are columns of the pages table in the order of pageRow.
+var pageColumns = []string{"id", "item_id", "sequence_order", "page_num"}
+
+// pageRow returns values of a page for pageColumns.
+func pageRow(v *model.Page) []any {
+	return []any{v.ID, v.ItemID, v.SequenceOrder, v.PageNum}
+}
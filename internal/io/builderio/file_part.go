@@ -49,7 +49,9 @@ type partPages struct {
 var dateRe = regexp.MustCompile(`\b([\d]{4})\b\s*(-\s*([\d]{1,4})\b(-([\d]{1,2}))?)?`)
 var pagesRe = regexp.MustCompile(`\b([\d]+)\b\s*((,|-|--|–)\s*\b([\d]+)\b)?`)
 
-func (b builderio) importPart(doiMap map[int]string) error {
+// readParts reads part.txt file and prepares parts for the parts table.
+// It takes a map of DOIs with part IDs as keys.
+func (b builderio) readParts(doiMap map[int]string) ([]*model.Part, error) {
 	slog.Info("Preparing part.txt data for db.")
 	//keeps unique IDs of the parts
 	pMap := make(map[int]struct{})
@@ -58,7 +60,7 @@ func (b builderio) importPart(doiMap map[int]string) error {
 	f, err := os.Open(path)
 	if err != nil {
 		slog.Error("Cannot open part.txt.", "path", path, "error", err)
-		return err
+		return nil, err
 	}
 
 	defer f.Close()
@@ -76,7 +78,7 @@ func (b builderio) importPart(doiMap map[int]string) error {
 		id, err := strconv.Atoi(fields[partIDF])
 		if err != nil {
 			slog.Error("Cannot convert part id to int.", "id", fields[partIDF])
-			return err
+			return nil, err
 		}
 		if _, ok := pMap[id]; ok {
 			continue
@@ -128,7 +130,27 @@ func (b builderio) importPart(doiMap map[int]string) error {
 			slog.Error("Error converting part data.", "error", err)
 		}
 	}
-	return b.importParts(res)
+	if err = scanner.Err(); err != nil {
+		slog.Error("Error reading part.txt.", "error", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+// partColumns are columns of the parts table in the order of partRow.
+var partColumns = []string{"id", "page_id", "item_id", "length", "doi",
+	"contributor_name", "sequence_order", "segment_type", "title",
+	"container_title", "publication_details", "volume", "series",
+	"issue", "date", "year", "year_end", "month", "day", "page_num_start",
+	"page_num_end", "language"}
+
+// partRow returns values of a part for partColumns.
+func partRow(v *model.Part) []any {
+	return []any{v.ID, v.PageID, v.ItemID, v.Length, v.DOI,
+		v.ContributorName, v.SequenceOrder, v.SegmentType, v.Title,
+		v.ContainerTitle, v.PublicationDetails, v.Volume, v.Series,
+		v.Issue, v.Date, v.Year, v.YearEnd, v.Month, v.Day,
+		v.PageNumStart, v.PageNumEnd, v.Language}
 }
 
 func (b builderio) importParts(parts []*model.Part) error {
@@ -145,23 +167,12 @@ func (b builderio) importParts(parts []*model.Part) error {
 		"Importing records to parts table",
 		"records-num", humanize.Comma(int64(len(parts))),
 	)
-	columns := []string{"id", "page_id", "item_id", "length", "doi",
-		"contributor_name", "sequence_order", "segment_type", "title",
-		"container_title", "publication_details", "volume", "series",
-		"issue", "date", "year", "year_end", "month", "day", "page_num_start",
-		"page_num_end", "language"}
 	rows := make([][]any, len(parts))
-
 	for i, v := range parts {
-		row := []any{v.ID, v.PageID, v.ItemID, v.Length, v.DOI,
-			v.ContributorName, v.SequenceOrder, v.SegmentType, v.Title,
-			v.ContainerTitle, v.PublicationDetails, v.Volume, v.Series,
-			v.Issue, v.Date, v.Year, v.YearEnd, v.Month, v.Day,
-			v.PageNumStart, v.PageNumEnd, v.Language}
-		rows[i] = row
+		rows[i] = partRow(v)
 	}
 
-	_, err = dbio.InsertRows(b.db, "parts", columns, rows)
+	_, err = dbio.InsertRows(b.db, "parts", partColumns, rows)
 	if err != nil {
 		slog.Error("Error inserting rows to parts table.", "error", err)
 		return err
@@ -0,0 +1,191 @@
+package builderio
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+
+	"github.com/dustin/go-humanize"
+	"github.com/gnames/bhlnames/internal/ent/model"
+	"github.com/gnames/bhlnames/internal/io/dbio"
+)
+
+const (
+	kindAdded   = "added"
+	kindUpdated = "updated"
+	kindDeleted = "deleted"
+)
+
+// dumpHashes contains hashes of records of BHL and bhlindex dumps. They
+// are saved with a build, so an update finds changed records by comparing
+// the hashes of the new dumps with the saved ones.
+type dumpHashes struct {
+	// items are hashes of item rows by item IDs.
+	items map[int]uint64
+
+	// pages are sums of hashes of pages by item IDs.
+	pages map[int]uint64
+
+	// occurs are sums of hashes of names' occurrences by item IDs.
+	occurs map[int]uint64
+
+	// parts are hashes of part rows by part IDs.
+	parts map[int]uint64
+}
+
+// itemHash combines hashes of an item, its pages and occurrences.
+type itemHash struct {
+	item, pages, occurs uint64
+}
+
+// newDumpHashes calculates hashes of items and parts. Hashes of pages and
+// occurrences are calculated during their import.
+func newDumpHashes(items []*model.Item, parts []*model.Part) dumpHashes {
+	res := dumpHashes{
+		items: make(map[int]uint64, len(items)),
+		parts: make(map[int]uint64, len(parts)),
+	}
+	for _, v := range items {
+		res.items[int(v.ID)] = rowHash(itemRow(v))
+	}
+	for _, v := range parts {
+		res.parts[int(v.ID)] = rowHash(partRow(v))
+	}
+	return res
+}
+
+// itemHashes returns combined hashes of every item.
+func (h dumpHashes) itemHashes() map[int]itemHash {
+	res := make(map[int]itemHash, len(h.items))
+	for k, v := range h.items {
+		res[k] = itemHash{item: v, pages: h.pages[k], occurs: h.occurs[k]}
+	}
+	return res
+}
+
+// rowHash returns a hash of values of a database row.
+func rowHash(row []any) uint64 {
+	h := fnv.New64a()
+	for _, v := range row {
+		fmt.Fprintf(h, "%v\x00", v)
+	}
+	return h.Sum64()
+}
+
+// changes compares new hashes with the current ones and returns kinds of
+// changes by IDs of added, updated and deleted records.
+func changes[T comparable](cur, new map[int]T) map[int]string {
+	res := make(map[int]string)
+	for k, v := range new {
+		old, ok := cur[k]
+		switch {
+		case !ok:
+			res[k] = kindAdded
+		case old != v:
+			res[k] = kindUpdated
+		}
+	}
+	for k := range cur {
+		if _, ok := new[k]; !ok {
+			res[k] = kindDeleted
+		}
+	}
+	return res
+}
+
+// saveHashes saves hashes of the given items and parts to the staging
+// schema.
+func (b builderio) saveHashes(h dumpHashes, itemIDs, partIDs []int) error {
+	slog.Info("Saving hashes of dump records.",
+		"items-num", humanize.Comma(int64(len(itemIDs))),
+		"parts-num", humanize.Comma(int64(len(partIDs))),
+	)
+	items := h.itemHashes()
+	rows := make([][]any, len(itemIDs))
+	for i, id := range itemIDs {
+		v := items[id]
+		rows[i] = []any{id, int64(v.item), int64(v.pages), int64(v.occurs)}
+	}
+	columns := []string{"id", "item", "pages", "occurrences"}
+	_, err := dbio.InsertRows(b.db, "item_hashes", columns, rows)
+	if err != nil {
+		slog.Error("Cannot insert rows to item_hashes table.", "error", err)
+		return err
+	}
+
+	rows = make([][]any, len(partIDs))
+	for i, id := range partIDs {
+		rows[i] = []any{id, int64(h.parts[id])}
+	}
+	_, err = dbio.InsertRows(b.db, "part_hashes", []string{"id", "hash"}, rows)
+	if err != nil {
+		slog.Error("Cannot insert rows to part_hashes table.", "error", err)
+		return err
+	}
+	return nil
+}
+
+// currentHashes returns hashes saved with the data used by readers.
+func (b builderio) currentHashes(
+	ctx context.Context,
+) (map[int]itemHash, map[int]uint64, error) {
+	slog.Info("Reading hashes of the current data.")
+	items := make(map[int]itemHash)
+	q := "SELECT id, item, pages, occurrences FROM " + pubTable("item_hashes")
+	rows, err := b.admin.Query(ctx, q)
+	if err != nil {
+		slog.Error("Cannot get item hashes.", "error", err)
+		return nil, nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int
+		var item, pages, occurs int64
+		err = rows.Scan(&id, &item, &pages, &occurs)
+		if err != nil {
+			slog.Error("Cannot read item hashes.", "error", err)
+			return nil, nil, err
+		}
+		items[id] = itemHash{
+			item: uint64(item), pages: uint64(pages), occurs: uint64(occurs),
+		}
+	}
+	if err = rows.Err(); err != nil {
+		slog.Error("Cannot read item hashes.", "error", err)
+		return nil, nil, err
+	}
+
+	parts := make(map[int]uint64)
+	q = "SELECT id, hash FROM " + pubTable("part_hashes")
+	rows, err = b.admin.Query(ctx, q)
+	if err != nil {
+		slog.Error("Cannot get part hashes.", "error", err)
+		return nil, nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int
+		var hash int64
+		err = rows.Scan(&id, &hash)
+		if err != nil {
+			slog.Error("Cannot read part hashes.", "error", err)
+			return nil, nil, err
+		}
+		parts[id] = uint64(hash)
+	}
+	if err = rows.Err(); err != nil {
+		slog.Error("Cannot read part hashes.", "error", err)
+		return nil, nil, err
+	}
+	return items, parts, nil
+}
+
+// mapIDs returns keys of a map.
+func mapIDs[T any](m map[int]T) []int {
+	res := make([]int, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+	return res
+}
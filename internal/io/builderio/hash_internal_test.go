@@ -0,0 +1,63 @@
+package builderio
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/gnames/bhlnames/internal/ent/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChanges(t *testing.T) {
+	assert := assert.New(t)
+	cur := map[int]itemHash{
+		1: {item: 1, pages: 1, occurs: 1},
+		2: {item: 2, pages: 2, occurs: 2},
+		3: {item: 3, pages: 3, occurs: 3},
+	}
+	new := map[int]itemHash{
+		1: {item: 1, pages: 1, occurs: 1},
+		2: {item: 2, pages: 2, occurs: 5},
+		4: {item: 4, pages: 4, occurs: 4},
+	}
+	res := changes(cur, new)
+	assert.Equal(map[int]string{
+		2: kindUpdated,
+		3: kindDeleted,
+		4: kindAdded,
+	}, res)
+	assert.Empty(changes(cur, cur))
+}
+
+func TestRowHash(t *testing.T) {
+	assert := assert.New(t)
+	pg := &model.Page{ID: 1, ItemID: 2, SequenceOrder: 3}
+	h := rowHash(pageRow(pg))
+	assert.Equal(h, rowHash(pageRow(pg)))
+
+	pg2 := *pg
+	pg2.PageNum = sql.NullInt64{Int64: 5, Valid: true}
+	assert.NotEqual(h, rowHash(pageRow(&pg2)))
+
+	// values do not run into each other
+	assert.NotEqual(
+		rowHash([]any{"ab", "c"}),
+		rowHash([]any{"a", "bc"}),
+	)
+}
+
+func TestItemHashes(t *testing.T) {
+	assert := assert.New(t)
+	items := []*model.Item{{ID: 1, Vol: "v. 1"}, {ID: 2, Vol: "v. 2"}}
+	parts := []*model.Part{{ID: 10, Title: "Part"}}
+	hs := newDumpHashes(items, parts)
+	hs.pages = map[int]uint64{1: 7}
+	hs.occurs = map[int]uint64{2: 9, 3: 11}
+
+	res := hs.itemHashes()
+	assert.Len(res, 2)
+	assert.Equal(uint64(7), res[1].pages)
+	assert.Equal(uint64(0), res[1].occurs)
+	assert.Equal(uint64(9), res[2].occurs)
+	assert.Len(hs.parts, 1)
+}
@@ -6,63 +6,100 @@ import (
 	"github.com/gnames/bhlnames/internal/io/dbio"
 )
 
-func (b builderio) importDataBHL() error {
-	var err error
-	// titleMap has titleID as a key, and Title data as a value.
-	var titlesMap map[int]*model.Title
-	var partDOImap map[int]string
-	var titleDOImap map[int]string
+// importDataBHL imports data from the BHL dump and creates abbreviations
+// of titles. It returns hashes of imported items, pages and parts.
+func (b builderio) importDataBHL() (dumpHashes, error) {
+	titlesMap, hs, err := b.importTablesBHL()
+	if err != nil {
+		return hs, err
+	}
 
-	err = dbio.Truncate(b.db, []string{"items", "pages", "parts", "page_parts"})
+	err = b.assignPartsToPages()
 	if err != nil {
-		return err
+		return hs, err
 	}
 
-	// DOI can belong to either a title or a part, so we need to prepare two
-	// lookup maps for the next steps: one for titles and one for parts.
-	titleDOImap, partDOImap, err = b.prepareDOI()
+	ac, err := acstorio.New(b.cfg, b.db, titlesMap)
 	if err != nil {
-		return err
+		return hs, err
 	}
 
-	// title data is needed for items, so we prepare it first.
-	titlesMap, err = b.prepareTitle(titleDOImap)
+	// Create AhoCorasickStore where abbreviated titles point to title IDs.
+	// It also creates a file with all found abbreviations, that is used
+	// lately to get Aho-Coarsick trie.
+	err = ac.Setup()
 	if err != nil {
-		return err
+		return hs, err
 	}
 
-	err = b.importItem(titlesMap)
+	return hs, nil
+}
+
+// importTablesBHL imports items, parts and pages from the BHL dump. It
+// returns titles data with title ID as a key, and hashes of imported
+// records.
+func (b builderio) importTablesBHL() (map[int]*model.Title, dumpHashes, error) {
+	var hs dumpHashes
+	err := dbio.Truncate(b.db, []string{"items", "pages", "parts", "page_parts"})
 	if err != nil {
-		return err
+		return nil, hs, err
 	}
 
-	err = b.importPart(partDOImap)
+	titlesMap, items, parts, err := b.readTablesBHL()
 	if err != nil {
-		return err
+		return nil, hs, err
 	}
+	hs = newDumpHashes(items, parts)
 
-	err = b.importPage()
+	err = b.importItems(items)
 	if err != nil {
-		return err
+		return nil, hs, err
 	}
 
-	err = b.assignPartsToPages()
+	err = b.importParts(parts)
 	if err != nil {
-		return err
+		return nil, hs, err
 	}
 
-	ac, err := acstorio.New(b.cfg, b.db, titlesMap)
+	hs.pages, err = b.importPage(nil)
 	if err != nil {
-		return err
+		return nil, hs, err
 	}
 
-	// Create AhoCorasickStore where abbreviated titles point to title IDs.
-	// It also creates a file with all found abbreviations, that is used
-	// lately to get Aho-Coarsick trie.
-	err = ac.Setup()
+	return titlesMap, hs, nil
+}
+
+// readTablesBHL reads titles, items and parts from the BHL dump. Titles
+// are returned with title ID as a key.
+func (b builderio) readTablesBHL() (
+	map[int]*model.Title,
+	[]*model.Item,
+	[]*model.Part,
+	error,
+) {
+	// DOI can belong to either a title or a part, so we need to prepare two
+	// lookup maps for the next steps: one for titles and one for parts.
+	titleDOImap, partDOImap, err := b.prepareDOI()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// title data is needed for items, so we prepare it first.
+	// titleMap has titleID as a key, and Title data as a value.
+	titlesMap, err := b.prepareTitle(titleDOImap)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	items, err := b.readItems(titlesMap)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	parts, err := b.readParts(partDOImap)
 	if err != nil {
-		return err
+		return nil, nil, nil, err
 	}
 
-	return nil
+	return titlesMap, items, parts, nil
 }
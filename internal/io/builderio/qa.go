@@ -0,0 +1,290 @@
+package builderio
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gnames/bhlnames/pkg/ent/builder"
+	"github.com/gnames/gnfmt"
+	"github.com/jackc/pgx/v5"
+)
+
+// qaSamplesNum is the maximum number of samples of anomalies for a check.
+const qaSamplesNum = 10
+
+// ignoredOccursCheck is the name of the check of occurrences that were
+// not imported because their names were not imported.
+const ignoredOccursCheck = "ignored_occurrences"
+
+// qaCheck describes a data quality check by parts of an SQL query.
+type qaCheck struct {
+	name, desc string
+
+	// from is a FROM clause with the checked records.
+	from string
+
+	// scope limits the checked records, all records are checked if it is
+	// empty.
+	scope string
+
+	// cond is true for anomalies.
+	cond string
+
+	// sample identifies an anomaly in the report.
+	sample string
+}
+
+var qaChecks = []qaCheck{
+	{
+		name:   "items_without_years",
+		desc:   "Items without a year, the years are missing or not parsed.",
+		from:   "items i",
+		cond:   "i.year_start IS NULL",
+		sample: "i.id",
+	},
+	{
+		name:   "items_without_pages",
+		desc:   "Items that have no pages.",
+		from:   "items i",
+		cond:   "NOT EXISTS (SELECT 1 FROM pages pg WHERE pg.item_id = i.id)",
+		sample: "i.id",
+	},
+	{
+		name: "titles_without_years",
+		desc: "Titles without a start year.",
+		from: `(SELECT title_id, min(title_year_start) AS year_start
+    FROM items GROUP BY title_id) t`,
+		cond:   "t.year_start IS NULL",
+		sample: "t.title_id",
+	},
+	{
+		name:   "parts_unparsed_dates",
+		desc:   "Parts with a date that does not have a parsed year.",
+		from:   "parts pt",
+		scope:  "coalesce(pt.date, '') <> ''",
+		cond:   "pt.year IS NULL",
+		sample: "pt.id",
+	},
+	{
+		name:   "parts_without_page_range",
+		desc:   "Parts without a start page number, the range is missing or not parsed.",
+		from:   "parts pt",
+		cond:   "pt.page_num_start IS NULL",
+		sample: "pt.id",
+	},
+	{
+		name:   "parts_missing_start_page",
+		desc:   "Parts with a start page ID that is not among pages.",
+		from:   "parts pt",
+		scope:  "pt.page_id IS NOT NULL",
+		cond:   "NOT EXISTS (SELECT 1 FROM pages pg WHERE pg.id = pt.page_id)",
+		sample: "pt.id",
+	},
+	{
+		name:   "pages_without_number",
+		desc:   "Pages without a page number.",
+		from:   "pages pg",
+		cond:   "pg.page_num IS NULL",
+		sample: "pg.id",
+	},
+	{
+		name: "orphan_occurrences",
+		desc: "Occurrences with a page or a name that are not imported.",
+		from: "name_occurrences o",
+		cond: `NOT EXISTS (SELECT 1 FROM pages pg WHERE pg.id = o.page_id)
+    OR NOT EXISTS (SELECT 1 FROM name_strings ns WHERE ns.id = o.name_string_id)`,
+		sample: "o.page_id || ':' || o.name_string_id",
+	},
+}
+
+// CheckData counts and samples anomalies of the new build, writes the
+// report and returns an error if any check exceeds its threshold.
+func (b *builderio) CheckData() (builder.QAReport, error) {
+	res, err := b.runChecks(context.Background())
+	if err != nil {
+		return res, err
+	}
+
+	evaluate(&res, b.cfg.QAThresholds)
+	logQA(res)
+
+	err = writeQA(b.cfg.QAReportFile, res)
+	if err != nil {
+		return res, err
+	}
+
+	if res.Failed {
+		var names []string
+		for _, v := range res.Checks {
+			if v.Failed {
+				names = append(names, v.Name)
+			}
+		}
+		err = fmt.Errorf(
+			"data quality checks failed: %s, see %s",
+			strings.Join(names, ", "), b.cfg.QAReportFile,
+		)
+		slog.Error("The new build is not published.", "error", err)
+		return res, err
+	}
+	return res, nil
+}
+
+// checkChanges counts and samples anomalies of records changed by an
+// update and writes them to a separate report. The staging schema of an
+// update contains only changed records, their percentages are not
+// comparable with thresholds of a full build, so thresholds are ignored.
+func (b *builderio) checkChanges() (builder.QAReport, error) {
+	res, err := b.runChecks(context.Background())
+	if err != nil {
+		return res, err
+	}
+
+	evaluate(&res, nil)
+	logQA(res)
+	return res, writeQA(b.cfg.QAUpdateReportFile, res)
+}
+
+// runChecks runs all data quality checks on the staging schema.
+func (b *builderio) runChecks(ctx context.Context) (builder.QAReport, error) {
+	res := builder.QAReport{CreatedAt: time.Now().UTC()}
+
+	slog.Info("Checking quality of the new data.", "schema", b.schema)
+	for _, v := range qaChecks {
+		chk, err := b.runCheck(ctx, v)
+		if err != nil {
+			return res, err
+		}
+		res.Checks = append(res.Checks, chk)
+	}
+
+	// occurrences skipped during import are not in the database
+	ignored := builder.QACheck{
+		Name:        ignoredOccursCheck,
+		Description: "Occurrences that were not imported, their names are not imported.",
+		Anomalies:   b.ignoredOccurs,
+	}
+	for _, v := range res.Checks {
+		if v.Name == "orphan_occurrences" {
+			ignored.Total = v.Total + b.ignoredOccurs
+		}
+	}
+	res.Checks = append(res.Checks, ignored)
+	return res, nil
+}
+
+// runCheck counts checked records and anomalies, and collects samples of
+// anomalies.
+func (b builderio) runCheck(
+	ctx context.Context,
+	chk qaCheck,
+) (builder.QACheck, error) {
+	res := builder.QACheck{Name: chk.name, Description: chk.desc}
+	countQ, sampleQ := checkQueries(chk)
+
+	err := b.db.QueryRow(ctx, countQ).Scan(&res.Total, &res.Anomalies)
+	if err != nil {
+		slog.Error("Cannot run data check.", "check", chk.name, "error", err)
+		return res, err
+	}
+	if res.Anomalies == 0 {
+		return res, nil
+	}
+
+	rows, err := b.db.Query(ctx, sampleQ)
+	if err != nil {
+		slog.Error("Cannot get samples.", "check", chk.name, "error", err)
+		return res, err
+	}
+	res.Samples, err = pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		slog.Error("Cannot read samples.", "check", chk.name, "error", err)
+		return res, err
+	}
+	return res, nil
+}
+
+// checkQueries returns a query that counts checked records and anomalies,
+// and a query for samples of anomalies.
+func checkQueries(chk qaCheck) (string, string) {
+	scope := chk.scope
+	if scope == "" {
+		scope = "true"
+	}
+	countQ := fmt.Sprintf(`
+SELECT count(*), count(*) FILTER (WHERE %s)
+  FROM %s
+  WHERE %s`, chk.cond, chk.from, scope)
+	sampleQ := fmt.Sprintf(`
+SELECT (%s)::text
+  FROM %s
+  WHERE (%s) AND (%s)
+  LIMIT %d`, chk.sample, chk.from, scope, chk.cond, qaSamplesNum)
+	return countQ, sampleQ
+}
+
+// evaluate calculates percentages of anomalies and compares them with
+// thresholds.
+func evaluate(res *builder.QAReport, thresholds map[string]float64) {
+	var names []string
+	for i := range res.Checks {
+		chk := &res.Checks[i]
+		names = append(names, chk.Name)
+
+		var pcent float64
+		if chk.Total > 0 {
+			pcent = float64(chk.Anomalies) * 100 / float64(chk.Total)
+		}
+		chk.Percent = math.Round(pcent*100) / 100
+
+		limit, ok := thresholds[chk.Name]
+		if !ok {
+			continue
+		}
+		chk.Threshold = &limit
+		if pcent > limit {
+			chk.Failed = true
+			res.Failed = true
+		}
+	}
+
+	for k := range thresholds {
+		if !slices.Contains(names, k) {
+			slog.Warn("Unknown data quality check in thresholds.", "check", k)
+		}
+	}
+}
+
+func logQA(r builder.QAReport) {
+	for _, v := range r.Checks {
+		if v.Anomalies == 0 {
+			continue
+		}
+		attrs := []any{
+			"check", v.Name, "anomalies", v.Anomalies, "percent", v.Percent,
+		}
+		if v.Failed {
+			slog.Error("Data check exceeds its threshold.", attrs...)
+			continue
+		}
+		slog.Info("Data check found anomalies.", attrs...)
+	}
+}
+
+// writeQA saves the report to a JSON file.
+func writeQA(path string, r builder.QAReport) error {
+	out := gnfmt.GNjson{Pretty: true}.Output(r, gnfmt.PrettyJSON)
+	err := os.WriteFile(path, []byte(out+"\n"), 0644)
+	if err != nil {
+		slog.Error("Cannot write data quality report.", "file", path, "error", err)
+		return err
+	}
+	slog.Info("Data quality report is saved.", "file", path)
+	return nil
+}
@@ -0,0 +1,55 @@
+package builderio
+
+import (
+	"testing"
+
+	"github.com/gnames/bhlnames/pkg/ent/builder"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluate(t *testing.T) {
+	assert := assert.New(t)
+	newReport := func() builder.QAReport {
+		return builder.QAReport{Checks: []builder.QACheck{
+			{Name: "items_without_pages", Total: 300, Anomalies: 2},
+			{Name: "pages_without_number", Total: 1000, Anomalies: 100},
+			{Name: "orphan_occurrences", Total: 0},
+		}}
+	}
+
+	r := newReport()
+	evaluate(&r, nil)
+	assert.False(r.Failed)
+	assert.Equal(0.67, r.Checks[0].Percent)
+	assert.Equal(10.0, r.Checks[1].Percent)
+	assert.Equal(0.0, r.Checks[2].Percent)
+	assert.Nil(r.Checks[0].Threshold)
+
+	r = newReport()
+	evaluate(&r, map[string]float64{
+		"items_without_pages":  1,
+		"pages_without_number": 5,
+		"orphan_occurrences":   0,
+	})
+	assert.True(r.Failed)
+	assert.False(r.Checks[0].Failed)
+	assert.Equal(1.0, *r.Checks[0].Threshold)
+	assert.True(r.Checks[1].Failed)
+	assert.False(r.Checks[2].Failed)
+}
+
+func TestCheckQueries(t *testing.T) {
+	assert := assert.New(t)
+	names := make(map[string]struct{})
+	for _, v := range qaChecks {
+		names[v.name] = struct{}{}
+		countQ, sampleQ := checkQueries(v)
+		assert.Contains(countQ, "FILTER (WHERE "+v.cond+")", v.name)
+		assert.Contains(sampleQ, "LIMIT 10", v.name)
+		if v.scope == "" {
+			assert.Contains(countQ, "WHERE true", v.name)
+		}
+	}
+	assert.Equal(len(qaChecks), len(names))
+	assert.NotContains(names, ignoredOccursCheck)
+}
@@ -2,7 +2,6 @@ package builderio
 
 import (
 	"context"
-	"fmt"
 	"log/slog"
 	"os"
 
@@ -10,20 +9,14 @@ import (
 	"github.com/gnames/gnsys"
 )
 
-func (b builderio) resetDB() error {
-	slog.Info("Resetting database.", "database",
+// prepareStaging creates an empty staging schema with all tables of a
+// build.
+func (b builderio) prepareStaging() error {
+	slog.Info("Preparing new build.", "database",
 		b.cfg.DbDatabase, "host", b.cfg.DbHost,
 	)
-	q := `
-DROP SCHEMA IF EXISTS public CASCADE;
-CREATE SCHEMA public;
-GRANT ALL ON SCHEMA public TO postgres;
-GRANT ALL ON SCHEMA public TO %s;
-COMMENT ON SCHEMA public IS 'standard public schema'`
-	q = fmt.Sprintf(q, b.cfg.DbUser)
-	_, err := b.db.Exec(context.Background(), q)
+	err := b.createStaging(context.Background())
 	if err != nil {
-		slog.Error("Cannot reset database.", "err", err)
 		return err
 	}
 
@@ -40,7 +33,7 @@ COMMENT ON SCHEMA public IS 'standard public schema'`
 	// 		return err
 	// 	}
 
-	slog.Info("Creating tables.")
+	slog.Info("Creating tables.", "schema", b.schema)
 	err = model.Migrate(b.grm)
 	if err != nil {
 		slog.Error("Cannot create tables.", "err", err)
@@ -0,0 +1,279 @@
+package builderio
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// readSchema is the schema used by readers of the data.
+	readSchema = "public"
+
+	// prevSchema keeps the previous build for a rollback.
+	prevSchema = "bhl_prev"
+
+	// swapSchema is a temporary name used during a switch of schemas.
+	swapSchema = "bhl_swap"
+)
+
+// stagingRe matches names of staging schemas.
+var stagingRe = regexp.MustCompile(`^bhl_\d{14}$`)
+
+// colTables are created by the CoL import and are not a part of a build,
+// so their data is carried over to every new build.
+var colTables = []string{"col_names", "col_bhl_refs", "col_bhl_results"}
+
+// infoTables keep sources and the history of builds. Their data are carried
+// over to every new build, records of the new build are added to them.
+var infoTables = []string{"data_sources", "builds"}
+
+// dumpTables are imported directly from BHL and bhlindex dumps.
+var dumpTables = []string{
+	"items", "pages", "parts", "name_strings", "name_occurrences",
+}
+
+// hashTables keep hashes of records of the dumps, they are used to find
+// changed records during an update.
+var hashTables = []string{"item_hashes", "part_hashes"}
+
+// validTables must not be empty for a build to become readable.
+var validTables = slices.Concat(dumpTables, hashTables, []string{
+	"item_stats", "abbr_titles",
+})
+
+// stagingSchema returns a name of the staging schema for a build that
+// starts at a given time.
+func stagingSchema(t time.Time) string {
+	return "bhl_" + t.UTC().Format("20060102150405")
+}
+
+// createStaging removes leftovers of unfinished builds and creates an
+// empty staging schema.
+func (b builderio) createStaging(ctx context.Context) error {
+	err := b.dropStaging(ctx)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Creating staging schema.", "schema", b.schema)
+	q := "CREATE SCHEMA " + pgx.Identifier{b.schema}.Sanitize()
+	_, err = b.admin.Exec(ctx, q)
+	if err != nil {
+		slog.Error("Cannot create staging schema.", "error", err)
+		return err
+	}
+	return nil
+}
+
+// dropStaging removes all staging schemas. The schema used by readers
+// and the previous build stay untouched.
+func (b builderio) dropStaging(ctx context.Context) error {
+	schemas, err := b.schemas(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range schemas {
+		if !stagingRe.MatchString(v) && v != swapSchema {
+			continue
+		}
+		slog.Info("Removing unfinished build.", "schema", v)
+		q := "DROP SCHEMA " + pgx.Identifier{v}.Sanitize() + " CASCADE"
+		_, err = b.admin.Exec(ctx, q)
+		if err != nil {
+			slog.Error("Cannot remove schema.", "schema", v, "error", err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (b builderio) schemas(ctx context.Context) ([]string, error) {
+	rows, err := b.admin.Query(ctx, "SELECT nspname FROM pg_namespace")
+	if err != nil {
+		slog.Error("Cannot get database schemas.", "error", err)
+		return nil, err
+	}
+	res, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		slog.Error("Cannot read database schemas.", "error", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+// copyKeptData copies CoL and provenance tables from the schema used by
+// readers to the staging schema.
+func (b builderio) copyKeptData(ctx context.Context) error {
+	for _, v := range slices.Concat(colTables, infoTables) {
+		cols, err := b.commonColumns(ctx, v)
+		if err != nil {
+			return err
+		}
+		// the table is absent in one of the schemas
+		if len(cols) == 0 {
+			continue
+		}
+
+		slog.Info("Copying data to the new build.", "table", v)
+		colStr := cols.Sanitize()
+		src := pgx.Identifier{readSchema, v}.Sanitize()
+		dst := pgx.Identifier{b.schema, v}.Sanitize()
+		q := fmt.Sprintf(
+			"INSERT INTO %s (%s) SELECT %s FROM %s", dst, colStr, colStr, src,
+		)
+		_, err = b.admin.Exec(ctx, q)
+		if err != nil {
+			slog.Error("Cannot copy data.", "table", v, "error", err)
+			return err
+		}
+
+		if !slices.Contains(cols, "id") {
+			continue
+		}
+		q = fmt.Sprintf(
+			"SELECT setval(pg_get_serial_sequence($1, 'id'), max(id)) FROM %s",
+			dst,
+		)
+		_, err = b.admin.Exec(ctx, q, dst)
+		if err != nil {
+			slog.Error("Cannot update ID sequence.", "table", v, "error", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// commonColumns returns columns that a table has in both the staging
+// schema and the schema used by readers.
+func (b builderio) commonColumns(
+	ctx context.Context,
+	table string,
+) (pgx.Identifier, error) {
+	q := `
+SELECT s.column_name
+	FROM information_schema.columns s
+		JOIN information_schema.columns r
+			ON r.table_name = s.table_name AND r.column_name = s.column_name
+	WHERE s.table_schema = $1 AND r.table_schema = $2 AND s.table_name = $3
+	ORDER BY s.ordinal_position`
+	rows, err := b.admin.Query(ctx, q, b.schema, readSchema, table)
+	if err != nil {
+		slog.Error("Cannot get columns.", "table", table, "error", err)
+		return nil, err
+	}
+	res, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		slog.Error("Cannot read columns.", "table", table, "error", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+// validateStaging checks that the given tables of the staging schema have
+// data.
+func (b builderio) validateStaging(
+	ctx context.Context,
+	tables []string,
+) error {
+	slog.Info("Validating the new build.", "schema", b.schema)
+	for _, v := range tables {
+		var ok bool
+		tbl := pgx.Identifier{b.schema, v}.Sanitize()
+		q := "SELECT EXISTS (SELECT 1 FROM " + tbl + ")"
+		err := b.admin.QueryRow(ctx, q).Scan(&ok)
+		if err != nil {
+			slog.Error("Cannot check table.", "table", tbl, "error", err)
+			return err
+		}
+		if !ok {
+			err = fmt.Errorf("table %s is empty", tbl)
+			slog.Error("The new build is incomplete.", "error", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// switchSchema makes the given schema the one used by readers in one
+// transaction. The schema used by readers before becomes the previous
+// build, and the older previous build is removed. Readers do not need a
+// restart, they use the new data with their next query.
+func (b builderio) switchSchema(ctx context.Context, schema string) error {
+	schemas, err := b.schemas(ctx)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(schemas, schema) {
+		err = fmt.Errorf("schema %s does not exist", schema)
+		slog.Error("Cannot switch schema.", "error", err)
+		return err
+	}
+	hasRead := slices.Contains(schemas, readSchema)
+
+	read := pgx.Identifier{readSchema}.Sanitize()
+	prev := pgx.Identifier{prevSchema}.Sanitize()
+	swap := pgx.Identifier{swapSchema}.Sanitize()
+	var qs []string
+	if hasRead {
+		qs = append(qs, "ALTER SCHEMA "+read+" RENAME TO "+swap)
+	}
+	qs = append(qs,
+		"ALTER SCHEMA "+pgx.Identifier{schema}.Sanitize()+" RENAME TO "+read,
+		"DROP SCHEMA IF EXISTS "+prev+" CASCADE",
+	)
+	if hasRead {
+		qs = append(qs, "ALTER SCHEMA "+swap+" RENAME TO "+prev)
+	}
+
+	tx, err := b.admin.Begin(ctx)
+	if err != nil {
+		slog.Error("Cannot start transaction.", "error", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for _, q := range qs {
+		_, err = tx.Exec(ctx, q)
+		if err != nil {
+			slog.Error("Cannot switch schema.", "query", q, "error", err)
+			return err
+		}
+	}
+
+	// the extension moves together with its schema, readers need it in
+	// their schema.
+	var extSchema string
+	q := `
+SELECT n.nspname
+	FROM pg_extension e
+		JOIN pg_namespace n ON n.oid = e.extnamespace
+	WHERE e.extname = 'fuzzystrmatch'`
+	err = tx.QueryRow(ctx, q).Scan(&extSchema)
+	if err != nil && err != pgx.ErrNoRows {
+		slog.Error("Cannot find fuzzystrmatch extension.", "error", err)
+		return err
+	}
+	if err == nil && extSchema != readSchema {
+		q = "ALTER EXTENSION fuzzystrmatch SET SCHEMA " + read
+		_, err = tx.Exec(ctx, q)
+		if err != nil {
+			slog.Error("Cannot move fuzzystrmatch extension.", "error", err)
+			return err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		slog.Error("Cannot commit schema switch.", "error", err)
+		return err
+	}
+	slog.Info("Switched schema used by readers.", "schema", schema)
+	return nil
+}
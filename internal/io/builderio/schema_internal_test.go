@@ -0,0 +1,20 @@
+package builderio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStagingSchema(t *testing.T) {
+	assert := assert.New(t)
+	tm := time.Date(2024, 12, 2, 10, 5, 7, 0, time.UTC)
+	res := stagingSchema(tm)
+	assert.Equal("bhl_20241202100507", res)
+	assert.True(stagingRe.MatchString(res))
+
+	for _, v := range []string{readSchema, prevSchema, swapSchema, "bhl_2024"} {
+		assert.False(stagingRe.MatchString(v), v)
+	}
+}
@@ -17,8 +17,8 @@ func (b builderio) CalculateTxStats() error {
 	var err error
 	var maxID int
 	var itx []txstats.ItemTaxa
-	slog.Info("Truncating item_stats table.")
-	dbio.Truncate(b.db, []string{"item_stats"})
+	slog.Info("Truncating item_stats and item_taxons tables.")
+	dbio.Truncate(b.db, []string{"item_stats", "item_taxons"})
 
 	maxID, err = b.maxItemID()
 	if err != nil {
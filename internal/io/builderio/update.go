@@ -0,0 +1,823 @@
+package builderio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/bits-and-blooms/bloom/v3"
+	"github.com/dustin/go-humanize"
+	"github.com/gnames/bhlnames/internal/ent/model"
+	"github.com/gnames/bhlnames/internal/io/acstorio"
+	"github.com/gnames/bhlnames/internal/io/dbio"
+	"github.com/gnames/bhlnames/internal/io/namesbhlio"
+	"github.com/gnames/bhlnames/internal/io/provio"
+	"github.com/gnames/bhlnames/pkg/ent/builder"
+	"github.com/jackc/pgx/v5"
+)
+
+// updTables contain IDs and kinds of changes of records.
+var updTables = []struct{ table, keyType string }{
+	{"upd_items", "integer"},
+	{"upd_parts", "integer"},
+	{"upd_names", "uuid"},
+}
+
+func (b *builderio) UpdateData() (builder.Report, error) {
+	var res builder.Report
+	ctx := context.Background()
+
+	var hasData bool
+	q := "SELECT to_regclass($1) IS NOT NULL"
+	err := b.admin.QueryRow(ctx, q, pubTable("item_hashes")).Scan(&hasData)
+	if err != nil {
+		slog.Error("Cannot check current build.", "error", err)
+		return res, err
+	}
+	if !hasData {
+		err = errors.New(
+			"there are no hashes of the current data, run full import first",
+		)
+		slog.Error("Cannot update data.", "error", err)
+		return res, err
+	}
+
+	// the update makes sense only with the newest dumps
+	err = b.downloadAndExtract(true)
+	if err != nil {
+		return res, err
+	}
+
+	// changed records go to the staging schema to be applied to the
+	// current data
+	err = b.prepareStaging()
+	if err != nil {
+		return res, err
+	}
+
+	curItems, curParts, err := b.currentHashes(ctx)
+	if err != nil {
+		return res, err
+	}
+
+	// records of the new dumps are hashed and compared with hashes of the
+	// current data. Names are imported, pages and occurrences are only
+	// hashed at this point.
+	titles, items, parts, err := b.readTablesBHL()
+	if err != nil {
+		return res, err
+	}
+	hs := newDumpHashes(items, parts)
+
+	noPages := func(*model.Page) bool { return false }
+	hs.pages, err = b.importPage(noPages)
+	if err != nil {
+		return res, err
+	}
+
+	n := namesbhlio.New(b.cfg, b.db, b.grm)
+	blf, err := n.ImportNames()
+	if err != nil {
+		return res, err
+	}
+	noItems := func(int) bool { return false }
+	hs.occurs, _, err = n.ImportOccurrences(blf, noItems)
+	if err != nil {
+		return res, err
+	}
+
+	err = b.validateStaging(ctx, []string{"name_strings"})
+	if err != nil {
+		return res, err
+	}
+
+	updItems := changes(curItems, hs.itemHashes())
+	updParts := changes(curParts, hs.parts)
+	res, err = b.diff(ctx, updItems, updParts)
+	if err != nil {
+		return res, err
+	}
+
+	if res.IsEmpty() {
+		slog.Info("Nothing changed since the last update.")
+		err = b.saveUpdateInfo(ctx)
+		if err != nil {
+			return res, err
+		}
+		return res, b.dropStaging(ctx)
+	}
+
+	err = b.importChanges(hs, blf, items, parts, updItems, updParts)
+	if err != nil {
+		return res, err
+	}
+
+	_, err = b.checkChanges()
+	if err != nil {
+		return res, err
+	}
+
+	err = b.execAll(ctx, b.affectedQueries())
+	if err != nil {
+		return res, err
+	}
+	q = "SELECT count(*) FROM " + b.stgTable("upd_titles")
+	err = b.admin.QueryRow(ctx, q).Scan(&res.Titles)
+	if err != nil {
+		slog.Error("Cannot count changed titles.", "error", err)
+		return res, err
+	}
+
+	statsIDs, err := b.ids(ctx, "stats_items")
+	if err != nil {
+		return res, err
+	}
+	stats, taxa, err := b.updStats(statsIDs)
+	if err != nil {
+		return res, err
+	}
+	res.ItemStats = len(stats)
+
+	abbrs, err := b.updAbbrs(ctx, titles)
+	if err != nil {
+		return res, err
+	}
+
+	err = b.applyUpdate(ctx, &res, stats, taxa, abbrs)
+	if err != nil {
+		return res, err
+	}
+
+	logReport(res)
+	err = b.saveUpdateInfo(ctx)
+	if err != nil {
+		return res, err
+	}
+	return res, b.dropStaging(ctx)
+}
+
+// importChanges imports added and updated items and parts to the staging
+// schema together with pages and names' occurrences of the items. Pages
+// of changed parts are imported as well, to assign them to the parts.
+func (b *builderio) importChanges(
+	hs dumpHashes,
+	blf *bloom.BloomFilter,
+	items []*model.Item,
+	parts []*model.Part,
+	updItems, updParts map[int]string,
+) error {
+	slog.Info("Importing changed records.")
+	kept := func(upd map[int]string, id int) bool {
+		kind, ok := upd[id]
+		return ok && kind != kindDeleted
+	}
+
+	var itemIDs, partIDs []int
+	var newItems []*model.Item
+	for _, v := range items {
+		if kept(updItems, int(v.ID)) {
+			newItems = append(newItems, v)
+			itemIDs = append(itemIDs, int(v.ID))
+		}
+	}
+
+	pageItems := make(map[int]struct{})
+	partPages := make(map[int]struct{})
+	var newParts []*model.Part
+	for _, v := range parts {
+		if !kept(updParts, int(v.ID)) {
+			continue
+		}
+		newParts = append(newParts, v)
+		partIDs = append(partIDs, int(v.ID))
+		if v.ItemID.Valid {
+			pageItems[int(v.ItemID.Int32)] = struct{}{}
+		}
+		if v.PageID.Valid {
+			partPages[int(v.PageID.Int32)] = struct{}{}
+		}
+	}
+
+	err := b.importItems(newItems)
+	if err != nil {
+		return err
+	}
+
+	err = b.importParts(newParts)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.importPage(func(p *model.Page) bool {
+		if _, ok := partPages[int(p.ID)]; ok {
+			return true
+		}
+		_, ok := pageItems[int(p.ItemID)]
+		return ok || kept(updItems, int(p.ItemID))
+	})
+	if err != nil {
+		return err
+	}
+
+	n := namesbhlio.New(b.cfg, b.db, b.grm)
+	_, b.ignoredOccurs, err = n.ImportOccurrences(blf, func(id int) bool {
+		return kept(updItems, id)
+	})
+	if err != nil {
+		return err
+	}
+
+	return b.saveHashes(hs, itemIDs, partIDs)
+}
+
+// saveUpdateInfo records the datasets and the update in the schema used
+// by readers. Provenance tables are created if the data were built by an
+// older version.
+func (b builderio) saveUpdateInfo(ctx context.Context) error {
+	grm, err := dbio.NewGORM(b.cfg)
+	if err != nil {
+		return err
+	}
+	if db, err := grm.DB(); err == nil {
+		defer db.Close()
+	}
+
+	err = model.MigrateInfo(grm)
+	if err != nil {
+		slog.Error("Cannot create provenance tables.", "error", err)
+		return err
+	}
+
+	err = provio.SaveSources(ctx, b.admin, b.sources...)
+	if err != nil {
+		return err
+	}
+	return provio.SaveBuild(ctx, b.admin, provio.KindUpdate, b.start)
+}
+
+// diff saves IDs of changed items and parts to the staging schema. It
+// compares name-strings of the staging schema with the ones used by
+// readers and saves IDs of changed names.
+func (b builderio) diff(
+	ctx context.Context,
+	updItems, updParts map[int]string,
+) (builder.Report, error) {
+	var res builder.Report
+	slog.Info("Comparing new data with the current one.")
+
+	for _, v := range updTables {
+		q := fmt.Sprintf(
+			"CREATE TABLE %s (id %s PRIMARY KEY, kind varchar(10) NOT NULL)",
+			b.stgTable(v.table), v.keyType,
+		)
+		_, err := b.admin.Exec(ctx, q)
+		if err != nil {
+			slog.Error("Cannot create table.", "table", v.table, "error", err)
+			return res, err
+		}
+	}
+
+	for _, v := range []struct {
+		table string
+		upd   map[int]string
+	}{
+		{"upd_items", updItems},
+		{"upd_parts", updParts},
+	} {
+		rows := make([][]any, 0, len(v.upd))
+		for id, kind := range v.upd {
+			rows = append(rows, []any{id, kind})
+		}
+		_, err := b.admin.CopyFrom(
+			ctx,
+			pgx.Identifier{b.schema, v.table},
+			[]string{"id", "kind"},
+			pgx.CopyFromRows(rows),
+		)
+		if err != nil {
+			slog.Error("Cannot insert rows.", "table", v.table, "error", err)
+			return res, err
+		}
+	}
+
+	cols, err := b.commonColumns(ctx, "name_strings")
+	if err != nil {
+		return res, err
+	}
+	cols = slices.DeleteFunc(cols, func(s string) bool { return s == "id" })
+	qs := diffQueries(
+		b.stgTable("upd_names"), b.stgTable("name_strings"),
+		pubTable("name_strings"), cols,
+	)
+	err = b.execAll(ctx, qs)
+	if err != nil {
+		return res, err
+	}
+
+	for _, v := range []struct {
+		table string
+		chng  *builder.Change
+	}{
+		{"upd_items", &res.Items},
+		{"upd_parts", &res.Parts},
+		{"upd_names", &res.Names},
+	} {
+		*v.chng, err = b.change(ctx, v.table)
+		if err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// diffQueries create queries that save IDs of added, deleted and updated
+// records to the upd table. The newTbl contains new data, the oldTbl
+// contains the current data, cols are compared columns besides ID.
+func diffQueries(upd, newTbl, oldTbl string, cols []string) []string {
+	return []string{
+		fmt.Sprintf(`
+INSERT INTO %s (id, kind)
+  SELECT n.id, 'added'
+    FROM %s n
+    WHERE NOT EXISTS (SELECT 1 FROM %s o WHERE o.id = n.id)`,
+			upd, newTbl, oldTbl),
+		fmt.Sprintf(`
+INSERT INTO %s (id, kind)
+  SELECT o.id, 'deleted'
+    FROM %s o
+    WHERE NOT EXISTS (SELECT 1 FROM %s n WHERE n.id = o.id)`,
+			upd, oldTbl, newTbl),
+		fmt.Sprintf(`
+INSERT INTO %s (id, kind)
+  SELECT n.id, 'updated'
+    FROM %s n
+      JOIN %s o ON o.id = n.id
+    WHERE (%s) IS DISTINCT FROM (%s)`,
+			upd, newTbl, oldTbl, prefixed("n", cols), prefixed("o", cols)),
+	}
+}
+
+// affectedQueries create tables with titles, items and their pages that
+// need recalculation of derived data. Staging tables contain only changed
+// records, unchanged ones are in the tables used by readers.
+func (b builderio) affectedQueries() []string {
+	updItems := b.stgTable("upd_items")
+	statsItems := b.stgTable("stats_items")
+	return []string{
+		// titles with changed abbreviations, a title changes if a pair of
+		// its ID and name appears or disappears.
+		fmt.Sprintf(`CREATE TABLE %s (id integer PRIMARY KEY)`,
+			b.stgTable("upd_titles")),
+		fmt.Sprintf(`
+INSERT INTO %[1]s (id)
+  SELECT n.title_id
+    FROM %[2]s n
+      JOIN %[3]s u ON u.id = n.id
+    WHERE NOT EXISTS (
+      SELECT 1 FROM %[4]s o
+        WHERE o.title_id = n.title_id AND o.title_name = n.title_name
+    )
+  UNION
+  SELECT o.title_id
+    FROM %[4]s o
+      JOIN %[3]s u ON u.id = o.id
+    WHERE NOT EXISTS (
+      SELECT 1 FROM %[2]s n
+        WHERE n.title_id = o.title_id AND n.title_name = o.title_name
+    )
+    AND NOT EXISTS (
+      SELECT 1 FROM %[4]s o2
+        WHERE o2.title_id = o.title_id AND o2.title_name = o.title_name
+          AND NOT EXISTS (SELECT 1 FROM %[3]s u2 WHERE u2.id = o2.id)
+    )`,
+			b.stgTable("upd_titles"), b.stgTable("items"), updItems,
+			pubTable("items")),
+
+		// items with changed taxonomic statistics
+		fmt.Sprintf(`CREATE TABLE %s (id integer PRIMARY KEY)`, statsItems),
+		fmt.Sprintf(`
+INSERT INTO %s (id)
+  SELECT id FROM %s WHERE kind <> 'deleted'`,
+			statsItems, updItems),
+		fmt.Sprintf(`
+INSERT INTO %s (id)
+  SELECT DISTINCT pg.item_id
+    FROM %s u
+      JOIN %s n ON n.id = u.id
+      JOIN %s o ON o.id = u.id
+      JOIN %s oc ON oc.name_string_id = u.id
+      JOIN %s pg ON pg.id = oc.page_id
+    WHERE u.kind = 'updated'
+      AND (n.classification, n.classification_ranks, n.classification_ids)
+        IS DISTINCT FROM
+        (o.classification, o.classification_ranks, o.classification_ids)
+  ON CONFLICT (id) DO NOTHING`,
+			statsItems, b.stgTable("upd_names"),
+			b.stgTable("name_strings"), pubTable("name_strings"),
+			pubTable("name_occurrences"), pubTable("pages")),
+
+		// unchanged items with changed statistics need their data in the
+		// staging schema for the calculation.
+		fmt.Sprintf(`
+INSERT INTO %s (%s)
+  SELECT %s
+    FROM %s i
+      JOIN %s s ON s.id = i.id
+    WHERE NOT EXISTS (SELECT 1 FROM %s u WHERE u.id = s.id)`,
+			b.stgTable("items"), strings.Join(itemColumns, ", "),
+			prefixed("i", itemColumns), pubTable("items"), statsItems, updItems),
+		fmt.Sprintf(`
+INSERT INTO %s (%s)
+  SELECT %s
+    FROM %s pg
+      JOIN %s s ON s.id = pg.item_id
+    WHERE NOT EXISTS (SELECT 1 FROM %s u WHERE u.id = s.id)
+  ON CONFLICT DO NOTHING`,
+			b.stgTable("pages"), strings.Join(pageColumns, ", "),
+			prefixed("pg", pageColumns), pubTable("pages"), statsItems, updItems),
+		fmt.Sprintf(`
+INSERT INTO %s (page_id, name_string_id, offset_start, offset_end,
+    odds_log10, annot_nomen)
+  SELECT oc.page_id, oc.name_string_id, oc.offset_start, oc.offset_end,
+      oc.odds_log10, oc.annot_nomen
+    FROM %s oc
+      JOIN %s pg ON pg.id = oc.page_id
+      JOIN %s s ON s.id = pg.item_id
+    WHERE NOT EXISTS (SELECT 1 FROM %s u WHERE u.id = s.id)`,
+			b.stgTable("name_occurrences"), pubTable("name_occurrences"),
+			pubTable("pages"), statsItems, updItems),
+
+		// items that need new assignment of pages to parts
+		fmt.Sprintf(`CREATE TABLE %s (id integer PRIMARY KEY)`,
+			b.stgTable("pp_items")),
+		fmt.Sprintf(`
+INSERT INTO %s (id)
+  SELECT id FROM %s WHERE kind <> 'deleted'
+  UNION
+  SELECT pg.item_id
+    FROM %s u
+      JOIN %s pt ON pt.id = u.id
+      JOIN %s pg ON pg.id = pt.page_id
+  UNION
+  SELECT pg.item_id
+    FROM %s u
+      JOIN %s pt ON pt.id = u.id
+      JOIN %s pg ON pg.id = pt.page_id`,
+			b.stgTable("pp_items"), updItems,
+			b.stgTable("upd_parts"), b.stgTable("parts"), b.stgTable("pages"),
+			b.stgTable("upd_parts"), pubTable("parts"), pubTable("pages")),
+	}
+}
+
+// change counts added, updated and deleted records.
+func (b builderio) change(
+	ctx context.Context,
+	table string,
+) (builder.Change, error) {
+	var res builder.Change
+	q := "SELECT kind, count(*) FROM " + b.stgTable(table) + " GROUP BY kind"
+	rows, err := b.admin.Query(ctx, q)
+	if err != nil {
+		slog.Error("Cannot count changes.", "table", table, "error", err)
+		return res, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var kind string
+		var num int
+		err = rows.Scan(&kind, &num)
+		if err != nil {
+			slog.Error("Cannot read changes.", "table", table, "error", err)
+			return res, err
+		}
+		switch kind {
+		case "added":
+			res.Added = num
+		case "updated":
+			res.Updated = num
+		case "deleted":
+			res.Deleted = num
+		}
+	}
+	return res, rows.Err()
+}
+
+func (b builderio) ids(ctx context.Context, table string) ([]int, error) {
+	rows, err := b.admin.Query(ctx, "SELECT id FROM "+b.stgTable(table))
+	if err != nil {
+		slog.Error("Cannot get IDs.", "table", table, "error", err)
+		return nil, err
+	}
+	res, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		slog.Error("Cannot read IDs.", "table", table, "error", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+// updStats calculates rows of item_stats and item_taxons tables for
+// items from the new data.
+func (b builderio) updStats(ids []int) ([][]any, [][]any, error) {
+	slog.Info(
+		"Calculating taxonomic statistics for changed items.",
+		"items-num", humanize.Comma(int64(len(ids))),
+	)
+	var stats, taxa [][]any
+	limit := 5000
+	for i := 0; i < len(ids); i += limit {
+		itx, err := b.getItemsTaxaByIDs(ids[i:min(i+limit, len(ids))])
+		if err != nil {
+			return nil, nil, err
+		}
+		rows, taxonRows := statsRows(itx)
+		stats = append(stats, rows...)
+		taxa = append(taxa, taxonRows...)
+	}
+	return stats, taxa, nil
+}
+
+// updAbbrs returns abbreviations of changed titles that still have items.
+func (b builderio) updAbbrs(
+	ctx context.Context,
+	titles map[int]*model.Title,
+) (map[string][]int, error) {
+	q := fmt.Sprintf(`
+SELECT t.id
+  FROM %[1]s t
+  WHERE EXISTS (
+    SELECT 1 FROM %[2]s i JOIN %[3]s u ON u.id = i.id
+      WHERE i.title_id = t.id
+  )
+  OR EXISTS (
+    SELECT 1 FROM %[4]s i
+      WHERE i.title_id = t.id
+        AND NOT EXISTS (SELECT 1 FROM %[3]s u WHERE u.id = i.id)
+  )`,
+		b.stgTable("upd_titles"), b.stgTable("items"), b.stgTable("upd_items"),
+		pubTable("items"))
+	rows, err := b.admin.Query(ctx, q)
+	if err != nil {
+		slog.Error("Cannot get changed titles.", "error", err)
+		return nil, err
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		slog.Error("Cannot read changed titles.", "error", err)
+		return nil, err
+	}
+
+	updTitles := make(map[int]*model.Title)
+	for _, id := range ids {
+		if t, ok := titles[id]; ok {
+			updTitles[id] = t
+		}
+	}
+
+	ac, err := acstorio.New(b.cfg, b.db, updTitles)
+	if err != nil {
+		return nil, err
+	}
+	return ac.Abbrs()
+}
+
+// applyUpdate changes the data used by readers in one transaction, so
+// readers see either the old or the new data.
+func (b builderio) applyUpdate(
+	ctx context.Context,
+	res *builder.Report,
+	stats, taxa [][]any,
+	abbrs map[string][]int,
+) error {
+	slog.Info("Applying changes to the current data.")
+	cols := make(map[string]string)
+	rawCols := make(map[string]string)
+	for _, v := range slices.Concat(dumpTables, hashTables) {
+		c, err := b.commonColumns(ctx, v)
+		if err != nil {
+			return err
+		}
+		cols[v] = prefixed("n", c)
+		rawCols[v] = c.Sanitize()
+	}
+
+	tx, err := b.admin.Begin(ctx)
+	if err != nil {
+		slog.Error("Cannot start transaction.", "error", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	updItems := b.stgTable("upd_items")
+	allStats := fmt.Sprintf("SELECT id FROM %s UNION SELECT id FROM %s",
+		updItems, b.stgTable("stats_items"))
+	ppPages := fmt.Sprintf(`
+SELECT pg.id
+  FROM %s pg
+  WHERE pg.item_id IN (SELECT id FROM %s UNION SELECT id FROM %s)`,
+		pubTable("pages"), b.stgTable("pp_items"), updItems,
+	)
+
+	type query struct {
+		q    string
+		rows *int
+	}
+	upsert := func(tbl, upd string) []query {
+		return []query{
+			{q: fmt.Sprintf("DELETE FROM %s t USING %s u WHERE t.id = u.id",
+				pubTable(tbl), b.stgTable(upd))},
+			{q: fmt.Sprintf(`
+INSERT INTO %s (%s)
+  SELECT %s
+    FROM %s n
+      JOIN %s u ON u.id = n.id
+    WHERE u.kind <> 'deleted'`,
+				pubTable(tbl), rawCols[tbl], cols[tbl], b.stgTable(tbl),
+				b.stgTable(upd))},
+		}
+	}
+
+	var qs []query
+	qs = append(qs, []query{
+		{q: fmt.Sprintf(`
+DELETE FROM %s o
+  USING %s pg, %s u
+  WHERE o.page_id = pg.id AND pg.item_id = u.id`,
+			pubTable("name_occurrences"), pubTable("pages"), updItems),
+			rows: &res.Occurrences.Deleted},
+		{q: fmt.Sprintf("DELETE FROM %s WHERE page_id IN (%s)",
+			pubTable("page_parts"), ppPages)},
+		{q: fmt.Sprintf("DELETE FROM %s pg USING %s u WHERE pg.item_id = u.id",
+			pubTable("pages"), updItems),
+			rows: &res.Pages.Deleted},
+		{q: fmt.Sprintf("DELETE FROM %s WHERE id IN (%s)",
+			pubTable("item_stats"), allStats)},
+		{q: fmt.Sprintf("DELETE FROM %s WHERE item_id IN (%s)",
+			pubTable("item_taxons"), allStats)},
+	}...)
+	qs = append(qs, upsert("items", "upd_items")...)
+	qs = append(qs, []query{
+		{q: fmt.Sprintf(`
+INSERT INTO %s (%s)
+  SELECT %s
+    FROM %s n
+      JOIN %s u ON u.id = n.item_id
+    WHERE u.kind <> 'deleted'`,
+			pubTable("pages"), rawCols["pages"], cols["pages"],
+			b.stgTable("pages"), updItems),
+			rows: &res.Pages.Added},
+		{q: fmt.Sprintf(`
+INSERT INTO %s (%s)
+  SELECT %s
+    FROM %s n
+      JOIN %s pg ON pg.id = n.page_id
+      JOIN %s u ON u.id = pg.item_id
+    WHERE u.kind <> 'deleted'`,
+			pubTable("name_occurrences"), rawCols["name_occurrences"],
+			cols["name_occurrences"], b.stgTable("name_occurrences"),
+			b.stgTable("pages"), updItems),
+			rows: &res.Occurrences.Added},
+		{q: fmt.Sprintf(
+			"DELETE FROM %s pp USING %s u WHERE pp.part_id = u.id",
+			pubTable("page_parts"), b.stgTable("upd_parts"))},
+	}...)
+	qs = append(qs, upsert("parts", "upd_parts")...)
+	qs = append(qs, upsert("name_strings", "upd_names")...)
+	qs = append(qs, upsert("item_hashes", "upd_items")...)
+	qs = append(qs, upsert("part_hashes", "upd_parts")...)
+	qs = append(qs, []query{
+		{q: fmt.Sprintf(`
+INSERT INTO %s (page_id, part_id)
+  WITH subq AS (
+    SELECT pt.id as part_id, pt.length, pg.item_id, pg.sequence_order
+      FROM %s pt
+        JOIN %s pg ON pt.page_id = pg.id
+        JOIN %s u ON u.id = pg.item_id
+  )
+  SELECT DISTINCT pg2.id, subq.part_id
+    FROM %s pg2
+      JOIN subq ON pg2.item_id = subq.item_id
+    WHERE pg2.sequence_order >= subq.sequence_order
+      AND pg2.sequence_order < subq.sequence_order + subq.length + 1
+  ON CONFLICT DO NOTHING`,
+			pubTable("page_parts"), pubTable("parts"), pubTable("pages"),
+			b.stgTable("pp_items"), pubTable("pages"))},
+		{q: fmt.Sprintf("DELETE FROM %s a USING %s u WHERE a.title_id = u.id",
+			pubTable("abbr_titles"), b.stgTable("upd_titles"))},
+	}...)
+
+	for _, v := range qs {
+		tag, err := tx.Exec(ctx, v.q)
+		if err != nil {
+			slog.Error("Cannot apply changes.", "query", v.q, "error", err)
+			return err
+		}
+		if v.rows != nil {
+			*v.rows = int(tag.RowsAffected())
+		}
+	}
+
+	abbrTitles := make([][]any, 0, len(abbrs))
+	abbrList := make([]string, 0, len(abbrs))
+	for k, v := range abbrs {
+		abbrList = append(abbrList, k)
+		for i := range v {
+			abbrTitles = append(abbrTitles, []any{k, v[i]})
+		}
+	}
+
+	for _, v := range []struct {
+		table   string
+		columns []string
+		rows    [][]any
+	}{
+		{"item_stats", statsColumns, stats},
+		{"item_taxons", taxonColumns, taxa},
+		{"abbr_titles", []string{"abbr", "title_id"}, abbrTitles},
+	} {
+		_, err = tx.CopyFrom(
+			ctx,
+			pgx.Identifier{readSchema, v.table},
+			v.columns,
+			pgx.CopyFromRows(v.rows),
+		)
+		if err != nil {
+			slog.Error("Cannot insert rows.", "table", v.table, "error", err)
+			return err
+		}
+	}
+
+	abbrQs := []string{
+		fmt.Sprintf(`
+INSERT INTO %s (abbr)
+  SELECT unnest($1::text[])
+  ON CONFLICT DO NOTHING`, pubTable("abbrs")),
+		fmt.Sprintf(`
+DELETE FROM %s a
+  WHERE NOT EXISTS (SELECT 1 FROM %s t WHERE t.abbr = a.abbr)`,
+			pubTable("abbrs"), pubTable("abbr_titles")),
+	}
+	_, err = tx.Exec(ctx, abbrQs[0], abbrList)
+	if err == nil {
+		_, err = tx.Exec(ctx, abbrQs[1])
+	}
+	if err != nil {
+		slog.Error("Cannot update abbreviations.", "error", err)
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		slog.Error("Cannot commit changes.", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (b builderio) execAll(ctx context.Context, qs []string) error {
+	for _, q := range qs {
+		_, err := b.admin.Exec(ctx, q)
+		if err != nil {
+			slog.Error("Cannot run query.", "query", q, "error", err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (b builderio) stgTable(tbl string) string {
+	return pgx.Identifier{b.schema, tbl}.Sanitize()
+}
+
+func pubTable(tbl string) string {
+	return pgx.Identifier{readSchema, tbl}.Sanitize()
+}
+
+// prefixed returns a list of columns with a table alias.
+func prefixed(alias string, cols []string) string {
+	res := make([]string, len(cols))
+	for i, v := range cols {
+		res[i] = alias + "." + pgx.Identifier{v}.Sanitize()
+	}
+	return strings.Join(res, ", ")
+}
+
+func logReport(r builder.Report) {
+	slog.Info("Updated data.",
+		"items-added", r.Items.Added,
+		"items-updated", r.Items.Updated,
+		"items-deleted", r.Items.Deleted,
+		"parts-added", r.Parts.Added,
+		"parts-updated", r.Parts.Updated,
+		"parts-deleted", r.Parts.Deleted,
+		"names-added", r.Names.Added,
+		"names-updated", r.Names.Updated,
+		"names-deleted", r.Names.Deleted,
+		"item-stats", r.ItemStats,
+		"titles", r.Titles,
+	)
+}
@@ -0,0 +1,42 @@
+package builderio
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffQueries(t *testing.T) {
+	assert := assert.New(t)
+	qs := diffQueries(`"bhl_1"."upd_parts"`, `"bhl_1"."parts"`,
+		`"public"."parts"`, []string{"title", "page_id"})
+	assert.Len(qs, 3)
+
+	tests := []struct {
+		msg string
+		q   string
+		has []string
+	}{
+		{"added", qs[0], []string{
+			`INSERT INTO "bhl_1"."upd_parts" (id, kind)`,
+			`SELECT n.id, 'added'`,
+			`FROM "bhl_1"."parts" n`,
+			`NOT EXISTS (SELECT 1 FROM "public"."parts" o WHERE o.id = n.id)`,
+		}},
+		{"deleted", qs[1], []string{
+			`SELECT o.id, 'deleted'`,
+			`FROM "public"."parts" o`,
+			`NOT EXISTS (SELECT 1 FROM "bhl_1"."parts" n WHERE n.id = o.id)`,
+		}},
+		{"updated", qs[2], []string{
+			`SELECT n.id, 'updated'`,
+			`JOIN "public"."parts" o ON o.id = n.id`,
+			`(n."title", n."page_id") IS DISTINCT FROM (o."title", o."page_id")`,
+		}},
+	}
+	for _, v := range tests {
+		for _, s := range v.has {
+			assert.Contains(v.q, s, v.msg)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+// package cacheio provides implementations of the results cache: an
+// in-memory LRU cache and a persistent cache on a local disk.
+package cacheio
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/gnames/bhlnames/pkg/config"
+	"github.com/gnames/bhlnames/pkg/ent/cache"
+)
+
+// New creates a cache according to the configuration. It returns nil if
+// the cache is disabled.
+func New(cfg config.Config) (cache.Cache, error) {
+	ttl := time.Duration(cfg.CacheTTL) * time.Second
+	switch cfg.CacheType {
+	case "", "none":
+		return nil, nil
+	case "memory":
+		slog.Info("Using in-memory cache", "size", cfg.CacheSize, "ttl", ttl)
+		return NewLRU(cfg.CacheSize, ttl), nil
+	case "disk":
+		slog.Info("Using disk cache", "dir", cfg.CacheDir, "ttl", ttl)
+		return NewDisk(cfg.CacheDir, ttl)
+	default:
+		err := fmt.Errorf("unknown cache type '%s'", cfg.CacheType)
+		slog.Error("Cannot create cache", "error", err)
+		return nil, err
+	}
+}
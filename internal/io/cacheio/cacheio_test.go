@@ -0,0 +1,109 @@
+package cacheio_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gnames/bhlnames/internal/io/cacheio"
+	"github.com/gnames/bhlnames/pkg/config"
+	"github.com/gnames/bhlnames/pkg/ent/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		msg, tp, stats string
+		isNil, isErr   bool
+	}{
+		{"none", "none", "", true, false},
+		{"memory", "memory", "memory", false, false},
+		{"disk", "disk", "disk", false, false},
+		{"bad", "redis", "", true, true},
+	}
+	for _, v := range tests {
+		cfg := config.New(
+			config.OptRootDir(t.TempDir()),
+			config.OptCacheType(v.tp),
+		)
+		c, err := cacheio.New(cfg)
+		assert.Equal(v.isErr, err != nil, v.msg)
+		assert.Equal(v.isNil, c == nil, v.msg)
+		if c != nil {
+			assert.Equal(v.stats, c.Stats().Type, v.msg)
+		}
+	}
+}
+
+func TestCache(t *testing.T) {
+	assert := assert.New(t)
+	dc, err := cacheio.NewDisk(t.TempDir(), 0)
+	assert.Nil(err)
+	caches := []cache.Cache{cacheio.NewLRU(10, 0), dc}
+
+	for _, c := range caches {
+		msg := c.Stats().Type
+		_, ok := c.Get("abcdef")
+		assert.False(ok, msg)
+
+		err = c.Set("abcdef", []byte("val1"))
+		assert.Nil(err, msg)
+		val, ok := c.Get("abcdef")
+		assert.True(ok, msg)
+		assert.Equal("val1", string(val), msg)
+
+		err = c.Set("abcdef", []byte("val2"))
+		assert.Nil(err, msg)
+		val, _ = c.Get("abcdef")
+		assert.Equal("val2", string(val), msg)
+
+		assert.Equal(cache.Stats{Type: msg, Hits: 2, Misses: 1}, c.Stats(), msg)
+
+		err = c.Clear()
+		assert.Nil(err, msg)
+		_, ok = c.Get("abcdef")
+		assert.False(ok, msg)
+		assert.Nil(c.Close(), msg)
+	}
+}
+
+func TestTTL(t *testing.T) {
+	assert := assert.New(t)
+	ttl := 50 * time.Millisecond
+	dc, err := cacheio.NewDisk(t.TempDir(), ttl)
+	assert.Nil(err)
+	caches := []cache.Cache{cacheio.NewLRU(10, ttl), dc}
+
+	for _, c := range caches {
+		msg := c.Stats().Type
+		err = c.Set("abcdef", []byte("val"))
+		assert.Nil(err, msg)
+		_, ok := c.Get("abcdef")
+		assert.True(ok, msg)
+	}
+
+	time.Sleep(2 * ttl)
+	for _, c := range caches {
+		_, ok := c.Get("abcdef")
+		assert.False(ok, c.Stats().Type)
+	}
+}
+
+func TestLRUEviction(t *testing.T) {
+	assert := assert.New(t)
+	c := cacheio.NewLRU(2, 0)
+	_ = c.Set("a", []byte("1"))
+	_ = c.Set("b", []byte("2"))
+
+	// "a" becomes the most recently used
+	_, ok := c.Get("a")
+	assert.True(ok)
+
+	_ = c.Set("c", []byte("3"))
+	_, ok = c.Get("b")
+	assert.False(ok)
+	_, ok = c.Get("a")
+	assert.True(ok)
+	_, ok = c.Get("c")
+	assert.True(ok)
+}
@@ -0,0 +1,123 @@
+package cacheio
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/gnames/bhlnames/pkg/ent/cache"
+	"github.com/gnames/gnsys"
+)
+
+// headerSize is the size of the expiration time saved in the beginning
+// of every cache file.
+const headerSize = 8
+
+// disk is a persistent cache that keeps every value in a separate file.
+// Files are distributed among subdirectories by the first two characters
+// of the key.
+type disk struct {
+	dir string
+	ttl time.Duration
+
+	hits, misses atomic.Uint64
+}
+
+// NewDisk creates a persistent cache in the given directory. Values expire
+// after ttl, zero ttl means values never expire.
+func NewDisk(dir string, ttl time.Duration) (cache.Cache, error) {
+	err := gnsys.MakeDir(dir)
+	if err != nil {
+		err = fmt.Errorf("cacheio.NewDisk: %w", err)
+		slog.Error("Cannot create cache directory", "dir", dir, "error", err)
+		return nil, err
+	}
+	return &disk{dir: dir, ttl: ttl}, nil
+}
+
+func (d *disk) Get(key string) ([]byte, bool) {
+	path := d.path(key)
+	bs, err := os.ReadFile(path)
+	if err != nil || len(bs) < headerSize {
+		d.misses.Add(1)
+		return nil, false
+	}
+
+	expires := int64(binary.BigEndian.Uint64(bs[:headerSize]))
+	if expires > 0 && time.Now().UnixNano() > expires {
+		_ = os.Remove(path)
+		d.misses.Add(1)
+		return nil, false
+	}
+
+	d.hits.Add(1)
+	return bs[headerSize:], true
+}
+
+func (d *disk) Set(key string, val []byte) error {
+	if len(key) < 3 {
+		return errors.New("cache key is too short")
+	}
+
+	var expires int64
+	if d.ttl > 0 {
+		expires = time.Now().Add(d.ttl).UnixNano()
+	}
+	bs := make([]byte, headerSize, headerSize+len(val))
+	binary.BigEndian.PutUint64(bs, uint64(expires))
+	bs = append(bs, val...)
+
+	path := d.path(key)
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first, so readers never see a partial value.
+	tmp, err := os.CreateTemp(filepath.Dir(path), key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(bs)
+	if errClose := tmp.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (d *disk) Clear() error {
+	slog.Info("Clearing disk cache", "dir", d.dir)
+	err := os.RemoveAll(d.dir)
+	if err != nil {
+		return err
+	}
+	return gnsys.MakeDir(d.dir)
+}
+
+func (d *disk) Stats() cache.Stats {
+	return cache.Stats{
+		Type:   "disk",
+		Hits:   d.hits.Load(),
+		Misses: d.misses.Load(),
+	}
+}
+
+func (d *disk) Close() error {
+	return nil
+}
+
+func (d *disk) path(key string) string {
+	if len(key) < 3 {
+		return filepath.Join(d.dir, key)
+	}
+	return filepath.Join(d.dir, key[:2], key[2:])
+}
@@ -0,0 +1,113 @@
+package cacheio
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gnames/bhlnames/pkg/ent/cache"
+)
+
+// lru is a thread-safe in-memory cache that removes the least recently
+// used values when it is full.
+type lru struct {
+	mu    sync.Mutex
+	size  int
+	ttl   time.Duration
+	ll    *list.List
+	items map[string]*list.Element
+
+	hits, misses atomic.Uint64
+}
+
+type entry struct {
+	key     string
+	val     []byte
+	expires time.Time
+}
+
+// NewLRU creates an in-memory cache that keeps at most size values.
+// Values expire after ttl, zero ttl means values never expire.
+func NewLRU(size int, ttl time.Duration) cache.Cache {
+	if size < 1 {
+		size = 1
+	}
+	return &lru{
+		size:  size,
+		ttl:   ttl,
+		ll:    list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+func (l *lru) Get(key string) ([]byte, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	el, ok := l.items[key]
+	if !ok {
+		l.misses.Add(1)
+		return nil, false
+	}
+
+	e := el.Value.(*entry)
+	if !e.expires.IsZero() && time.Now().After(e.expires) {
+		l.remove(el)
+		l.misses.Add(1)
+		return nil, false
+	}
+
+	l.ll.MoveToFront(el)
+	l.hits.Add(1)
+	return e.val, true
+}
+
+func (l *lru) Set(key string, val []byte) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var expires time.Time
+	if l.ttl > 0 {
+		expires = time.Now().Add(l.ttl)
+	}
+
+	if el, ok := l.items[key]; ok {
+		e := el.Value.(*entry)
+		e.val, e.expires = val, expires
+		l.ll.MoveToFront(el)
+		return nil
+	}
+
+	el := l.ll.PushFront(&entry{key: key, val: val, expires: expires})
+	l.items[key] = el
+	for l.ll.Len() > l.size {
+		l.remove(l.ll.Back())
+	}
+	return nil
+}
+
+func (l *lru) Clear() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.ll.Init()
+	l.items = make(map[string]*list.Element)
+	return nil
+}
+
+func (l *lru) Stats() cache.Stats {
+	return cache.Stats{
+		Type:   "memory",
+		Hits:   l.hits.Load(),
+		Misses: l.misses.Load(),
+	}
+}
+
+func (l *lru) Close() error {
+	return l.Clear()
+}
+
+func (l *lru) remove(el *list.Element) {
+	l.ll.Remove(el)
+	delete(l.items, el.Value.(*entry).key)
+}
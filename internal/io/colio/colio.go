@@ -1,13 +1,18 @@
 package colio
 
 import (
+	"context"
 	"log/slog"
 	"path/filepath"
+	"time"
+
+	"github.com/gnames/bhlnames/internal/ent/model"
 
-	"github.com/gnames/bhlnames/internal/ent/col"
 	"github.com/gnames/bhlnames/internal/io/bhlsys"
 	"github.com/gnames/bhlnames/internal/io/dbio"
+	"github.com/gnames/bhlnames/internal/io/provio"
 	"github.com/gnames/bhlnames/pkg/config"
+	"github.com/gnames/bhlnames/pkg/ent/col"
 	"github.com/gnames/gnparser"
 	"github.com/gnames/gnsys"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -19,6 +24,9 @@ type colio struct {
 	db  *pgxpool.Pool
 	grm *gorm.DB
 
+	// start is the time when the CoL import started.
+	start time.Time
+
 	gnpPool chan gnparser.GNparser
 
 	recordsNum  int
@@ -44,6 +52,7 @@ func New(cfg config.Config) (col.Nomen, error) {
 		db:      db,
 		grm:     grm,
 		gnpPool: gnpPool,
+		start:   time.Now(),
 	}
 	return &res, nil
 }
@@ -87,11 +96,17 @@ func (c *colio) ResetCoLData() error {
 func (c *colio) ImportCoLData() error {
 	var err error
 	slog.Info("Downloading CoL DwCA data.")
-	err = bhlsys.Download(c.cfg.DownloadCoLFile, c.cfg.CoLDataURL, false)
+	err = bhlsys.Download(
+		c.cfg.DownloadCoLFile, c.cfg.CoLDataURL, c.cfg.CoLDataSHA256, false,
+	)
 	if err != nil {
 		slog.Error("Cannot download CoL data", "error", err)
 		return err
 	}
+	src, err := provio.Source("col", c.cfg.CoLDataURL, c.cfg.DownloadCoLFile)
+	if err != nil {
+		return err
+	}
 
 	err = bhlsys.Extract(c.cfg.DownloadCoLFile, c.cfg.ExtractDir, false)
 	if err != nil {
@@ -104,7 +119,13 @@ func (c *colio) ImportCoLData() error {
 		slog.Error("Cannot import CoL data", "error", err)
 		return err
 	}
-	return nil
+
+	err = model.MigrateInfo(c.grm)
+	if err != nil {
+		slog.Error("Cannot create provenance tables", "error", err)
+		return err
+	}
+	return provio.SaveSources(context.Background(), c.db, src)
 }
 
 // Close releases all resources (e.g., database connections) used by the
@@ -9,9 +9,9 @@ import (
 	"strings"
 
 	"github.com/dustin/go-humanize"
-	"github.com/gnames/bhlnames/internal/ent/bhl"
 	"github.com/gnames/bhlnames/internal/ent/model"
 	"github.com/gnames/bhlnames/internal/io/dbio"
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
 	"github.com/gnames/gnfmt"
 	"github.com/gnames/gnparser"
 	"github.com/jackc/pgx/v5"
@@ -10,8 +10,10 @@ import (
 	"time"
 
 	"github.com/dustin/go-humanize"
-	"github.com/gnames/bhlnames/internal/ent/bhl"
-	"github.com/gnames/bhlnames/internal/ent/input"
+	"github.com/gnames/bhlnames/internal/ent/model"
+	"github.com/gnames/bhlnames/internal/io/provio"
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/input"
 	"github.com/gnames/gnfmt"
 	"golang.org/x/sync/errgroup"
 )
@@ -57,16 +59,20 @@ func (c *colio) NomenEvents(
 	g, ctx := errgroup.WithContext(ctx)
 
 	g.Go(func() error {
-		nomenRef(ctx, chIn, chOut)
-		return nil
+		return nomenRef(ctx, chIn, chOut)
 	})
 
 	// save references
 	var count int
 	g.Go(func() error {
 		for nrs := range chOut {
+			// a failed record would be saved as a record without references,
+			// stop instead, so the record is processed again on the next run.
+			if nrs.Error != "" {
+				return fmt.Errorf("NomenEvents %s: %s", nrs.Input.ID, nrs.Error)
+			}
 			count++
-			err = c.saveColBhlRefs(nrs)
+			err := c.saveColBhlRefs(nrs)
 			if err != nil {
 				err = fmt.Errorf("SaveColBhlNomen: %w", err)
 				return err
@@ -79,13 +85,15 @@ func (c *colio) NomenEvents(
 		return nil
 	})
 
-	// load input data
-	err = c.inputFromCol(chIn)
-	if err != nil {
-		slog.Error("Cannot generate input from CoL data.", "error", err)
-		return err
-	}
+	// load input data, stops if processing of the data was aborted.
+	errIn := c.inputFromCol(ctx, chIn)
 	close(chIn)
+	if errIn != nil && ctx.Err() == nil {
+		slog.Error("Cannot generate input from CoL data.", "error", errIn)
+		cancel()
+		_ = g.Wait()
+		return errIn
+	}
 
 	if err = g.Wait(); err != nil {
 		return err
@@ -97,7 +105,14 @@ func (c *colio) NomenEvents(
 	dur := float64(time.Since(start)) / float64(time.Hour)
 	durStr := fmt.Sprintf("%0.2f", dur)
 	slog.Info("Stats", "records-num", count, "hours", durStr)
-	return nil
+
+	// the build is recorded when CoL data are linked to BHL.
+	err = model.MigrateInfo(c.grm)
+	if err != nil {
+		slog.Error("Cannot create provenance tables", "error", err)
+		return err
+	}
+	return provio.SaveBuild(context.Background(), c.db, provio.KindCoL, c.start)
 }
 
 func (c colio) recNumToPcent(recNum int) float64 {
@@ -123,7 +138,10 @@ func (c colio) progressOutput(start time.Time, recsNum int) {
 	}
 }
 
-func (c colio) inputFromCol(chIn chan<- input.Input) error {
+func (c colio) inputFromCol(
+	ctx context.Context,
+	chIn chan<- input.Input,
+) error {
 	slog.Info("Finding nomenclatural events for names from the Catalogue of Life.")
 
 	gnp := <-c.gnpPool
@@ -149,7 +167,11 @@ func (c colio) inputFromCol(chIn chan<- input.Input) error {
 				input.OptRefString(cnr[i].Ref),
 				input.OptWithNomenEvent(true),
 			}
-			chIn <- input.New(c.gnpPool, opts...)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case chIn <- input.New(c.gnpPool, opts...):
+			}
 		}
 	}
 	return nil
@@ -4,25 +4,93 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net"
+	"net/url"
+	"strconv"
 
 	"github.com/gnames/bhlnames/pkg/config"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// dburl returns the connection string of the database. DbDSN, if given,
+// overrides all other connection settings.
 func dburl(cfg config.Config) string {
-	return fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=disable",
-		cfg.DbUser, cfg.DbPass, cfg.DbHost, 5432, cfg.DbDatabase)
+	if cfg.DbDSN != "" {
+		return cfg.DbDSN
+	}
+
+	q := url.Values{}
+	if cfg.DbSSLMode != "" {
+		q.Set("sslmode", cfg.DbSSLMode)
+	}
+	if cfg.DbSSLRootCert != "" {
+		q.Set("sslrootcert", cfg.DbSSLRootCert)
+	}
+
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(cfg.DbUser, cfg.DbPass),
+		Host:     net.JoinHostPort(cfg.DbHost, strconv.Itoa(cfg.DbPort)),
+		Path:     cfg.DbDatabase,
+		RawQuery: q.Encode(),
+	}
+	return u.String()
+}
+
+// connConfig parses the connection string and adds settings that are
+// applied to every connection. If schema is not empty, it becomes the
+// only schema in the search path of connections.
+func connConfig(cfg config.Config, schema string) (*pgx.ConnConfig, error) {
+	res, err := pgx.ParseConfig(dburl(cfg))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.DbStatementTimeout > 0 {
+		ms := strconv.Itoa(cfg.DbStatementTimeout * 1000)
+		res.RuntimeParams["statement_timeout"] = ms
+	}
+	if schema != "" {
+		res.RuntimeParams["search_path"] = pgx.Identifier{schema}.Sanitize()
+	}
+	return res, nil
 }
 
 // NewDB creates a new connections pool to the database.
 func NewDB(cfg config.Config) (*pgxpool.Pool, error) {
-	pool, err := pgxpool.New(
-		context.Background(),
-		dburl(cfg),
-	)
+	return newDB(cfg, "")
+}
+
+// NewSchemaDB creates a new connections pool that reads and writes tables
+// of the given schema.
+func NewSchemaDB(cfg config.Config, schema string) (*pgxpool.Pool, error) {
+	return newDB(cfg, schema)
+}
+
+func newDB(cfg config.Config, schema string) (*pgxpool.Pool, error) {
+	poolCfg, err := pgxpool.ParseConfig(dburl(cfg))
+	if err != nil {
+		slog.Error("Cannot parse database connection settings.", "error", err)
+		return nil, err
+	}
+
+	connCfg, err := connConfig(cfg, schema)
+	if err != nil {
+		return nil, err
+	}
+	poolCfg.ConnConfig = connCfg
+
+	if cfg.DbMaxConns > 0 {
+		poolCfg.MaxConns = int32(cfg.DbMaxConns)
+	}
+	if cfg.DbMinConns > 0 {
+		poolCfg.MinConns = int32(cfg.DbMinConns)
+	}
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolCfg)
 	if err != nil {
 		return nil, err
 	}
@@ -32,7 +100,34 @@ func NewDB(cfg config.Config) (*pgxpool.Pool, error) {
 
 // NewGORM creates a new GORM connection to the database.
 func NewGORM(cfg config.Config) (*gorm.DB, error) {
-	db, err := gorm.Open(postgres.Open(dburl(cfg)), &gorm.Config{})
+	return newGORM(cfg, "")
+}
+
+// NewSchemaGORM creates a new GORM connection that reads and writes tables
+// of the given schema.
+func NewSchemaGORM(cfg config.Config, schema string) (*gorm.DB, error) {
+	return newGORM(cfg, schema)
+}
+
+func newGORM(cfg config.Config, schema string) (*gorm.DB, error) {
+	connCfg, err := connConfig(cfg, schema)
+	if err != nil {
+		slog.Error("Cannot parse database connection settings.", "error", err)
+		return nil, err
+	}
+
+	sqlDB := stdlib.OpenDB(*connCfg)
+	if cfg.DbMaxConns > 0 {
+		sqlDB.SetMaxOpenConns(cfg.DbMaxConns)
+	}
+	if cfg.DbMinConns > 0 {
+		sqlDB.SetMaxIdleConns(cfg.DbMinConns)
+	}
+
+	db, err := gorm.Open(
+		postgres.New(postgres.Config{Conn: sqlDB}),
+		&gorm.Config{},
+	)
 	if err != nil {
 		slog.Error("Cannot setup GORM connection.", "error", err)
 		return nil, err
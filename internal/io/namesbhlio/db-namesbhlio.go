@@ -11,6 +11,7 @@ import (
 	"github.com/bits-and-blooms/bloom/v3"
 	"github.com/dustin/go-humanize"
 	"github.com/gnames/bhlnames/internal/io/dbio"
+	"github.com/gnames/gnparser"
 )
 
 const (
@@ -49,11 +50,12 @@ func (n namesbhlio) saveNames(
 	total := 0
 	columns := []string{"id", "name", "record_id", "match_type",
 		"match_sort_order", "edit_distance", "stem_edit_distance", "matched_name",
-		"matched_canonical", "current_name", "current_canonical", "classification",
+		"matched_canonical", "matched_canonical_stem", "current_name", "current_canonical", "classification",
 		"classification_ranks", "classification_ids", "data_source_id",
 		"data_source_title", "data_sources_number", "curation", "occurences",
 		"odds_log10", "error"}
 
+	gnp := gnparser.New(gnparser.NewConfig())
 	for names := range ch {
 		total += len(names)
 
@@ -99,7 +101,8 @@ func (n namesbhlio) saveNames(
 
 			row := []any{v[NameIDF], v[DetectedNameF], v[RecordIDF],
 				v[MatchTypeF], matchSort, eDist, stemDist, v[MatchedFullNameF],
-				v[MatchedCanonicalF], v[CurrentFullNameF], v[CurrentCanonicalF],
+				v[MatchedCanonicalF], stem(gnp, v[MatchedCanonicalF]),
+				v[CurrentFullNameF], v[CurrentCanonicalF],
 				v[ClassificationF], v[ClassificationRanksF], v[ClassificationIDsF],
 				dsID, v[DataSourceF], dsNum, true, occurs, odds, v[ErrorF]}
 			rows = append(rows, row)
@@ -124,3 +127,15 @@ func (n namesbhlio) saveNames(
 	slog.Info("Imported names to db", "records-num", humanize.Comma(int64(total)))
 	return nil
 }
+
+// stem returns the stemmed canonical form of a name.
+func stem(gnp gnparser.GNparser, canonical string) string {
+	if canonical == "" {
+		return ""
+	}
+	p := gnp.ParseName(canonical)
+	if !p.Parsed || p.Canonical == nil {
+		return ""
+	}
+	return p.Canonical.Stemmed
+}
@@ -7,7 +7,6 @@ import (
 	"os"
 	"strings"
 
-	"github.com/bits-and-blooms/bloom/v3"
 	"github.com/dustin/go-humanize"
 	"github.com/gnames/bhlnames/internal/ent/model"
 	"github.com/gnames/bhlnames/internal/io/dbio"
@@ -16,26 +15,18 @@ import (
 func (n namesbhlio) saveOcurrences(
 	ctx context.Context,
 	chIn <-chan []model.NameOccurrence,
-	blf *bloom.BloomFilter,
 ) error {
 	var i int
 	columns := []string{"page_id", "name_string_id", "offset_start",
 		"offset_end", "odds_log10", "annot_nomen"}
-	var count, missing int
+	var count int
 	for ocs := range chIn {
-		rows := make([][]any, 0, len(ocs))
+		rows := make([][]any, len(ocs))
 		for i = range ocs {
-			// do not save occurrences for which there is no verified name saved
-			if !blf.Test([]byte(ocs[i].NameStringID)) {
-				missing++
-				continue
-			}
-
 			row := []any{ocs[i].PageID, ocs[i].NameStringID,
 				ocs[i].OffsetStart, ocs[i].OffsetEnd, ocs[i].OddsLog10,
 				ocs[i].AnnotNomen}
-
-			rows = append(rows, row)
+			rows[i] = row
 		}
 
 		_, err := dbio.InsertRows(n.db, "name_occurrences", columns, rows)
@@ -47,6 +38,8 @@ func (n namesbhlio) saveOcurrences(
 		}
 		select {
 		case <-ctx.Done():
+			for range chIn {
+			}
 			return ctx.Err()
 		default:
 			count += len(rows)
@@ -57,7 +50,6 @@ func (n namesbhlio) saveOcurrences(
 	fmt.Fprintf(os.Stderr, "\r%s\r", strings.Repeat(" ", 47))
 	slog.Info("Imported name occurrences.",
 		"records-num", humanize.Comma(int64(count)),
-		"ignored-num", humanize.Comma(int64(missing)),
 	)
 
 	return nil
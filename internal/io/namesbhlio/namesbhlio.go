@@ -3,6 +3,7 @@ package namesbhlio
 import (
 	"context"
 	"encoding/csv"
+	"hash/fnv"
 	"io"
 	"log/slog"
 	"os"
@@ -10,6 +11,7 @@ import (
 	"strconv"
 
 	"github.com/bits-and-blooms/bloom/v3"
+	"github.com/dustin/go-humanize"
 	"github.com/gnames/bhlnames/internal/ent/model"
 	"github.com/gnames/bhlnames/internal/ent/namebhl"
 	"github.com/gnames/bhlnames/internal/io/dbio"
@@ -36,13 +38,20 @@ func New(cfg config.Config, db *pgxpool.Pool, gormdb *gorm.DB) namebhl.NameBHL {
 }
 
 // ImportOccurrences transfers occurrences data from bhlindex's
-// occurrences.csv dump file to the database.
-func (n namesbhlio) ImportOccurrences(blf *bloom.BloomFilter) error {
+// occurrences.csv dump file to the database. Only occurrences of items
+// accepted by keep are imported, all of them are imported if keep is nil.
+// It returns hashes of imported occurrences of every item, and the number
+// of occurrences of kept items that were ignored because their names were
+// not imported.
+func (n namesbhlio) ImportOccurrences(
+	blf *bloom.BloomFilter,
+	keep func(itemID int) bool,
+) (map[int]uint64, int, error) {
 	slog.Info("Importing names' occurrences.")
 	slog.Info("Truncating data from name_occurrences table.")
 	err := dbio.Truncate(n.db, []string{"name_occurrences"})
 	if err != nil {
-		return err
+		return nil, 0, err
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -52,27 +61,39 @@ func (n namesbhlio) ImportOccurrences(blf *bloom.BloomFilter) error {
 	chOccur := make(chan []model.NameOccurrence)
 
 	g.Go(func() error {
-		return n.saveOcurrences(ctx, chOccur, blf)
+		return n.saveOcurrences(ctx, chOccur)
 	})
 
-	err = n.loadOccurrences(chOccur)
+	hashes, missing, err := n.loadOccurrences(chOccur, blf, keep)
+	close(chOccur)
 	if err != nil {
-		return err
+		g.Wait()
+		return nil, 0, err
 	}
-	close(chOccur)
 
 	err = g.Wait()
 	if err != nil {
-		return err
+		return nil, 0, err
 	}
-	return nil
+	slog.Info("Ignored occurrences of not imported names.",
+		"records-num", humanize.Comma(int64(missing)),
+	)
+	return hashes, missing, nil
 }
 
-func (n namesbhlio) loadOccurrences(chIn chan<- []model.NameOccurrence) error {
+// loadOccurrences reads occurrences.csv and sends occurrences of kept
+// items to the channel. Occurrences which names are not imported are
+// skipped. It returns hashes of the rest of occurrences by item IDs and
+// the number of skipped occurrences of kept items.
+func (n namesbhlio) loadOccurrences(
+	chIn chan<- []model.NameOccurrence,
+	blf *bloom.BloomFilter,
+	keep func(itemID int) bool,
+) (map[int]uint64, int, error) {
 	path := filepath.Join(n.cfg.ExtractDir, "occurrences.csv")
 	f, err := os.Open(path)
 	if err != nil {
-		return err
+		return nil, 0, err
 	}
 	defer f.Close()
 	r := csv.NewReader(f)
@@ -80,13 +101,15 @@ func (n namesbhlio) loadOccurrences(chIn chan<- []model.NameOccurrence) error {
 	_, err = r.Read()
 	if err != nil {
 		slog.Error("Could not read header of occurrences.csv.", "error", err)
-		return err
+		return nil, 0, err
 	}
 
 	chunk := make([][]string, occurBatchSize)
-	var count int
+	hashes := make(map[int]uint64)
+	var count, missing int
 
 	var row []string
+	var itemID int
 	var occurs []model.NameOccurrence
 	for {
 		row, err = r.Read()
@@ -95,12 +118,34 @@ func (n namesbhlio) loadOccurrences(chIn chan<- []model.NameOccurrence) error {
 		}
 		if err != nil {
 			slog.Error("Could not read a row from occurrences.csv.", "error", err)
-			return err
+			return nil, 0, err
+		}
+
+		itemID, err = strconv.Atoi(row[occItemIDF])
+		if err != nil {
+			slog.Error("Could not convert item_id to int.", "item_id", row[occItemIDF])
+			return nil, 0, err
+		}
+		kept := keep == nil || keep(itemID)
+
+		// do not save occurrences for which there is no verified name saved
+		if !blf.Test([]byte(row[occNameIDF])) {
+			if kept {
+				missing++
+			}
+			continue
+		}
+
+		// the sum does not depend on the order of rows
+		hashes[itemID] += occurHash(row)
+		if !kept {
+			continue
 		}
+
 		if count == occurBatchSize {
 			occurs, err = convertToOccurs(chunk)
 			if err != nil {
-				return err
+				return nil, 0, err
 			}
 			chIn <- occurs
 			chunk = make([][]string, occurBatchSize)
@@ -111,10 +156,20 @@ func (n namesbhlio) loadOccurrences(chIn chan<- []model.NameOccurrence) error {
 	}
 	occurs, err = convertToOccurs(chunk[0:count])
 	if err != nil {
-		return err
+		return nil, 0, err
 	}
 	chIn <- occurs
-	return nil
+	return hashes, missing, nil
+}
+
+// occurHash returns a hash of a row of occurrences.csv.
+func occurHash(row []string) uint64 {
+	h := fnv.New64a()
+	for _, v := range row {
+		h.Write([]byte(v))
+		h.Write([]byte{0})
+	}
+	return h.Sum64()
 }
 
 const (
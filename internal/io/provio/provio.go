@@ -0,0 +1,133 @@
+// Package provio records provenance of the data: datasets the data were
+// built from and builds that created or changed the data.
+package provio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/gnames/bhlnames/internal/ent/model"
+	"github.com/gnames/bhlnames/internal/io/bhlsys"
+	bhlnames "github.com/gnames/bhlnames/pkg"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Kinds of builds.
+const (
+	KindInit   = "init"
+	KindUpdate = "update"
+	KindCoL    = "col"
+)
+
+// Tables are counted after every build.
+var Tables = []string{
+	"items", "item_stats", "item_taxons", "pages", "parts", "page_parts",
+	"name_strings", "name_occurrences", "abbrs", "abbr_titles",
+	"col_names", "col_bhl_refs", "col_bhl_results",
+}
+
+// Source returns provenance of a dataset downloaded from url to path.
+func Source(name, url, path string) (model.DataSource, error) {
+	res := model.DataSource{Name: name, URL: url}
+	arc, err := bhlsys.Info(path)
+	if err != nil {
+		slog.Error("Cannot read archive info.", "file", path, "error", err)
+		return res, err
+	}
+	res.FileSize = arc.Size
+	res.SHA256 = arc.SHA256
+	res.DumpDate = arc.DumpDate
+	return res, nil
+}
+
+// SaveSources records datasets in the data_sources table of the search
+// path of the db. Records with the same names are replaced.
+func SaveSources(
+	ctx context.Context,
+	db *pgxpool.Pool,
+	srcs ...model.DataSource,
+) error {
+	q := `
+INSERT INTO data_sources
+  (name, url, file_size, sha256, dump_date, imported_at)
+  VALUES ($1, $2, $3, $4, $5, $6)
+  ON CONFLICT (name) DO UPDATE
+    SET url = EXCLUDED.url, file_size = EXCLUDED.file_size,
+      sha256 = EXCLUDED.sha256, dump_date = EXCLUDED.dump_date,
+      imported_at = EXCLUDED.imported_at`
+	now := time.Now().UTC()
+	for _, v := range srcs {
+		_, err := db.Exec(
+			ctx, q, v.Name, v.URL, v.FileSize, v.SHA256, v.DumpDate.UTC(), now,
+		)
+		if err != nil {
+			slog.Error("Cannot save data source.", "source", v.Name, "error", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// SaveBuild counts rows of Tables and records a build that started at
+// the given time in the builds table of the search path of the db.
+func SaveBuild(
+	ctx context.Context,
+	db *pgxpool.Pool,
+	kind string,
+	start time.Time,
+) error {
+	slog.Info("Counting rows of tables.", "build", kind)
+	rows, err := CountRows(ctx, db)
+	if err != nil {
+		return err
+	}
+	tableRows, err := json.Marshal(rows)
+	if err != nil {
+		return err
+	}
+
+	q := `
+INSERT INTO builds (kind, version, started_at, duration, table_rows)
+  VALUES ($1, $2, $3, $4, $5)`
+	dur := int(time.Since(start).Seconds())
+	_, err = db.Exec(
+		ctx, q, kind, bhlnames.Version, start.UTC(), dur, tableRows,
+	)
+	if err != nil {
+		slog.Error("Cannot save build.", "build", kind, "error", err)
+		return err
+	}
+	return nil
+}
+
+// CountRows returns numbers of rows of Tables that exist in the search
+// path of the db.
+func CountRows(ctx context.Context, db *pgxpool.Pool) (map[string]int64, error) {
+	res := make(map[string]int64)
+	for _, v := range Tables {
+		var exists bool
+		q := "SELECT to_regclass($1) IS NOT NULL"
+		err := db.QueryRow(ctx, q, v).Scan(&exists)
+		if err != nil {
+			slog.Error("Cannot check table.", "table", v, "error", err)
+			return nil, err
+		}
+		if !exists {
+			continue
+		}
+
+		var num int64
+		q = fmt.Sprintf("SELECT count(*) FROM %s", pgx.Identifier{v}.Sanitize())
+		err = db.QueryRow(ctx, q).Scan(&num)
+		if err != nil {
+			slog.Error("Cannot count rows.", "table", v, "error", err)
+			return nil, err
+		}
+		res[v] = num
+	}
+	return res, nil
+}
@@ -0,0 +1,298 @@
+package reffndio
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/gnames/bhlnames/internal/ent/model"
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/input"
+)
+
+// citationRefsLimit is the maximum number of candidate pages for a
+// citation.
+const citationRefsLimit = 100
+
+// referencesByCitation finds candidate pages for a reference in items of
+// the given titles. Items are selected by volume or year of the reference,
+// pages by their page numbers or by page ranges of parts. Only one page is
+// returned for each item.
+func (rf reffndio) referencesByCitation(
+	ctx context.Context,
+	ref input.Reference,
+	titleIDs []int,
+) ([]*bhl.ReferenceName, error) {
+	res := make([]*bhl.ReferenceName, 0)
+	if len(titleIDs) == 0 {
+		return res, nil
+	}
+
+	q, args := citationQuery(ref, titleIDs)
+	recs, _, err := rf.citationRecs(ctx, q, args, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// parts that cover pages of the citation, keys are item IDs.
+	var rangeParts map[int]*model.Part
+	if ref.PageStart > 0 {
+		var partRecs []*refRec
+		q, args = citationPartsQuery(ref, titleIDs)
+		partRecs, rangeParts, err = rf.citationRecs(ctx, q, args, true)
+		if err != nil {
+			return nil, err
+		}
+		recs = mergeCitationRecs(recs, partRecs)
+	}
+
+	pageIDs := make([]int, len(recs))
+	for i := range recs {
+		pageIDs[i] = recs[i].pageID
+	}
+	pageParts, err := rf.partsByPageIDs(ctx, pageIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	preRefs := make([]*preReference, len(recs))
+	for i, v := range recs {
+		part, ok := pageParts[v.pageID]
+		if !ok {
+			part = rangeParts[v.itemID]
+		}
+		preRefs[i] = &preReference{item: v, part: part}
+	}
+
+	res = rf.getReferences(preRefs, false)
+	return res, nil
+}
+
+// citationRecs runs a citation query and reads its rows. If withPart is
+// true, rows also contain parts, they are returned by item IDs.
+func (rf reffndio) citationRecs(
+	ctx context.Context,
+	q string,
+	args []any,
+	withPart bool,
+) ([]*refRec, map[int]*model.Part, error) {
+	ctx, cancel := rf.queryCtx(ctx)
+	defer cancel()
+	rows, err := rf.db.Query(ctx, q, args...)
+	if err != nil {
+		err = dbError("citation references are not found", err)
+		slog.Error("Cannot run citation query", "error", err)
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var recs []*refRec
+	parts := make(map[int]*model.Part)
+	for rows.Next() {
+		var rr refRec
+		var part model.Part
+		var matchNum int
+		fields := []any{&rr.itemID, &rr.titleID, &rr.pageID, &rr.pageNum,
+			&rr.titleYearStart, &rr.titleYearEnd, &rr.yearStart,
+			&rr.yearEnd, &rr.titleName, &rr.volume, &rr.titleDOI,
+			&rr.mainTaxon, &rr.mainKingdom, &rr.mainKingdomPercent,
+			&rr.namesTotal,
+		}
+		if withPart {
+			fields = append(fields, &part.ID, &part.Title, &part.DOI,
+				&part.PageNumStart, &part.PageNumEnd, &part.Year)
+		}
+		fields = append(fields, &matchNum)
+		if err = rows.Scan(fields...); err != nil {
+			err = fmt.Errorf("reffinderio.citationRecs: %w", err)
+			slog.Error("Cannot scan row", "error", err)
+			return nil, nil, err
+		}
+		recs = append(recs, &rr)
+		if withPart {
+			parts[rr.itemID] = &part
+		}
+	}
+	if err = rows.Err(); err != nil {
+		err = dbError("citation references are not found", err)
+		slog.Error("Cannot read citation references", "error", err)
+		return nil, nil, err
+	}
+	return recs, parts, nil
+}
+
+// partsByPageIDs returns parts of pages, keys are page IDs. If a page
+// belongs to several parts, the part with the largest ID is used.
+func (rf reffndio) partsByPageIDs(
+	ctx context.Context,
+	pageIDs []int,
+) (map[int]*model.Part, error) {
+	res := make(map[int]*model.Part)
+	if len(pageIDs) == 0 {
+		return res, nil
+	}
+
+	q := `SELECT
+  pp.page_id, p.id, p.title, p.doi, p.page_num_start, p.page_num_end, p.year
+	FROM parts p
+	JOIN page_parts pp
+		ON p.id = pp.part_id
+	WHERE pp.page_id = ANY($1)
+	ORDER BY pp.page_id, p.id`
+
+	ctx, cancel := rf.queryCtx(ctx)
+	defer cancel()
+	rows, err := rf.db.Query(ctx, q, pageIDs)
+	if err != nil {
+		err = dbError("parts are not found", err)
+		slog.Error("Cannot run parts query", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var pageID int
+		var part model.Part
+		err = rows.Scan(&pageID, &part.ID, &part.Title, &part.DOI,
+			&part.PageNumStart, &part.PageNumEnd, &part.Year)
+		if err != nil {
+			err = fmt.Errorf("reffinderio.partsByPageIDs: %w", err)
+			slog.Error("Cannot scan row", "error", err)
+			return nil, err
+		}
+		res[pageID] = &part
+	}
+	if err = rows.Err(); err != nil {
+		err = dbError("parts are not found", err)
+		slog.Error("Cannot read parts", "error", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+// mergeCitationRecs adds candidates found by parts to candidates found by
+// page numbers. Items that are already found by page numbers keep their
+// pages.
+func mergeCitationRecs(recs, partRecs []*refRec) []*refRec {
+	items := make(map[int]struct{}, len(recs))
+	for _, v := range recs {
+		items[v.itemID] = struct{}{}
+	}
+	for _, v := range partRecs {
+		if _, ok := items[v.itemID]; ok {
+			continue
+		}
+		items[v.itemID] = struct{}{}
+		recs = append(recs, v)
+	}
+	return recs
+}
+
+// citationItems creates conditions for items of a citation and an SQL
+// expression that counts how many of volume and year match an item. If
+// both volume and year are known, an item needs to match only one of
+// them, because volumes and years of BHL items are often incomplete.
+func citationItems(
+	ref input.Reference,
+	add func(string, any) string,
+) (string, string) {
+	var conds []string
+	if ref.Volume > 0 {
+		conds = append(conds,
+			add("itm.vol ~ $%d", fmt.Sprintf(`(^|\D)%d(\D|$)`, ref.Volume)))
+	}
+	if ref.RefYearStart > 0 {
+		conds = append(conds, add(`$%d BETWEEN
+      coalesce(itm.year_start, itm.title_year_start) - 1
+      AND coalesce(itm.year_end, itm.year_start, itm.title_year_end,
+        itm.title_year_start) + 1`, ref.RefYearStart))
+	}
+	if len(conds) == 0 {
+		return "", "0"
+	}
+
+	nums := make([]string, len(conds))
+	for i, v := range conds {
+		nums[i] = "coalesce((" + v + ")::int, 0)"
+	}
+	where := "\n    AND (" + strings.Join(conds, "\n    OR ") + ")"
+	return where, strings.Join(nums, " + ")
+}
+
+// citationFields are item and page columns of citation queries, they
+// are read by citationRecs.
+const citationFields = `
+  itm.id AS item_id, itm.title_id, pg.id AS page_id, pg.page_num,
+  itm.title_year_start, itm.title_year_end, itm.year_start, itm.year_end,
+  itm.title_name, itm.vol, itm.title_doi, ist.main_taxon, ist.main_kingdom,
+  ist.main_kingdom_percent, ist.names_total`
+
+// citationQuery creates a query for pages of a citation. Items that match
+// both volume and year go first, so the limit does not cut them off.
+// Scoring of the results decides which match is the best.
+func citationQuery(ref input.Reference, titleIDs []int) (string, []any) {
+	args := []any{titleIDs}
+	add := func(cond string, arg any) string {
+		args = append(args, arg)
+		return fmt.Sprintf(cond, len(args))
+	}
+
+	where, matchNum := citationItems(ref, add)
+
+	order := "itm.id, pg.sequence_order"
+	if ref.PageStart > 0 {
+		pageEnd := max(ref.PageEnd, ref.PageStart)
+		where += add("\n    AND pg.page_num BETWEEN $%d", ref.PageStart)
+		where += add(" AND $%d", pageEnd)
+		order = "itm.id, pg.page_num, pg.sequence_order"
+	}
+
+	q := `SELECT * FROM (
+  SELECT DISTINCT ON (itm.id)` + citationFields + `,
+    ` + matchNum + ` AS match_num
+	FROM items itm
+		JOIN item_stats ist ON itm.id = ist.id
+		JOIN pages pg ON pg.item_id = itm.id
+	WHERE itm.title_id = ANY($1)` + where + `
+	ORDER BY ` + order + `
+) cand
+ORDER BY match_num DESC, item_id
+LIMIT ` + fmt.Sprint(citationRefsLimit)
+	return q, args
+}
+
+// citationPartsQuery creates a query for parts which page ranges overlap
+// with pages of a citation. The first page of a part is used as a page of
+// its item. If several parts of an item overlap with the citation, the
+// part that starts last is used.
+func citationPartsQuery(ref input.Reference, titleIDs []int) (string, []any) {
+	args := []any{titleIDs}
+	add := func(cond string, arg any) string {
+		args = append(args, arg)
+		return fmt.Sprintf(cond, len(args))
+	}
+
+	where, matchNum := citationItems(ref, add)
+	pageEnd := max(ref.PageEnd, ref.PageStart)
+	where += add("\n    AND p.page_num_start <= $%d", pageEnd)
+	where += add(
+		"\n    AND coalesce(p.page_num_end, p.page_num_start) >= $%d",
+		ref.PageStart,
+	)
+
+	q := `SELECT * FROM (
+  SELECT DISTINCT ON (itm.id)` + citationFields + `,
+    p.id AS part_id, p.title, p.doi, p.page_num_start, p.page_num_end,
+    p.year, ` + matchNum + ` AS match_num
+	FROM items itm
+		JOIN item_stats ist ON itm.id = ist.id
+		JOIN parts p ON p.item_id = itm.id
+		JOIN pages pg ON pg.id = p.page_id
+	WHERE itm.title_id = ANY($1)` + where + `
+	ORDER BY itm.id, p.page_num_start DESC, p.id
+) cand
+ORDER BY match_num DESC, item_id
+LIMIT ` + fmt.Sprint(citationRefsLimit)
+	return q, args
+}
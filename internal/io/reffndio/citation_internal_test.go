@@ -0,0 +1,99 @@
+package reffndio
+
+import (
+	"testing"
+
+	"github.com/gnames/bhlnames/pkg/ent/input"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCitationQuery(t *testing.T) {
+	assert := assert.New(t)
+	ids := []int{7928}
+	tests := []struct {
+		msg   string
+		ref   input.Reference
+		has   []string
+		hasNo []string
+		args  []any
+	}{
+		{
+			"title only",
+			input.Reference{},
+			[]string{
+				"WHERE itm.title_id = ANY($1)",
+				"ORDER BY itm.id, pg.sequence_order",
+				"0 AS match_num",
+				"ORDER BY match_num DESC, item_id",
+				"LIMIT 100",
+			},
+			[]string{"itm.vol ~", "pg.page_num BETWEEN"},
+			[]any{ids},
+		},
+		{
+			"volume, year and pages",
+			input.Reference{Volume: 34, RefYearStart: 2008, PageStart: 50},
+			[]string{
+				"AND (itm.vol ~ $2",
+				"OR $3 BETWEEN",
+				"AND pg.page_num BETWEEN $4 AND $5",
+				"ORDER BY itm.id, pg.page_num, pg.sequence_order",
+				"coalesce((itm.vol ~ $2)::int, 0) + coalesce(($3 BETWEEN",
+			},
+			nil,
+			[]any{ids, `(^|\D)34(\D|$)`, 2008, 50, 50},
+		},
+		{
+			"page range",
+			input.Reference{PageStart: 50, PageEnd: 51},
+			[]string{"AND pg.page_num BETWEEN $2 AND $3"},
+			[]string{"itm.vol ~"},
+			[]any{ids, 50, 51},
+		},
+	}
+
+	for _, v := range tests {
+		q, args := citationQuery(v.ref, ids)
+		for _, s := range v.has {
+			assert.Contains(q, s, v.msg)
+		}
+		for _, s := range v.hasNo {
+			assert.NotContains(q, s, v.msg)
+		}
+		assert.Equal(v.args, args, v.msg)
+	}
+}
+
+func TestCitationPartsQuery(t *testing.T) {
+	assert := assert.New(t)
+	ids := []int{7928}
+	ref := input.Reference{RefYearStart: 1893, PageStart: 188, PageEnd: 189}
+	q, args := citationPartsQuery(ref, ids)
+	for _, s := range []string{
+		"JOIN parts p ON p.item_id = itm.id",
+		"JOIN pages pg ON pg.id = p.page_id",
+		"AND ($2 BETWEEN",
+		"AND p.page_num_start <= $3",
+		"AND coalesce(p.page_num_end, p.page_num_start) >= $4",
+		"ORDER BY match_num DESC, item_id",
+	} {
+		assert.Contains(q, s)
+	}
+	assert.Equal([]any{ids, 1893, 189, 188}, args)
+
+	_, args = citationPartsQuery(input.Reference{PageStart: 188}, ids)
+	assert.Equal([]any{ids, 188, 188}, args)
+}
+
+func TestMergeCitationRecs(t *testing.T) {
+	assert := assert.New(t)
+	recs := []*refRec{{itemID: 1, pageID: 10}, {itemID: 2, pageID: 20}}
+	partRecs := []*refRec{{itemID: 2, pageID: 21}, {itemID: 3, pageID: 30}}
+	res := mergeCitationRecs(recs, partRecs)
+
+	pages := make([]int, len(res))
+	for i := range res {
+		pages[i] = res[i].pageID
+	}
+	assert.Equal([]int{10, 20, 30}, pages)
+}
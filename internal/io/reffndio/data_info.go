@@ -0,0 +1,107 @@
+package reffndio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/reffnd"
+)
+
+// buildsLimit is the maximum number of the latest builds in data info.
+const buildsLimit = 20
+
+// dataInfo returns datasets and the latest builds of the data.
+func (rf reffndio) dataInfo(ctx context.Context) (*bhl.DataInfo, error) {
+	ctx, cancel := rf.queryCtx(ctx)
+	defer cancel()
+
+	var exists bool
+	q := "SELECT to_regclass('data_sources') IS NOT NULL"
+	err := rf.db.QueryRow(ctx, q).Scan(&exists)
+	if err != nil {
+		err = dbError("data info is not found", err)
+		slog.Error("Cannot check data info", "error", err)
+		return nil, err
+	}
+	if !exists {
+		msg := "data info is not found, the data were built by an older version"
+		return nil, reffnd.NewError(reffnd.ErrNotFound, msg, nil)
+	}
+
+	res := bhl.DataInfo{
+		Sources: []*bhl.DataSource{},
+		Builds:  []*bhl.Build{},
+	}
+
+	q = `SELECT name, url, file_size, sha256, dump_date, imported_at
+  FROM data_sources
+  ORDER BY name`
+	rows, err := rf.db.Query(ctx, q)
+	if err != nil {
+		err = dbError("data sources are not found", err)
+		slog.Error("Cannot run data sources query", "error", err)
+		return nil, err
+	}
+	for rows.Next() {
+		var ds bhl.DataSource
+		err = rows.Scan(
+			&ds.Name, &ds.URL, &ds.FileSize, &ds.SHA256, &ds.DumpDate,
+			&ds.ImportedAt,
+		)
+		if err != nil {
+			rows.Close()
+			err = fmt.Errorf("reffinderio.dataInfo: %w", err)
+			slog.Error("Cannot scan row", "error", err)
+			return nil, err
+		}
+		res.Sources = append(res.Sources, &ds)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		err = dbError("data sources are not found", err)
+		slog.Error("Cannot read data sources", "error", err)
+		return nil, err
+	}
+
+	q = `SELECT kind, version, started_at, duration, table_rows
+  FROM builds
+  ORDER BY started_at DESC, id DESC
+  LIMIT $1`
+	rows, err = rf.db.Query(ctx, q, buildsLimit)
+	if err != nil {
+		err = dbError("builds are not found", err)
+		slog.Error("Cannot run builds query", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var b bhl.Build
+		var tableRows []byte
+		err = rows.Scan(
+			&b.Kind, &b.Version, &b.StartedAt, &b.Duration, &tableRows,
+		)
+		if err != nil {
+			err = fmt.Errorf("reffinderio.dataInfo: %w", err)
+			slog.Error("Cannot scan row", "error", err)
+			return nil, err
+		}
+		if len(tableRows) > 0 {
+			err = json.Unmarshal(tableRows, &b.TableRows)
+			if err != nil {
+				slog.Error("Cannot decode table rows", "error", err)
+				return nil, err
+			}
+		}
+		res.Builds = append(res.Builds, &b)
+	}
+	if err = rows.Err(); err != nil {
+		err = dbError("builds are not found", err)
+		slog.Error("Cannot read builds", "error", err)
+		return nil, err
+	}
+	return &res, nil
+}
@@ -4,14 +4,15 @@ import (
 	"cmp"
 	"context"
 	"database/sql"
-	"errors"
 	"fmt"
 	"log/slog"
 	"slices"
+	"strings"
 
-	"github.com/gnames/bhlnames/internal/ent/bhl"
-	"github.com/gnames/bhlnames/internal/ent/input"
 	"github.com/gnames/bhlnames/internal/ent/model"
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/input"
+	"github.com/gnames/bhlnames/pkg/ent/reffnd"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
@@ -43,9 +44,95 @@ type refRec struct {
 	matchedCanonical   string
 	matchType          string
 	editDistance       int
+	nameMatch          input.NameMatch
+	queryEditDistance  int
 }
 
-func (rf reffndio) refByPageID(pageID int) (*bhl.Reference, error) {
+// nameQuery describes how names are selected for the occurrences query.
+type nameQuery struct {
+	// cond is an SQL condition with numbered placeholders for args.
+	cond string
+
+	// dist is an SQL expression for the edit distance between the query
+	// and a matched canonical form.
+	dist string
+
+	// args are arguments of the condition.
+	args []any
+
+	// mode is the kind of the name match.
+	mode input.NameMatch
+
+	// name is the canonical form of the query.
+	name string
+}
+
+// exactQuery selects names where the field is equal to the name.
+func exactQuery(field, name string) nameQuery {
+	return nameQuery{
+		cond: fmt.Sprintf("ns.%s = $1", field),
+		dist: "0",
+		args: []any{name},
+		mode: input.MatchExact,
+		name: name,
+	}
+}
+
+// variantsQuery selects names which canonical forms are in the list. Names
+// that differ from the canonical form of the query are stem matches.
+func variantsQuery(names []string, canonical string) nameQuery {
+	return nameQuery{
+		cond: "ns.matched_canonical = ANY($1)",
+		dist: "0",
+		args: []any{names},
+		mode: input.MatchStem,
+		name: canonical,
+	}
+}
+
+// newNameQuery creates a query for the name according to the name match
+// of the input. Stem and fuzzy matches need the stemmed canonical form.
+// Fuzzy candidates are limited to names of the same genus to keep the
+// search fast.
+func newNameQuery(prm input.Params, canonical, stem string) nameQuery {
+	switch prm.NameMatch {
+	case input.MatchStem:
+		if stem == "" {
+			break
+		}
+		return nameQuery{
+			cond: "ns.matched_canonical_stem = $1",
+			dist: "0",
+			args: []any{stem},
+			mode: input.MatchStem,
+			name: canonical,
+		}
+	case input.MatchFuzzy:
+		if stem == "" {
+			break
+		}
+		genus := escapeLike(strings.Fields(stem)[0])
+		return nameQuery{
+			cond: "ns.matched_canonical_stem LIKE $1" +
+				" AND levenshtein_less_equal(ns.matched_canonical, $2, $3) <= $3",
+			dist: "levenshtein_less_equal(ns.matched_canonical, $2, $3)",
+			args: []any{genus + " %", canonical, prm.EditDistance()},
+			mode: input.MatchFuzzy,
+			name: canonical,
+		}
+	}
+	return exactQuery("matched_canonical", canonical)
+}
+
+func (rf reffndio) refByPageID(
+	ctx context.Context,
+	pageID int,
+) (*bhl.Reference, error) {
+	if pageID < 1 {
+		msg := fmt.Sprintf("page ID %d is not a positive number", pageID)
+		return nil, reffnd.NewError(reffnd.ErrInvalidInput, msg, nil)
+	}
+
 	qs := `SELECT
   itm.id, itm.title_id, pg.id, pg.page_num,
   itm.title_year_start, itm.title_year_end, itm.year_start, itm.year_end,
@@ -57,7 +144,9 @@ func (rf reffndio) refByPageID(pageID int) (*bhl.Reference, error) {
 	WHERE pg.id = $1
 	ORDER BY title_year_start`
 
-	r := rf.db.QueryRow(rf.ctx, qs, pageID)
+	ctx, cancel := rf.queryCtx(ctx)
+	defer cancel()
+	r := rf.db.QueryRow(ctx, qs, pageID)
 
 	var rr refRec
 	err := r.Scan(&rr.itemID, &rr.titleID, &rr.pageID, &rr.pageNum,
@@ -67,14 +156,14 @@ func (rf reffndio) refByPageID(pageID int) (*bhl.Reference, error) {
 		&rr.namesTotal,
 	)
 	if err != nil {
-		err = fmt.Errorf("reffinderio.refByPageID: %w", err)
+		err = dbError(fmt.Sprintf("page %d is not found", pageID), err)
 		slog.Error("Cannot find page", "page_id", pageID, "err", err)
 		return nil, err
 	}
 
 	preRes := preReference{item: &rr}
 
-	part, err := rf.partByID(pageID)
+	part, err := rf.partByID(ctx, pageID)
 	if err != nil {
 		err = fmt.Errorf("reffinderio.refByPageID: %w", err)
 		slog.Error("Cannot find part", "page_id", pageID, "err", err)
@@ -84,12 +173,16 @@ func (rf reffndio) refByPageID(pageID int) (*bhl.Reference, error) {
 	// }
 	res := rf.getReferences([]*preReference{&preRes}, false)
 	if len(res) == 0 {
-		return nil, errors.New("reffinderio.refByPageID: no references found")
+		msg := fmt.Sprintf("no references found for page %d", pageID)
+		return nil, reffnd.NewError(reffnd.ErrNotFound, msg, nil)
 	}
 	return &res[0].Reference, nil
 }
 
-func (rf reffndio) partByID(pageID int) (*model.Part, error) {
+func (rf reffndio) partByID(
+	ctx context.Context,
+	pageID int,
+) (*model.Part, error) {
 	q := `SELECT
   id, title, doi, page_num_start, page_num_end, year
 	FROM parts p
@@ -97,8 +190,11 @@ func (rf reffndio) partByID(pageID int) (*model.Part, error) {
 		ON p.id = pp.part_id
 	WHERE pp.page_id = $1
 	`
-	rows, err := rf.db.Query(rf.ctx, q, pageID)
+	ctx, cancel := rf.queryCtx(ctx)
+	defer cancel()
+	rows, err := rf.db.Query(ctx, q, pageID)
 	if err != nil {
+		err = dbError("part is not found", err)
 		slog.Error("Cannot run part query", "error", err)
 		return nil, err
 	}
@@ -115,28 +211,42 @@ func (rf reffndio) partByID(pageID int) (*model.Part, error) {
 		}
 		parts = append(parts, res)
 	}
+	if err = rows.Err(); err != nil {
+		err = dbError("part is not found", err)
+		slog.Error("Cannot read part rows", "page_id", pageID, "error", err)
+		return nil, err
+	}
 	if len(parts) == 0 {
 		return nil, nil
 	}
 	return &parts[len(parts)-1], nil
 }
 
-func (rf reffndio) nameOnlyOccurrences(nameRefs *bhl.RefsByName) ([]*refRec, error) {
-	return rf.occurrences(nameRefs.Canonical, "matched_canonical")
+func (rf reffndio) nameOnlyOccurrences(
+	ctx context.Context,
+	nameRefs *bhl.RefsByName,
+	stem string,
+) ([]*refRec, error) {
+	inp := nameRefs.Input
+	nq := newNameQuery(inp.Params, nameRefs.Canonical, stem)
+	return rf.occurrences(ctx, nq, inp.Filter)
 }
 
-func (rf reffndio) taxonOccurrences(nameRefs *bhl.RefsByName) ([]*refRec, error) {
-	return rf.occurrences(nameRefs.CurrentCanonical, "current_canonical")
+func (rf reffndio) taxonOccurrences(
+	ctx context.Context,
+	nameRefs *bhl.RefsByName,
+) ([]*refRec, error) {
+	nq := exactQuery("current_canonical", nameRefs.CurrentCanonical)
+	return rf.occurrences(ctx, nq, nameRefs.Input.Filter)
 }
 
-func (rf reffndio) occurrences(name string, field string) ([]*refRec, error) {
-	switch field {
-	case "matched_canonical", "current_canonical":
-	default:
-		slog.Warn("Unregistered field", "field", field)
-		return nil, nil
-	}
+func (rf reffndio) occurrences(
+	ctx context.Context,
+	nq nameQuery,
+	f input.Filter,
+) ([]*refRec, error) {
 	var res []*refRec
+	var queryDist int
 	var itemID, titleID, pageID int
 	var kingdomPercent *pgtype.Int4
 	var namesTotal int
@@ -151,18 +261,23 @@ func (rf reffndio) occurrences(name string, field string) ([]*refRec, error) {
   itm.title_year_start, itm.title_year_end, itm.year_start, itm.year_end,
   itm.title_name, itm.vol, itm.title_doi, ist.main_taxon, ist.main_kingdom,
   ist.main_kingdom_percent, ist.names_total, ns.id, ns.name, ns.matched_canonical,
-  ns.match_type, ns.edit_distance
+  ns.match_type, ns.edit_distance, %s
 	FROM name_strings ns
 			JOIN name_occurrences pns ON ns.id = pns.name_string_id
 			JOIN pages pg ON pg.id = pns.page_id
 			JOIN items itm ON itm.id = pg.item_id
       JOIN item_stats ist ON itm.id = ist.id
-	WHERE ns.%s = $1
+	WHERE %s%s
 	ORDER BY title_year_start`
-	q := fmt.Sprintf(qs, field)
+	where, args := occurrencesFilter(f, len(nq.args)+1)
+	q := fmt.Sprintf(qs, nq.dist, nq.cond, where)
+	args = append(nq.args, args...)
 
-	rows, err := rf.db.Query(rf.ctx, q, name)
+	ctx, cancel := rf.queryCtx(ctx)
+	defer cancel()
+	rows, err := rf.db.Query(ctx, q, args...)
 	if err != nil {
+		err = dbError("occurrences are not found", err)
 		slog.Error("Cannot run occurences query", "error", err)
 		return nil, err
 	}
@@ -171,7 +286,8 @@ func (rf reffndio) occurrences(name string, field string) ([]*refRec, error) {
 		err := rows.Scan(&itemID, &titleID, &pageID, &pageNum, &annot,
 			&titleYearStart, &titleYearEnd, &yearStart, &yearEnd, &titleName, &vol,
 			&titleDOI, &contextWrds, &majorKingdom, &kingdomPercent, &namesTotal,
-			&nameID, &nameString, &matchedCanonical, &matchType, &editDistance)
+			&nameID, &nameString, &matchedCanonical, &matchType, &editDistance,
+			&queryDist)
 		if err != nil {
 			err = fmt.Errorf("reffinderio.occurrences: %w", err)
 			slog.Error("Cannot scan row", "error", err)
@@ -199,43 +315,108 @@ func (rf reffndio) occurrences(name string, field string) ([]*refRec, error) {
 			matchedCanonical:   matchedCanonical.String,
 			matchType:          matchType.String,
 			editDistance:       int(editDistance.Int16),
+			nameMatch:          nq.mode,
+			queryEditDistance:  queryDist,
+		}
+		if rec.matchedCanonical == nq.name {
+			rec.nameMatch = input.MatchExact
 		}
 
 		res = append(res, rec)
 	}
+	if err = rows.Err(); err != nil {
+		err = dbError("occurrences are not found", err)
+		slog.Error("Cannot read occurrences", "error", err)
+		return nil, err
+	}
 	return res, nil
 }
 
-func (rf reffndio) currentCanonical(canonical string) (string, error) {
+// occurrencesFilter creates additional conditions for the occurrences query
+// from the filter. Placeholders of the conditions start from argNum. The
+// year of a reference might come from its part, so years only keep items
+// which range overlaps with the filter, the exact cut is done by
+// filterYears.
+func occurrencesFilter(f input.Filter, argNum int) (string, []any) {
+	var conds []string
+	var args []any
+	add := func(cond string, arg any) {
+		conds = append(conds, fmt.Sprintf(cond, argNum))
+		args = append(args, arg)
+		argNum++
+	}
+
+	if f.YearFrom > 0 {
+		yearEnd := "GREATEST(itm.year_end, itm.year_start, itm.title_year_start)"
+		add(yearEnd+" >= $%d", f.YearFrom)
+	}
+	if f.YearTo > 0 {
+		add("COALESCE(itm.year_start, itm.title_year_start) <= $%d", f.YearTo)
+	}
+	if f.TitleID > 0 {
+		add("itm.title_id = $%d", f.TitleID)
+	}
+	if f.ItemID > 0 {
+		add("itm.id = $%d", f.ItemID)
+	}
+	if f.MainKingdom != "" {
+		add("ist.main_kingdom = $%d", f.MainKingdom)
+	}
+	switch f.AnnotNomen {
+	case "":
+	case "ANY":
+		conds = append(conds, "pns.annot_nomen != 'NO_ANNOT'")
+	default:
+		add("pns.annot_nomen = $%d", f.AnnotNomen)
+	}
+
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return "\n    AND " + strings.Join(conds, "\n    AND "), args
+}
+
+func (rf reffndio) currentCanonical(
+	ctx context.Context,
+	canonical string,
+) (string, error) {
 	var currentCan sql.NullString
 	q := `SELECT current_canonical
           FROM name_strings
         WHERE matched_canonical = $1
         LIMIT 1`
-	ctx := context.Background()
+	ctx, cancel := rf.queryCtx(ctx)
+	defer cancel()
 	row := rf.db.QueryRow(ctx, q, canonical)
 	err := row.Scan(&currentCan)
 	if err == pgx.ErrNoRows {
 		return "", nil
 	} else if err != nil {
-		return "", err
+		return "", dbError("current canonical is not found", err)
 	}
 	return currentCan.String, nil
 }
 
-func (rf *reffndio) refsByExtID(extID string) ([]byte, error) {
+func (rf *reffndio) refsByExtID(
+	ctx context.Context,
+	extID string,
+) ([]byte, error) {
 	var res []byte
 	q := `
 SELECT cr.result
 	FROM col_bhl_results cr
 	WHERE cr.record_id = $1
 `
-	err := rf.db.QueryRow(rf.ctx, q, extID).Scan(&res)
+	ctx, cancel := rf.queryCtx(ctx)
+	defer cancel()
+	err := rf.db.QueryRow(ctx, q, extID).Scan(&res)
 	if err == pgx.ErrNoRows {
-		return nil, nil
+		msg := fmt.Sprintf("external ID %s is not found", extID)
+		return nil, reffnd.NewError(reffnd.ErrNotFound, msg, nil)
 	}
 
 	if err != nil {
+		err = dbError("external ID is not found", err)
 		slog.Error("Cannot run external ID query", "error", err)
 		return nil, err
 	}
@@ -243,7 +424,10 @@ SELECT cr.result
 	return res, nil
 }
 
-func (rf *reffndio) colNomen(inp input.Input) (*bhl.RefsByName, error) {
+func (rf *reffndio) colNomen(
+	ctx context.Context,
+	inp input.Input,
+) (*bhl.RefsByName, error) {
 	q := `
 SELECT cr.result 
 	FROM col_names cn
@@ -254,8 +438,11 @@ SELECT cr.result
 
 	var res []*bhl.RefsByName
 
-	rows, err := rf.db.Query(rf.ctx, q, inp.Name.CanonicalSimple)
+	ctx, cancel := rf.queryCtx(ctx)
+	defer cancel()
+	rows, err := rf.db.Query(ctx, q, inp.Name.CanonicalSimple)
 	if err != nil {
+		err = dbError("nomenclatural event is not found", err)
 		slog.Error("Cannot run CoL nomen query", "error", err)
 		return nil, err
 	}
@@ -280,6 +467,11 @@ SELECT cr.result
 			res = append(res, &nref)
 		}
 	}
+	if err = rows.Err(); err != nil {
+		err = dbError("nomenclatural event is not found", err)
+		slog.Error("Cannot read CoL nomen results", "error", err)
+		return nil, err
+	}
 	var out *bhl.RefsByName
 	switch len(res) {
 	case 0:
@@ -305,70 +497,151 @@ func prepareCoLOutput(inp input.Input, nr *bhl.RefsByName) {
 	nr.Meta.InputReferenceFrom = "Catalogue of Life"
 }
 
-func (rf *reffndio) itemStats(itemID int) (*bhl.Item, error) {
+func (rf *reffndio) itemStats(
+	ctx context.Context,
+	itemID, taxaLimit int,
+) (*bhl.Item, error) {
+	if itemID < 1 {
+		msg := fmt.Sprintf("item ID %d is not a positive number", itemID)
+		return nil, reffnd.NewError(reffnd.ErrInvalidInput, msg, nil)
+	}
+	if taxaLimit < 0 {
+		msg := fmt.Sprintf("taxa limit %d is a negative number", taxaLimit)
+		return nil, reffnd.NewError(reffnd.ErrInvalidInput, msg, nil)
+	}
+
 	q := `
-SELECT
-	item.id, item.title_id, item.title_year_start, item.title_year_end,
-	item.year_start, item.year_end, item.title_name, item.vol, item.title_doi,
-	ist.main_taxon, ist.main_taxon_rank, ist.main_taxon_percent,
-	ist.main_kingdom, ist.main_kingdom_percent,
-	ist.animalia_num, ist.plantae_num, ist.fungi_num, ist.bacteria_num,
-	ist.main_phylum, ist.main_phylum_percent,
-	ist.main_class, ist.main_class_percent,
-	ist.main_order, ist.main_order_percent,
-	ist.main_family, ist.main_family_percent,
-	ist.main_genus, ist.main_genus_percent,
-	ist.names_total
+SELECT` + itemFields + `
 	FROM items item
 		JOIN item_stats ist
 			ON item.id = ist.id
 	WHERE item.id = $1
 `
 
-	var res bhl.Item
-	err := rf.db.QueryRow(rf.ctx, q, itemID).Scan(
-		&res.ItemID, &res.TitleID, &res.TitleYearStart, &res.TitleYearEnd,
-		&res.YearStart, &res.YearEnd, &res.TitleName, &res.Volume, &res.TitleDOI,
-		&res.MainTaxon, &res.MainTaxonRank, &res.MainTaxonPercent,
-		&res.MainKingdom, &res.MainKingdomPercent,
-		&res.AnimaliaNum, &res.PlantaeNum, &res.FungiNum, &res.BacteriaNum,
-		&res.MainPhylum, &res.MainPhylumPercent,
-		&res.MainClass, &res.MainClassPercent,
-		&res.MainOrder, &res.MainOrderPercent,
-		&res.MainFamily, &res.MainFamilyPercent,
-		&res.MainGenus, &res.MainGenusPercent,
-		&res.UniqNamesNum,
-	)
+	ctx, cancel := rf.queryCtx(ctx)
+	defer cancel()
+	res, err := scanItem(rf.db.QueryRow(ctx, q, itemID))
 	if err != nil {
+		err = dbError(fmt.Sprintf("item %d is not found", itemID), err)
 		slog.Error("Cannot run item stats query", "error", err)
 		return nil, err
 	}
-	return &res, nil
+
+	res.Taxa, err = rf.itemTaxa(ctx, itemID, taxaLimit)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
 }
 
-func (rf *reffndio) itemsByTaxon(taxon string) ([]*bhl.Item, error) {
-	q := `
-SELECT
+// itemTaxa returns the taxonomic distribution of an item. If taxaLimit is
+// positive, only this number of the largest taxa is returned for each rank.
+func (rf *reffndio) itemTaxa(
+	ctx context.Context,
+	itemID, taxaLimit int,
+) ([]*bhl.ItemTaxon, error) {
+	q := `SELECT rank, taxon, names_num, percent FROM (
+  SELECT *, row_number()
+    OVER (PARTITION BY rank ORDER BY names_num DESC, taxon) AS rn
+  FROM item_taxons
+  WHERE item_id = $1
+) taxa
+WHERE $2 = 0 OR rn <= $2
+ORDER BY array_position(
+  ARRAY['kingdom','phylum','class','order','family','genus']::varchar[],
+  rank
+), names_num DESC, taxon`
+
+	rows, err := rf.db.Query(ctx, q, itemID, taxaLimit)
+	if err != nil {
+		err = dbError("item taxa are not found", err)
+		slog.Error("Cannot run item taxa query", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*bhl.ItemTaxon
+	for rows.Next() {
+		var it bhl.ItemTaxon
+		err = rows.Scan(&it.Rank, &it.Taxon, &it.NamesNum, &it.Percent)
+		if err != nil {
+			err = fmt.Errorf("reffinderio.itemTaxa: %w", err)
+			slog.Error("Cannot scan row", "error", err)
+			return nil, err
+		}
+		res = append(res, &it)
+	}
+	if err = rows.Err(); err != nil {
+		err = dbError("item taxa are not found", err)
+		slog.Error("Cannot read item taxa", "error", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+// itemFields are the columns of items and item_stats tables that are
+// read by scanItem. Statistics are empty for items without item_stats
+// rows, so the fields can be used with LEFT JOIN.
+const itemFields = `
 	item.id, item.title_id, item.title_year_start, item.title_year_end,
 	item.year_start, item.year_end, item.title_name, item.vol, item.title_doi,
-	ist.main_taxon, ist.main_taxon_rank, ist.main_taxon_percent,
-	ist.main_kingdom, ist.main_kingdom_percent,
-	ist.animalia_num, ist.plantae_num, ist.fungi_num, ist.bacteria_num,
-	ist.main_phylum, ist.main_phylum_percent,
-	ist.main_class, ist.main_class_percent,
-	ist.main_order, ist.main_order_percent,
-	ist.main_family, ist.main_family_percent,
-	ist.main_genus, ist.main_genus_percent,
-	ist.names_total
-	FROM items item
-		JOIN item_stats ist
-			ON item.id = ist.id
-	WHERE ist.main_taxon = $1
-	ORDER BY ist.main_taxon_percent DESC, ist.names_total DESC, item.id
-`
+	coalesce(ist.main_taxon, ''), coalesce(ist.main_taxon_rank, ''),
+	coalesce(ist.main_taxon_percent, 0),
+	coalesce(ist.main_kingdom, ''), coalesce(ist.main_kingdom_percent, 0),
+	coalesce(ist.animalia_num, 0), coalesce(ist.plantae_num, 0),
+	coalesce(ist.fungi_num, 0), coalesce(ist.bacteria_num, 0),
+	coalesce(ist.main_phylum, ''), coalesce(ist.main_phylum_percent, 0),
+	coalesce(ist.main_class, ''), coalesce(ist.main_class_percent, 0),
+	coalesce(ist.main_order, ''), coalesce(ist.main_order_percent, 0),
+	coalesce(ist.main_family, ''), coalesce(ist.main_family_percent, 0),
+	coalesce(ist.main_genus, ''), coalesce(ist.main_genus_percent, 0),
+	coalesce(ist.names_total, 0)`
+
+// scanItem reads a row with itemFields columns.
+func scanItem(r pgx.Row) (*bhl.Item, error) {
+	var itm bhl.Item
+	err := r.Scan(
+		&itm.ItemID, &itm.TitleID, &itm.TitleYearStart, &itm.TitleYearEnd,
+		&itm.YearStart, &itm.YearEnd, &itm.TitleName, &itm.Volume, &itm.TitleDOI,
+		&itm.MainTaxon, &itm.MainTaxonRank, &itm.MainTaxonPercent,
+		&itm.MainKingdom, &itm.MainKingdomPercent,
+		&itm.AnimaliaNum, &itm.PlantaeNum, &itm.FungiNum, &itm.BacteriaNum,
+		&itm.MainPhylum, &itm.MainPhylumPercent,
+		&itm.MainClass, &itm.MainClassPercent,
+		&itm.MainOrder, &itm.MainOrderPercent,
+		&itm.MainFamily, &itm.MainFamilyPercent,
+		&itm.MainGenus, &itm.MainGenusPercent,
+		&itm.UniqNamesNum,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &itm, nil
+}
 
-	rows, err := rf.db.Query(rf.ctx, q, taxon)
+func (rf *reffndio) itemsByTaxon(
+	ctx context.Context,
+	iq input.ItemsQuery,
+) ([]*bhl.Item, error) {
+	if iq.Taxon == "" {
+		return nil, reffnd.NewError(reffnd.ErrInvalidInput, "taxon name is empty", nil)
+	}
+	if !iq.Rank.IsValid() {
+		msg := fmt.Sprintf("unknown rank '%s'", iq.Rank)
+		return nil, reffnd.NewError(reffnd.ErrInvalidInput, msg, nil)
+	}
+	if !iq.SortBy.IsValid() {
+		msg := fmt.Sprintf("unknown sort order '%s'", iq.SortBy)
+		return nil, reffnd.NewError(reffnd.ErrInvalidInput, msg, nil)
+	}
+
+	q, args := taxonItemsQuery(iq)
+
+	ctx, cancel := rf.queryCtx(ctx)
+	defer cancel()
+	rows, err := rf.db.Query(ctx, q, args...)
 	if err != nil {
+		err = dbError("items are not found", err)
 		slog.Error("Cannot run items taxon query", "error", err)
 		return nil, err
 	}
@@ -376,25 +649,65 @@ SELECT
 
 	var res []*bhl.Item
 	for rows.Next() {
-		var itm bhl.Item
-		err = rows.Scan(
-			&itm.ItemID, &itm.TitleID, &itm.TitleYearStart, &itm.TitleYearEnd,
-			&itm.YearStart, &itm.YearEnd, &itm.TitleName, &itm.Volume, &itm.TitleDOI,
-			&itm.MainTaxon, &itm.MainTaxonRank, &itm.MainTaxonPercent,
-			&itm.MainKingdom, &itm.MainKingdomPercent,
-			&itm.AnimaliaNum, &itm.PlantaeNum, &itm.FungiNum, &itm.BacteriaNum,
-			&itm.MainPhylum, &itm.MainPhylumPercent,
-			&itm.MainClass, &itm.MainClassPercent,
-			&itm.MainOrder, &itm.MainOrderPercent,
-			&itm.MainFamily, &itm.MainFamilyPercent,
-			&itm.MainGenus, &itm.MainGenusPercent,
-			&itm.UniqNamesNum,
-		)
+		itm, err := scanItem(rows)
 		if err != nil {
-			slog.Error("Cannot run item stats query", "error", err)
+			slog.Error("Cannot scan taxon item", "error", err)
 			return nil, err
 		}
-		res = append(res, &itm)
+		res = append(res, itm)
+	}
+	if err = rows.Err(); err != nil {
+		err = dbError("items are not found", err)
+		slog.Error("Cannot read taxon items", "error", err)
+		return nil, err
 	}
 	return res, nil
 }
+
+// taxonItemsQuery creates a query for items where the taxon is the most
+// prevalent one at the given rank.
+func taxonItemsQuery(iq input.ItemsQuery) (string, []any) {
+	taxon, percent := "ist.main_taxon", "ist.main_taxon_percent"
+	if iq.Rank != input.RankAny {
+		taxon = "ist.main_" + string(iq.Rank)
+		percent = taxon + "_percent"
+	}
+
+	args := []any{iq.Taxon}
+	where := "\n\tWHERE " + taxon + " = $1"
+	if iq.MinPercent > 0 {
+		args = append(args, iq.MinPercent)
+		where += fmt.Sprintf("\n    AND %s >= $%d", percent, len(args))
+	}
+	if iq.MinNamesNum > 0 {
+		args = append(args, iq.MinNamesNum)
+		where += fmt.Sprintf("\n    AND ist.names_total >= $%d", len(args))
+	}
+	cond, condArgs := itemsFilter(iq.ItemsFilter, len(args)+1)
+	where += cond
+	args = append(args, condArgs...)
+
+	var order string
+	switch iq.SortBy {
+	case input.ItemsByNames:
+		order = "ist.names_total DESC, " + percent + " DESC, item.id"
+	case input.ItemsByYear:
+		order = "GREATEST(item.year_start, item.title_year_start), item.id"
+	default:
+		order = percent + " DESC, ist.names_total DESC, item.id"
+	}
+
+	q := `
+SELECT` + itemFields + `
+	FROM items item
+		JOIN item_stats ist
+			ON item.id = ist.id` + where + `
+	ORDER BY ` + order
+	if iq.Limit > 0 {
+		q += fmt.Sprintf("\n\tLIMIT %d", iq.Limit)
+	}
+	if iq.Offset > 0 {
+		q += fmt.Sprintf("\n\tOFFSET %d", iq.Offset)
+	}
+	return q, args
+}
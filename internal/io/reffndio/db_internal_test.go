@@ -0,0 +1,170 @@
+package reffndio
+
+import (
+	"testing"
+
+	"github.com/gnames/bhlnames/pkg/ent/input"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOccurrencesFilter(t *testing.T) {
+	assert := assert.New(t)
+	yearEnd := "GREATEST(itm.year_end, itm.year_start, itm.title_year_start)"
+	yearStart := "COALESCE(itm.year_start, itm.title_year_start)"
+	tests := []struct {
+		msg    string
+		filter input.Filter
+		conds  string
+		args   []any
+	}{
+		{"empty", input.Filter{}, "", nil},
+		{
+			"years",
+			input.Filter{YearFrom: 1890, YearTo: 1920},
+			"\n    AND " + yearEnd + " >= $2\n    AND " + yearStart + " <= $3",
+			[]any{1890, 1920},
+		},
+		{
+			"ids and kingdom",
+			input.Filter{TitleID: 5, ItemID: 7, MainKingdom: "Plantae"},
+			"\n    AND itm.title_id = $2\n    AND itm.id = $3" +
+				"\n    AND ist.main_kingdom = $4",
+			[]any{5, 7, "Plantae"},
+		},
+		{
+			"any annot",
+			input.Filter{AnnotNomen: "ANY"},
+			"\n    AND pns.annot_nomen != 'NO_ANNOT'",
+			nil,
+		},
+		{
+			"annot",
+			input.Filter{AnnotNomen: "SP_NOV", ItemID: 3},
+			"\n    AND itm.id = $2\n    AND pns.annot_nomen = $3",
+			[]any{3, "SP_NOV"},
+		},
+		{
+			"quality is not in SQL",
+			input.Filter{MinRefMatchQuality: 3},
+			"",
+			nil,
+		},
+	}
+
+	for _, v := range tests {
+		conds, args := occurrencesFilter(v.filter, 2)
+		assert.Equal(v.conds, conds, v.msg)
+		assert.Equal(v.args, args, v.msg)
+	}
+}
+
+func TestNewNameQuery(t *testing.T) {
+	assert := assert.New(t)
+	can, stem := "Pardosa moesta", "Pardosa moest"
+	tests := []struct {
+		msg  string
+		prm  input.Params
+		stem string
+		cond string
+		args []any
+		mode input.NameMatch
+	}{
+		{
+			"default", input.Params{}, stem,
+			"ns.matched_canonical = $1", []any{can}, input.MatchExact,
+		},
+		{
+			"stem", input.Params{NameMatch: input.MatchStem}, stem,
+			"ns.matched_canonical_stem = $1", []any{stem}, input.MatchStem,
+		},
+		{
+			"no stem", input.Params{NameMatch: input.MatchStem}, "",
+			"ns.matched_canonical = $1", []any{can}, input.MatchExact,
+		},
+		{
+			"fuzzy",
+			input.Params{NameMatch: input.MatchFuzzy, MaxEditDistance: 2},
+			stem,
+			"ns.matched_canonical_stem LIKE $1" +
+				" AND levenshtein_less_equal(ns.matched_canonical, $2, $3) <= $3",
+			[]any{"Pardosa %", can, 2},
+			input.MatchFuzzy,
+		},
+	}
+
+	for _, v := range tests {
+		nq := newNameQuery(v.prm, can, v.stem)
+		assert.Equal(v.cond, nq.cond, v.msg)
+		assert.Equal(v.args, nq.args, v.msg)
+		assert.Equal(v.mode, nq.mode, v.msg)
+	}
+}
+
+func TestVariantsQuery(t *testing.T) {
+	assert := assert.New(t)
+	names := []string{"Pardosa moesta", "Lycosa moesta"}
+	nq := variantsQuery(names, "Pardosa moesta")
+	assert.Equal("ns.matched_canonical = ANY($1)", nq.cond)
+	assert.Equal([]any{names}, nq.args)
+	assert.Equal(input.MatchStem, nq.mode)
+	assert.Equal("Pardosa moesta", nq.name)
+}
+
+func TestTaxonItemsQuery(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		msg  string
+		iq   input.ItemsQuery
+		has  []string
+		args []any
+	}{
+		{
+			"default",
+			input.NewItemsQuery("Lepidoptera"),
+			[]string{
+				"WHERE ist.main_taxon = $1",
+				"ORDER BY ist.main_taxon_percent DESC, ist.names_total DESC",
+			},
+			[]any{"Lepidoptera"},
+		},
+		{
+			"rank and thresholds",
+			input.NewItemsQuery("Aves",
+				input.OptItemsRank(input.RankClass),
+				input.OptItemsMinPercent(70),
+				input.OptItemsMinNamesNum(100),
+				input.OptItemsYearFrom(1850),
+			),
+			[]string{
+				"WHERE ist.main_class = $1",
+				"AND ist.main_class_percent >= $2",
+				"AND ist.names_total >= $3",
+				"GREATEST(item.year_start, item.title_year_start) >= $4",
+			},
+			[]any{"Aves", 70, 100, 1850},
+		},
+		{
+			"sort and paging",
+			input.NewItemsQuery("Plantae",
+				input.OptItemsRank(input.RankKingdom),
+				input.OptItemsSortBy(input.ItemsByYear),
+				input.OptItemsLimit(20),
+				input.OptItemsOffset(40),
+			),
+			[]string{
+				"ORDER BY GREATEST(item.year_start, item.title_year_start), item.id",
+				"LIMIT 20",
+				"OFFSET 40",
+			},
+			[]any{"Plantae"},
+		},
+	}
+
+	for _, v := range tests {
+		q, args := taxonItemsQuery(v.iq)
+		for _, s := range v.has {
+			assert.Contains(q, s, v.msg)
+		}
+		assert.Equal(v.args, args, v.msg)
+	}
+}
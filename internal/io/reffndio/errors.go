@@ -0,0 +1,44 @@
+package reffndio
+
+import (
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/gnames/bhlnames/pkg/ent/reffnd"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// dbError converts errors from the database to typed RefFinder errors.
+// The msg is used if nothing was found. Unknown errors are returned as is.
+func dbError(msg string, err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, pgx.ErrNoRows):
+		return reffnd.NewError(reffnd.ErrNotFound, msg, err)
+	case isUnavailable(err):
+		return reffnd.NewError(reffnd.ErrUnavailable, "database is unavailable", err)
+	default:
+		return err
+	}
+}
+
+// isUnavailable checks if the error is caused by a lost, refused or
+// timed out connection to the database.
+func isUnavailable(err error) bool {
+	var connErr *pgconn.ConnectError
+	var netErr net.Error
+	if errors.As(err, &connErr) || errors.As(err, &netErr) || pgconn.Timeout(err) {
+		return true
+	}
+
+	// connection exceptions (08) and operator intervention (57P) classes.
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return strings.HasPrefix(pgErr.Code, "08") ||
+			strings.HasPrefix(pgErr.Code, "57P")
+	}
+	return false
+}
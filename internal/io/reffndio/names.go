@@ -0,0 +1,176 @@
+package reffndio
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/reffnd"
+)
+
+// itemNames returns unique names detected in a BHL item.
+func (rf reffndio) itemNames(
+	ctx context.Context,
+	itemID int,
+) (*bhl.NameList, error) {
+	if itemID < 1 {
+		msg := fmt.Sprintf("item ID %d is not a positive number", itemID)
+		return nil, reffnd.NewError(reffnd.ErrInvalidInput, msg, nil)
+	}
+
+	msg := fmt.Sprintf("item %d is not found", itemID)
+	err := rf.exists(ctx, "SELECT 1 FROM items WHERE id = $1", itemID, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := rf.nameList(ctx, "", "pg.item_id = $1", itemID)
+	if err != nil {
+		return nil, err
+	}
+	res.ItemID = itemID
+	return res, nil
+}
+
+// partNames returns unique names detected on pages of a BHL part.
+func (rf reffndio) partNames(
+	ctx context.Context,
+	partID int,
+) (*bhl.NameList, error) {
+	if partID < 1 {
+		msg := fmt.Sprintf("part ID %d is not a positive number", partID)
+		return nil, reffnd.NewError(reffnd.ErrInvalidInput, msg, nil)
+	}
+
+	msg := fmt.Sprintf("part %d is not found", partID)
+	err := rf.exists(ctx, "SELECT 1 FROM parts WHERE id = $1", partID, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	join := "JOIN page_parts pp ON pp.page_id = pg.id"
+	res, err := rf.nameList(ctx, join, "pp.part_id = $1", partID)
+	if err != nil {
+		return nil, err
+	}
+	res.PartID = partID
+	return res, nil
+}
+
+// exists returns ErrNotFound error with msg if the query returns no rows.
+func (rf reffndio) exists(
+	ctx context.Context,
+	q string,
+	id int,
+	msg string,
+) error {
+	ctx, cancel := rf.queryCtx(ctx)
+	defer cancel()
+
+	var one int
+	err := rf.db.QueryRow(ctx, q, id).Scan(&one)
+	if err != nil {
+		err = dbError(msg, err)
+		slog.Error("Cannot find record", "id", id, "err", err)
+		return err
+	}
+	return nil
+}
+
+// nameList aggregates name occurrences from pages that satisfy the
+// condition. The join adds tables that the condition needs.
+func (rf reffndio) nameList(
+	ctx context.Context,
+	join, cond string,
+	id int,
+) (*bhl.NameList, error) {
+	q := `SELECT
+  ns.name, count(*),
+  (array_agg(pg.id ORDER BY pg.sequence_order, pns.offset_start))[1],
+  (array_agg(pg.id ORDER BY pg.sequence_order DESC, pns.offset_start DESC))[1],
+  array_agg(DISTINCT pns.annot_nomen)
+    FILTER (WHERE pns.annot_nomen <> '' AND pns.annot_nomen <> 'NO_ANNOT'),
+  ns.match_type, ns.matched_canonical, ns.current_canonical,
+  ns.classification, ns.classification_ranks, ns.classification_ids,
+  ns.data_source_id
+	FROM name_occurrences pns
+		JOIN pages pg ON pg.id = pns.page_id
+		JOIN name_strings ns ON ns.id = pns.name_string_id
+		%s
+	WHERE %s
+	GROUP BY ns.id
+	ORDER BY min(pg.sequence_order), ns.name`
+	q = fmt.Sprintf(q, join, cond)
+
+	ctx, cancel := rf.queryCtx(ctx)
+	defer cancel()
+	rows, err := rf.db.Query(ctx, q, id)
+	if err != nil {
+		err = dbError("names are not found", err)
+		slog.Error("Cannot run names list query", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := bhl.NameList{
+		Kingdoms: make(map[string]int),
+		Names:    make([]*bhl.NameStats, 0),
+	}
+	for rows.Next() {
+		var name, matchType, matchedCanonical, currentCanonical, cl, clRanks,
+			clIDs sql.NullString
+		var dsID sql.NullInt32
+		var ns bhl.NameStats
+		err = rows.Scan(&name, &ns.OccurrencesNum, &ns.FirstPageID,
+			&ns.LastPageID, &ns.AnnotNomen, &matchType, &matchedCanonical,
+			&currentCanonical, &cl, &clRanks, &clIDs, &dsID,
+		)
+		if err != nil {
+			err = fmt.Errorf("reffinderio.nameList: %w", err)
+			slog.Error("Cannot scan row", "error", err)
+			return nil, err
+		}
+		ns.Name = name.String
+		ns.MatchType = matchType.String
+		ns.MatchedCanonical = matchedCanonical.String
+		ns.CurrentCanonical = currentCanonical.String
+		ns.Classification = cl.String
+		ns.ClassificationRanks = clRanks.String
+		ns.ClassificationIDs = clIDs.String
+		ns.DataSourceID = int(dsID.Int32)
+
+		if k := kingdom(ns.Classification, ns.ClassificationRanks); k != "" {
+			res.Kingdoms[k]++
+		}
+		res.OccurrencesNum += ns.OccurrencesNum
+		res.Names = append(res.Names, &ns)
+	}
+	if err = rows.Err(); err != nil {
+		err = dbError("names are not found", err)
+		slog.Error("Cannot read names list", "error", err)
+		return nil, err
+	}
+	res.NamesNum = len(res.Names)
+	return &res, nil
+}
+
+// kingdom returns the kingdom from a pipe-delimited classification and its
+// ranks, or an empty string if the kingdom is unknown.
+func kingdom(cl, ranks string) string {
+	if cl == "" || ranks == "" {
+		return ""
+	}
+	taxa := strings.Split(cl, "|")
+	for i, v := range strings.Split(ranks, "|") {
+		if i >= len(taxa) {
+			break
+		}
+		if strings.ToLower(v) == "kingdom" {
+			return taxa[i]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,29 @@
+package reffndio
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKingdom(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		msg, cl, ranks, res string
+	}{
+		{
+			"animal",
+			"Biota|Animalia|Arthropoda|Arachnida",
+			"unranked|kingdom|phylum|class",
+			"Animalia",
+		},
+		{"capitalized rank", "Plantae|Tracheophyta", "Kingdom|Phylum", "Plantae"},
+		{"no kingdom", "Arthropoda|Arachnida", "phylum|class", ""},
+		{"short classification", "Biota", "unranked|kingdom", ""},
+		{"empty", "", "", ""},
+	}
+
+	for _, v := range tests {
+		assert.Equal(v.res, kingdom(v.cl, v.ranks), v.msg)
+	}
+}
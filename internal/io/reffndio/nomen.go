@@ -0,0 +1,134 @@
+package reffndio
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
+	"github.com/gnames/gnparser"
+)
+
+// nomenOccurrences collects occurrences for a nomenclatural event search
+// of a name that is not in the Catalogue of Life cache. Besides the name
+// itself it uses names with the same stem (for example names with a
+// different gender of the epithet) and names of the same taxon with the
+// same stem of the last epithet (original combinations and other
+// variants). Annotations and years of the occurrences are taken into
+// account later by scoring.
+func (rf reffndio) nomenOccurrences(
+	ctx context.Context,
+	nameRefs *bhl.RefsByName,
+	stem string,
+) ([]*refRec, error) {
+	if stem == "" {
+		return rf.nameOnlyOccurrences(ctx, nameRefs, stem)
+	}
+	names, err := rf.nomenVariants(ctx, stem, nameRefs.CurrentCanonical)
+	if err != nil {
+		return nil, err
+	}
+	gnp := <-rf.gnpPool
+	names = filterVariants(gnp, nameRefs.Canonical, stem, names)
+	rf.gnpPool <- gnp
+
+	nq := variantsQuery(names, nameRefs.Canonical)
+	res, err := rf.occurrences(ctx, nq, nameRefs.Input.Filter)
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("Live nomenclatural event search",
+		"name", nameRefs.Canonical, "variants", names,
+		"occurrences", len(res),
+	)
+	return res, nil
+}
+
+// nomenVariants returns canonical forms with the same stem and canonical
+// forms of names that belong to the same taxon.
+func (rf reffndio) nomenVariants(
+	ctx context.Context,
+	stem, current string,
+) ([]string, error) {
+	q := `
+SELECT matched_canonical
+	FROM name_strings
+	WHERE matched_canonical_stem = $1
+UNION
+SELECT matched_canonical
+	FROM name_strings
+	WHERE current_canonical = $2
+`
+	ctx, cancel := rf.queryCtx(ctx)
+	defer cancel()
+	rows, err := rf.db.Query(ctx, q, stem, current)
+	if err != nil {
+		err = dbError("name variants are not found", err)
+		slog.Error("Cannot run name variants query", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []string
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			slog.Error("Cannot scan name variant", "error", err)
+			return nil, err
+		}
+		res = append(res, name)
+	}
+	if err = rows.Err(); err != nil {
+		err = dbError("name variants are not found", err)
+		slog.Error("Cannot read name variants", "error", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+// filterVariants keeps the canonical form of the name, names with the
+// same stem and names with the same stem of the last epithet.
+func filterVariants(
+	gnp gnparser.GNparser,
+	canonical, stem string,
+	names []string,
+) []string {
+	res := []string{canonical}
+	epithet := lastWord(stem)
+	isUninomial := !strings.Contains(stem, " ")
+	for _, v := range names {
+		if v == canonical {
+			continue
+		}
+		vStem := stemCanonical(gnp, v)
+		if vStem == stem ||
+			(!isUninomial && strings.Contains(vStem, " ") &&
+				lastWord(vStem) == epithet) {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// stemCanonical returns the stemmed canonical form of a name or an empty
+// string if the name cannot be parsed.
+func stemCanonical(gnp gnparser.GNparser, name string) string {
+	if name == "" {
+		return ""
+	}
+	p := gnp.ParseName(name)
+	if !p.Parsed || p.Canonical == nil {
+		return ""
+	}
+	return p.Canonical.Stemmed
+}
+
+func lastWord(s string) string {
+	return s[strings.LastIndex(s, " ")+1:]
+}
+
+// escapeLike escapes special characters of the LIKE pattern.
+func escapeLike(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return r.Replace(s)
+}
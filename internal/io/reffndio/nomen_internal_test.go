@@ -0,0 +1,48 @@
+package reffndio
+
+import (
+	"testing"
+
+	"github.com/gnames/gnparser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterVariants(t *testing.T) {
+	assert := assert.New(t)
+	gnp := gnparser.New(gnparser.NewConfig())
+	tests := []struct {
+		msg, canonical string
+		names, res     []string
+	}{
+		{
+			"stem",
+			"Pardosa moesta",
+			[]string{"Pardosa moesta", "Pardosa moestus", "Pardosa moestella"},
+			[]string{"Pardosa moesta", "Pardosa moestus"},
+		},
+		{
+			"original combination",
+			"Pardosa moesta",
+			[]string{"Lycosa moesta", "Lycosa borealis", "Pardosa"},
+			[]string{"Pardosa moesta", "Lycosa moesta"},
+		},
+		{
+			"uninomial",
+			"Pardosa",
+			[]string{"Pardosa", "Pardosa moesta", "Lycosa"},
+			[]string{"Pardosa"},
+		},
+	}
+
+	for _, v := range tests {
+		stem := stemCanonical(gnp, v.canonical)
+		res := filterVariants(gnp, v.canonical, stem, v.names)
+		assert.Equal(v.res, res, v.msg)
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(`Bubo bub`, escapeLike("Bubo bub"))
+	assert.Equal(`a\%b\_c\\`, escapeLike(`a%b_c\`))
+}
@@ -0,0 +1,99 @@
+package reffndio
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/reffnd"
+)
+
+// pageNames returns all name occurrences of a page, sorted by their
+// offsets.
+func (rf reffndio) pageNames(
+	ctx context.Context,
+	pageID int,
+) (*bhl.PageNames, error) {
+	if pageID < 1 {
+		msg := fmt.Sprintf("page ID %d is not a positive number", pageID)
+		return nil, reffnd.NewError(reffnd.ErrInvalidInput, msg, nil)
+	}
+
+	ctx, cancel := rf.queryCtx(ctx)
+	defer cancel()
+
+	res := bhl.PageNames{PageID: pageID, URL: getURL(pageID)}
+	var pageNum sql.NullInt64
+	err := rf.db.QueryRow(ctx,
+		"SELECT item_id, page_num FROM pages WHERE id = $1", pageID,
+	).Scan(&res.ItemID, &pageNum)
+	if err != nil {
+		err = dbError(fmt.Sprintf("page %d is not found", pageID), err)
+		slog.Error("Cannot find page", "page_id", pageID, "err", err)
+		return nil, err
+	}
+	res.PageNum = int(pageNum.Int64)
+
+	q := `SELECT
+  pns.offset_start, pns.offset_end, pns.odds_log10, pns.annot_nomen,
+  ns.name, ns.match_type, ns.edit_distance, ns.matched_name,
+  ns.matched_canonical, ns.current_name, ns.current_canonical,
+  ns.classification, ns.classification_ranks, ns.classification_ids,
+  ns.data_source_id, ns.data_source_title
+	FROM name_occurrences pns
+		JOIN name_strings ns ON ns.id = pns.name_string_id
+	WHERE pns.page_id = $1
+	ORDER BY pns.offset_start, pns.offset_end`
+
+	rows, err := rf.db.Query(ctx, q, pageID)
+	if err != nil {
+		err = dbError("page names are not found", err)
+		slog.Error("Cannot run page names query", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	res.Names = make([]*bhl.PageName, 0)
+	for rows.Next() {
+		var annot, name, matchType, matchedName, matchedCanonical,
+			currentName, currentCanonical, cl, clRanks, clIDs,
+			dsTitle sql.NullString
+		var editDistance sql.NullInt16
+		var dsID sql.NullInt32
+		var pn bhl.PageName
+		err = rows.Scan(&pn.OffsetStart, &pn.OffsetEnd, &pn.OddsLog10, &annot,
+			&name, &matchType, &editDistance, &matchedName,
+			&matchedCanonical, &currentName, &currentCanonical,
+			&cl, &clRanks, &clIDs,
+			&dsID, &dsTitle,
+		)
+		if err != nil {
+			err = fmt.Errorf("reffinderio.pageNames: %w", err)
+			slog.Error("Cannot scan row", "error", err)
+			return nil, err
+		}
+		pn.AnnotNomen = annot.String
+		pn.Name = name.String
+		pn.MatchType = matchType.String
+		pn.EditDistance = int(editDistance.Int16)
+		pn.MatchedName = matchedName.String
+		pn.MatchedCanonical = matchedCanonical.String
+		pn.CurrentName = currentName.String
+		pn.CurrentCanonical = currentCanonical.String
+		pn.Classification = cl.String
+		pn.ClassificationRanks = clRanks.String
+		pn.ClassificationIDs = clIDs.String
+		pn.DataSourceID = int(dsID.Int32)
+		pn.DataSourceTitle = dsTitle.String
+		res.Names = append(res.Names, &pn)
+	}
+	if err = rows.Err(); err != nil {
+		err = dbError("page names are not found", err)
+		slog.Error("Cannot read page names", "error", err)
+		return nil, err
+	}
+	res.NamesNum = len(res.Names)
+	return &res, nil
+}
@@ -2,19 +2,21 @@ package reffndio
 
 import (
 	"cmp"
+	"context"
 	"fmt"
 	"log/slog"
 	"slices"
 	"strconv"
 
-	"github.com/gnames/bhlnames/internal/ent/bhl"
-	"github.com/gnames/bhlnames/internal/ent/input"
 	"github.com/gnames/bhlnames/internal/ent/model"
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/input"
 )
 
 // deduplicateResults makes sure that every item part and title get only one unique
 // name to avoid information overload.
 func (rf reffndio) deduplicateResults(
+	ctx context.Context,
 	inp input.Input,
 	o *bhl.RefsByName,
 	raw []*refRec,
@@ -27,7 +29,7 @@ func (rf reffndio) deduplicateResults(
 
 	var preRefs []*preReference
 	for _, v := range raw {
-		part, err := rf.partByID(v.pageID)
+		part, err := rf.partByID(ctx, v.pageID)
 		if err != nil {
 			slog.Error("Cannot find part", "pageID", v.pageID, "error", err)
 			return err
@@ -72,6 +74,7 @@ func (rf reffndio) deduplicateResults(
 		preRefs = append(preRefs, v)
 	}
 	refs := rf.getReferences(preRefs, inp.SortDesc)
+	refs = filterYears(refs, inp.Filter)
 	if inp.WithTaxon {
 		o.Synonyms = getSynonyms(refs, o.CurrentCanonical)
 	}
@@ -81,6 +84,24 @@ func (rf reffndio) deduplicateResults(
 	return nil
 }
 
+// filterYears removes references with the aggregated year outside of the
+// filter's range. The database query uses years of items and titles,
+// so references with a year of their part need this final check.
+func filterYears(
+	refs []*bhl.ReferenceName,
+	f input.Filter,
+) []*bhl.ReferenceName {
+	if f.YearFrom == 0 && f.YearTo == 0 {
+		return refs
+	}
+	return slices.DeleteFunc(refs, func(r *bhl.ReferenceName) bool {
+		if f.YearFrom > 0 && r.YearAggr < f.YearFrom {
+			return true
+		}
+		return f.YearTo > 0 && r.YearAggr > f.YearTo
+	})
+}
+
 func genMapID(id uint, name string) string {
 	return strconv.Itoa(int(id)) + "-" + name
 }
@@ -118,10 +139,12 @@ func (rf reffndio) getReferences(
 		yr, tp := getYearAggr(v)
 		res[i] = &bhl.ReferenceName{
 			NameData: &bhl.NameData{
-				Name:         v.item.name,
-				MatchedName:  v.item.matchedCanonical,
-				AnnotNomen:   v.item.annotation,
-				EditDistance: v.item.editDistance,
+				Name:              v.item.name,
+				MatchedName:       v.item.matchedCanonical,
+				AnnotNomen:        v.item.annotation,
+				EditDistance:      v.item.editDistance,
+				NameMatch:         v.item.nameMatch,
+				QueryEditDistance: v.item.queryEditDistance,
 			},
 			Reference: bhl.Reference{
 				YearAggr:       yr,
@@ -157,7 +180,11 @@ func (rf reffndio) getReferences(
 		}
 	}
 
+	// page ID makes the order stable, which is important for pagination
 	slices.SortStableFunc(res, func(a, b *bhl.ReferenceName) int {
+		if a.YearAggr == b.YearAggr {
+			return cmp.Compare(a.PageID, b.PageID)
+		}
 		if desc {
 			return cmp.Compare(b.YearAggr, a.YearAggr)
 		} else {
@@ -6,13 +6,14 @@ import (
 	"fmt"
 	"log/slog"
 	"net/url"
+	"time"
 
 	"github.com/gnames/aho_corasick"
-	"github.com/gnames/bhlnames/internal/ent/bhl"
-	"github.com/gnames/bhlnames/internal/ent/input"
-	"github.com/gnames/bhlnames/internal/ent/reffnd"
 	"github.com/gnames/bhlnames/internal/io/dbio"
 	"github.com/gnames/bhlnames/pkg/config"
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/input"
+	"github.com/gnames/bhlnames/pkg/ent/reffnd"
 	"github.com/gnames/gnfmt"
 	"github.com/gnames/gnparser"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -28,8 +29,12 @@ type reffndio struct {
 	// enc is an encoder/decoder for serializing/deserializing data.
 	enc gnfmt.Encoder
 
-	// ctx is a placeholder for database queries, for now it is "empty".
-	ctx context.Context
+	// queryTimeout limits the duration of database queries.
+	queryTimeout time.Duration
+
+	// gnpPool is a pool of gnparser instances used to compare stems of
+	// name variants.
+	gnpPool chan gnparser.GNparser
 }
 
 func New(cfg config.Config) (reffnd.RefFinder, error) {
@@ -41,14 +46,16 @@ func New(cfg config.Config) (reffnd.RefFinder, error) {
 	}
 
 	res := &reffndio{
-		db:  dbConn,
-		enc: gnfmt.GNgob{},
-		ctx: context.Background(),
+		db:           dbConn,
+		enc:          gnfmt.GNgob{},
+		queryTimeout: time.Duration(cfg.DbQueryTimeout) * time.Second,
+		gnpPool:      gnparser.NewPool(gnparser.NewConfig(), cfg.JobsNum),
 	}
 	return res, nil
 }
 
 func (rf reffndio) ReferencesByName(
+	ctx context.Context,
 	inp input.Input,
 	cfg config.Config) (*bhl.RefsByName, error) {
 	var err error
@@ -56,8 +63,9 @@ func (rf reffndio) ReferencesByName(
 	// gets empty *namerefs.NameRefs with current_canonical
 	res := rf.EmptyNameRefs(inp)
 
-	res.Canonical, _ = simpleCanonical(inp.NameString)
-	res.CurrentCanonical, err = rf.currentCanonical(res.Canonical)
+	var stem string
+	res.Canonical, stem, _ = parseCanonical(inp.NameString)
+	res.CurrentCanonical, err = rf.currentCanonical(ctx, res.Canonical)
 	if err != nil {
 		slog.Error(
 			"Could not get current canonical",
@@ -68,52 +76,75 @@ func (rf reffndio) ReferencesByName(
 
 	var refRecs []*refRec
 
-	if inp.Reference == nil && inp.WithNomenEvent {
-		res, err = rf.colNomen(inp)
+	isLiveNomen := inp.Reference == nil && inp.WithNomenEvent
+
+	// precalculated CoL results do not know about filters
+	if isLiveNomen && inp.Filter.IsEmpty() {
+		colRes, err := rf.colNomen(ctx, inp)
 		if err != nil {
 			slog.Error("Cannot get nomenclatural reference from CoL", "error", err)
 			return nil, err
 		}
-		return res, nil
+		if colRes != nil {
+			return colRes, nil
+		}
 	}
 
-	if inp.WithTaxon {
-		refRecs, err = rf.taxonOccurrences(res)
+	switch {
+	case isLiveNomen:
+		res.NomenEventLive = true
+		refRecs, err = rf.nomenOccurrences(ctx, res, stem)
 		if err != nil {
 			return nil, err
 		}
-	} else {
-		refRecs, err = rf.nameOnlyOccurrences(res)
+	case inp.WithTaxon:
+		refRecs, err = rf.taxonOccurrences(ctx, res)
 		if err != nil {
 			return nil, err
 		}
+	default:
+		refRecs, err = rf.nameOnlyOccurrences(ctx, res, stem)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = rf.deduplicateResults(ctx, inp, res, refRecs)
+	if err != nil {
+		return nil, err
 	}
-	rf.deduplicateResults(inp, res, refRecs)
 	return res, nil
 }
 
-func (rf *reffndio) RefByPageID(pageID int) (*bhl.Reference, error) {
+func (rf *reffndio) RefByPageID(
+	ctx context.Context,
+	pageID int,
+) (*bhl.Reference, error) {
 	var ref *bhl.Reference
-	ref, err := rf.refByPageID(pageID)
+	ref, err := rf.refByPageID(ctx, pageID)
 	if err != nil {
 		return ref, err
 	}
 	return ref, nil
 }
 
+func (rf *reffndio) PageNames(
+	ctx context.Context,
+	pageID int,
+) (*bhl.PageNames, error) {
+	return rf.pageNames(ctx, pageID)
+}
+
 func (rf *reffndio) RefsByExtID(
+	ctx context.Context,
 	extID string,
 	dataSourceID int,
 ) (*bhl.RefsByName, error) {
 	_ = dataSourceID // not used yet, here for future use
 
-	bs, err := rf.refsByExtID(extID)
+	bs, err := rf.refsByExtID(ctx, extID)
 	if err != nil {
 		return nil, err
 	}
-	if bs == nil {
-		return nil, nil
-	}
 
 	var res bhl.RefsByName
 	err = rf.enc.Decode(bs, &res)
@@ -124,8 +155,11 @@ func (rf *reffndio) RefsByExtID(
 	return &res, nil
 }
 
-func (rf *reffndio) ItemStats(itemID int) (*bhl.Item, error) {
-	res, err := rf.itemStats(itemID)
+func (rf *reffndio) ItemStats(
+	ctx context.Context,
+	itemID, taxaLimit int,
+) (*bhl.Item, error) {
+	res, err := rf.itemStats(ctx, itemID, taxaLimit)
 	if err != nil {
 		return nil, err
 	}
@@ -133,10 +167,42 @@ func (rf *reffndio) ItemStats(itemID int) (*bhl.Item, error) {
 	return res, nil
 }
 
-// ItemsByTaxon returns a collection of BHL items that contain more than
-// 50% of the species of the profided taxon.
-func (rf *reffndio) ItemsByTaxon(taxon string) ([]*bhl.Item, error) {
-	items, err := rf.itemsByTaxon(taxon)
+func (rf *reffndio) ItemNames(
+	ctx context.Context,
+	itemID int,
+) (*bhl.NameList, error) {
+	return rf.itemNames(ctx, itemID)
+}
+
+func (rf *reffndio) PartNames(
+	ctx context.Context,
+	partID int,
+) (*bhl.NameList, error) {
+	return rf.partNames(ctx, partID)
+}
+
+func (rf *reffndio) Title(
+	ctx context.Context,
+	titleID int,
+) (*bhl.Title, error) {
+	return rf.title(ctx, titleID)
+}
+
+func (rf *reffndio) TitleItems(
+	ctx context.Context,
+	titleID int,
+	f input.ItemsFilter,
+) ([]*bhl.Item, error) {
+	return rf.titleItems(ctx, titleID, f)
+}
+
+// ItemsByTaxon returns a collection of BHL items where the taxon is the
+// most prevalent one according to the query.
+func (rf *reffndio) ItemsByTaxon(
+	ctx context.Context,
+	iq input.ItemsQuery,
+) ([]*bhl.Item, error) {
+	items, err := rf.itemsByTaxon(ctx, iq)
 	if err != nil {
 		return nil, err
 	}
@@ -144,10 +210,34 @@ func (rf *reffndio) ItemsByTaxon(taxon string) ([]*bhl.Item, error) {
 	return items, nil
 }
 
+// DataInfo returns datasets and the latest builds of the data.
+func (rf *reffndio) DataInfo(ctx context.Context) (*bhl.DataInfo, error) {
+	return rf.dataInfo(ctx)
+}
+
+// queryCtx returns a context for a database query, limited by the query
+// timeout if it is set.
+func (rf reffndio) queryCtx(
+	ctx context.Context,
+) (context.Context, context.CancelFunc) {
+	if rf.queryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, rf.queryTimeout)
+}
+
 func (rf *reffndio) Close() {
 	rf.db.Close()
 }
 
+func (rf *reffndio) ReferencesByCitation(
+	ctx context.Context,
+	ref input.Reference,
+	titleIDs []int,
+) ([]*bhl.ReferenceName, error) {
+	return rf.referencesByCitation(ctx, ref, titleIDs)
+}
+
 func (rf *reffndio) EmptyNameRefs(inp input.Input) *bhl.RefsByName {
 	meta := bhl.Meta{
 		Input: inp,
@@ -159,7 +249,8 @@ func (rf *reffndio) EmptyNameRefs(inp input.Input) *bhl.RefsByName {
 	return res
 }
 
-func simpleCanonical(name_string string) (string, error) {
+// parseCanonical returns simple and stemmed canonical forms of a name.
+func parseCanonical(name_string string) (string, string, error) {
 	cfg := gnparser.NewConfig()
 	gnp := gnparser.New(cfg)
 	ps := gnp.ParseName(name_string)
@@ -170,10 +261,9 @@ func simpleCanonical(name_string string) (string, error) {
 			"name_string", name_string,
 			"error", err,
 		)
-		return "", err
+		return "", "", err
 	}
-	can := ps.Canonical.Simple
-	return can, nil
+	return ps.Canonical.Simple, ps.Canonical.Stemmed, nil
 }
 
 func imagesUrl(name string) string {
@@ -0,0 +1,231 @@
+package reffndio
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/input"
+	"github.com/gnames/bhlnames/pkg/ent/reffnd"
+)
+
+// mainTaxaLimit is the maximum number of main taxa for each rank in the
+// profile of a title.
+const mainTaxaLimit = 10
+
+// title returns metadata of a title and the taxonomic profile of its
+// items. Titles are not kept in a separate table, their data are
+// collected from items.
+func (rf reffndio) title(
+	ctx context.Context,
+	titleID int,
+) (*bhl.Title, error) {
+	if titleID < 1 {
+		msg := fmt.Sprintf("title ID %d is not a positive number", titleID)
+		return nil, reffnd.NewError(reffnd.ErrInvalidInput, msg, nil)
+	}
+
+	q := `SELECT
+  min(item.title_name), min(item.title_doi),
+  min(item.title_year_start), max(item.title_year_end), min(item.title_lang),
+  count(*), min(item.year_start), max(GREATEST(item.year_start, item.year_end)),
+  coalesce(sum(ist.names_total), 0)::bigint,
+  coalesce(sum(ist.animalia_num), 0)::bigint,
+  coalesce(sum(ist.plantae_num), 0)::bigint,
+  coalesce(sum(ist.fungi_num), 0)::bigint,
+  coalesce(sum(ist.bacteria_num), 0)::bigint
+	FROM items item
+		LEFT JOIN item_stats ist ON item.id = ist.id
+	WHERE item.title_id = $1
+	HAVING count(*) > 0`
+
+	ctx, cancel := rf.queryCtx(ctx)
+	defer cancel()
+
+	res := bhl.Title{TitleID: titleID}
+	p := &res.Profile
+	err := rf.db.QueryRow(ctx, q, titleID).Scan(
+		&res.TitleName, &res.TitleDOI, &res.TitleYearStart, &res.TitleYearEnd,
+		&res.Language, &res.ItemsNum, &res.ItemsYearStart, &res.ItemsYearEnd,
+		&p.NamesNum, &p.AnimaliaNum, &p.PlantaeNum, &p.FungiNum, &p.BacteriaNum,
+	)
+	if err != nil {
+		err = dbError(fmt.Sprintf("title %d is not found", titleID), err)
+		slog.Error("Cannot find title", "title_id", titleID, "err", err)
+		return nil, err
+	}
+	setMainKingdom(p)
+
+	p.MainTaxa, err = rf.titleMainTaxa(ctx, titleID, res.ItemsNum)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// titleMainTaxa counts items of a title by their main taxa from phylum
+// to genus.
+func (rf reffndio) titleMainTaxa(
+	ctx context.Context,
+	titleID, itemsNum int,
+) ([]*bhl.TaxonItems, error) {
+	ranks := []string{"phylum", "class", "order", "family", "genus"}
+	var qs []string
+	for i, v := range ranks {
+		qs = append(qs, fmt.Sprintf(`SELECT %d AS rank_order, '%s' AS rank,
+    ist.main_%[2]s AS taxon, count(*) AS num
+  FROM items item
+    JOIN item_stats ist ON item.id = ist.id
+  WHERE item.title_id = $1 AND ist.main_%[2]s <> ''
+  GROUP BY ist.main_%[2]s`, i, v))
+	}
+
+	q := fmt.Sprintf(`SELECT rank, taxon, num FROM (
+  SELECT *, row_number() OVER (PARTITION BY rank ORDER BY num DESC, taxon) AS rn
+  FROM (%s) taxa
+) ranked
+WHERE rn <= %d
+ORDER BY rank_order, num DESC, taxon`,
+		strings.Join(qs, "\nUNION ALL\n"), mainTaxaLimit)
+
+	rows, err := rf.db.Query(ctx, q, titleID)
+	if err != nil {
+		err = dbError("main taxa are not found", err)
+		slog.Error("Cannot run title main taxa query", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*bhl.TaxonItems
+	for rows.Next() {
+		var ti bhl.TaxonItems
+		if err = rows.Scan(&ti.Rank, &ti.Taxon, &ti.ItemsNum); err != nil {
+			err = fmt.Errorf("reffinderio.titleMainTaxa: %w", err)
+			slog.Error("Cannot scan row", "error", err)
+			return nil, err
+		}
+		if itemsNum > 0 {
+			ti.ItemsPercent = ti.ItemsNum * 100 / itemsNum
+		}
+		res = append(res, &ti)
+	}
+	if err = rows.Err(); err != nil {
+		err = dbError("main taxa are not found", err)
+		slog.Error("Cannot read title main taxa", "error", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+// setMainKingdom finds the kingdom with the most names in the profile.
+func setMainKingdom(p *bhl.TitleProfile) {
+	kingdoms := []struct {
+		name string
+		num  int
+	}{
+		{"Animalia", p.AnimaliaNum},
+		{"Plantae", p.PlantaeNum},
+		{"Fungi", p.FungiNum},
+		{"Bacteria", p.BacteriaNum},
+	}
+
+	var total, top int
+	for _, v := range kingdoms {
+		total += v.num
+		if v.num > top {
+			top = v.num
+			p.MainKingdom = v.name
+		}
+	}
+	if total > 0 {
+		p.MainKingdomPercent = top * 100 / total
+	}
+}
+
+// titleItems returns items of a title with their taxonomic statistics.
+func (rf reffndio) titleItems(
+	ctx context.Context,
+	titleID int,
+	f input.ItemsFilter,
+) ([]*bhl.Item, error) {
+	if titleID < 1 {
+		msg := fmt.Sprintf("title ID %d is not a positive number", titleID)
+		return nil, reffnd.NewError(reffnd.ErrInvalidInput, msg, nil)
+	}
+
+	msg := fmt.Sprintf("title %d is not found", titleID)
+	err := rf.exists(ctx,
+		"SELECT 1 FROM items WHERE title_id = $1 LIMIT 1", titleID, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := itemsFilter(f, 2)
+	q := `
+SELECT` + itemFields + `
+	FROM items item
+		LEFT JOIN item_stats ist
+			ON item.id = ist.id
+	WHERE item.title_id = $1` + where + `
+	ORDER BY GREATEST(item.year_start, item.title_year_start), item.vol, item.id
+`
+	args = append([]any{titleID}, args...)
+
+	ctx, cancel := rf.queryCtx(ctx)
+	defer cancel()
+	rows, err := rf.db.Query(ctx, q, args...)
+	if err != nil {
+		err = dbError("items are not found", err)
+		slog.Error("Cannot run title items query", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]*bhl.Item, 0)
+	for rows.Next() {
+		itm, err := scanItem(rows)
+		if err != nil {
+			slog.Error("Cannot scan title item", "error", err)
+			return nil, err
+		}
+		res = append(res, itm)
+	}
+	if err = rows.Err(); err != nil {
+		err = dbError("items are not found", err)
+		slog.Error("Cannot read title items", "error", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+// itemsFilter creates additional conditions for the items query
+// from the filter. Placeholders of the conditions start from argNum.
+func itemsFilter(f input.ItemsFilter, argNum int) (string, []any) {
+	var conds []string
+	var args []any
+	add := func(cond string, arg any) {
+		conds = append(conds, fmt.Sprintf(cond, argNum))
+		args = append(args, arg)
+		argNum++
+	}
+
+	year := "GREATEST(item.year_start, item.title_year_start)"
+	if f.YearFrom > 0 {
+		add(year+" >= $%d", f.YearFrom)
+	}
+	if f.YearTo > 0 {
+		add(year+" <= $%d", f.YearTo)
+	}
+	// volume numbers must not match parts of other numbers
+	if f.Volume != "" {
+		add("item.vol ~* $%d", `(^|\D)`+regexp.QuoteMeta(f.Volume)+`(\D|$)`)
+	}
+
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return "\n    AND " + strings.Join(conds, "\n    AND "), args
+}
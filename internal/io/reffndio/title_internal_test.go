@@ -0,0 +1,72 @@
+package reffndio
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/input"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItemsFilter(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		msg   string
+		f     input.ItemsFilter
+		where string
+		args  []any
+	}{
+		{"empty", input.ItemsFilter{}, "", nil},
+		{
+			"years",
+			input.ItemsFilter{YearFrom: 1890, YearTo: 1900},
+			"\n    AND GREATEST(item.year_start, item.title_year_start) >= $2" +
+				"\n    AND GREATEST(item.year_start, item.title_year_start) <= $3",
+			[]any{1890, 1900},
+		},
+		{
+			"volume",
+			input.ItemsFilter{Volume: "1"},
+			"\n    AND item.vol ~* $2",
+			[]any{`(^|\D)1(\D|$)`},
+		},
+		{
+			"volume with dot",
+			input.ItemsFilter{Volume: "v.1"},
+			"\n    AND item.vol ~* $2",
+			[]any{`(^|\D)v\.1(\D|$)`},
+		},
+	}
+
+	for _, v := range tests {
+		where, args := itemsFilter(v.f, 2)
+		assert.Equal(v.where, where, v.msg)
+		assert.Equal(v.args, args, v.msg)
+	}
+}
+
+func TestItemsFilterVolume(t *testing.T) {
+	assert := assert.New(t)
+	_, args := itemsFilter(input.ItemsFilter{Volume: "1"}, 2)
+	re := regexp.MustCompile(args[0].(string))
+	for _, v := range []string{"1", "v.1", "v.1 (1890)", "t. 1-2"} {
+		assert.True(re.MatchString(v), v)
+	}
+	for _, v := range []string{"v.10", "v.21", "v.11 (1891)"} {
+		assert.False(re.MatchString(v), v)
+	}
+}
+
+func TestSetMainKingdom(t *testing.T) {
+	assert := assert.New(t)
+	p := bhl.TitleProfile{AnimaliaNum: 30, PlantaeNum: 60, FungiNum: 10}
+	setMainKingdom(&p)
+	assert.Equal("Plantae", p.MainKingdom)
+	assert.Equal(60, p.MainKingdomPercent)
+
+	p = bhl.TitleProfile{}
+	setMainKingdom(&p)
+	assert.Equal("", p.MainKingdom)
+	assert.Equal(0, p.MainKingdomPercent)
+}
@@ -0,0 +1,196 @@
+package restio
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	bhlnames "github.com/gnames/bhlnames/pkg"
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/input"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/sync/errgroup"
+)
+
+// mimeNDJSON is the content type of newline-delimited JSON.
+const mimeNDJSON = "application/x-ndjson"
+
+var (
+	errBatchEmpty    = errors.New("batch has no inputs")
+	errBatchTooLarge = errors.New("batch is too large")
+	errBatchInput    = errors.New("batch has an invalid input")
+)
+
+// nameRefsBatchPost takes many inputs and streams back the best matched
+// references for each of them.
+// @Summary Finds BHL references for a batch of names
+// @Description Takes a JSON array or newline-delimited JSON (NDJSON) of inputs and returns the results as NDJSON, one line per input. Results are sent as soon as they are ready, so their order might differ from the order of inputs. Use the `id` field of the inputs to correlate results. If an input has no `id`, it gets its position in the batch (starting from 1) as an ID. If the search fails for one of the inputs, its result has the `error` field set, and other inputs are still processed. The number of inputs is limited by the `MaxBatchSize` setting of the service.
+// @ID post-name-refs-batch
+// @Param input body []input.Input true "A list of inputs"
+// @Accept json
+// @Accept application/x-ndjson
+// @Produce application/x-ndjson
+// @Success 200 {object} bhl.RefsByName  "Matched references for one of the inputs, one JSON object per line"
+// @Failure 400 {object} rest.ErrorResponse "Malformed or empty batch, or an input with unknown parameters"
+// @Failure 413 {object} rest.ErrorResponse "Batch exceeds the maximum size"
+// @Router /name_refs_batch [post]
+func nameRefsBatchPost(
+	bn bhlnames.BHLnames,
+	maxSize int,
+) func(echo.Context) error {
+	return func(c echo.Context) error {
+		inps, err := readBatch(c.Request().Body, maxSize)
+		if errors.Is(err, errBatchTooLarge) {
+			return echo.NewHTTPError(http.StatusRequestEntityTooLarge, err.Error())
+		}
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		chIn := make(chan input.Input)
+		chOut := make(chan *bhl.RefsByName)
+		g, ctx := errgroup.WithContext(c.Request().Context())
+
+		g.Go(func() error {
+			defer close(chIn)
+			for _, inp := range inps {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case chIn <- inp:
+				}
+			}
+			return nil
+		})
+
+		g.Go(func() error {
+			return bn.NameRefsStream(ctx, chIn, chOut)
+		})
+
+		resp := c.Response()
+		resp.Header().Set(echo.HeaderContentType, mimeNDJSON)
+		resp.WriteHeader(http.StatusOK)
+
+		var errWrite error
+		enc := json.NewEncoder(resp)
+		for res := range chOut {
+			// keep draining results if the client is gone, the
+			// request context stops the workers.
+			if errWrite != nil {
+				continue
+			}
+			if errWrite = enc.Encode(res); errWrite != nil {
+				slog.Warn("Cannot write batch result", "error", errWrite)
+				continue
+			}
+			resp.Flush()
+		}
+
+		if err = g.Wait(); err != nil {
+			slog.Error("Cannot finish batch of name references", "error", err)
+			return err
+		}
+		return nil
+	}
+}
+
+// readBatch reads inputs from a JSON array or from newline-delimited JSON.
+// It returns an error if the batch is empty, malformed, contains more
+// than maxSize inputs or an input with unknown parameters. Inputs without
+// ID get their position in the batch as an ID.
+func readBatch(r io.Reader, maxSize int) ([]input.Input, error) {
+	br := bufio.NewReader(r)
+	first, err := firstByte(br)
+	if err == io.EOF {
+		return nil, errBatchEmpty
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(br)
+	isArray := first == '['
+	if isArray {
+		// consume the opening bracket
+		if _, err = dec.Token(); err != nil {
+			return nil, err
+		}
+	}
+
+	var res []input.Input
+	for {
+		if isArray && !dec.More() {
+			break
+		}
+
+		var inp input.Input
+		err = dec.Decode(&inp)
+		if err == io.EOF && !isArray {
+			break
+		}
+		if err != nil {
+			err = fmt.Errorf("cannot decode input %d: %w", len(res)+1, err)
+			return nil, err
+		}
+
+		if maxSize > 0 && len(res) == maxSize {
+			err = fmt.Errorf("%w: maximum is %d inputs", errBatchTooLarge, maxSize)
+			return nil, err
+		}
+
+		if inp.ID == "" {
+			inp.ID = strconv.Itoa(len(res) + 1)
+		}
+		if err = validateInput(inp); err != nil {
+			err = fmt.Errorf("input %d (id '%s'): %w", len(res)+1, inp.ID, err)
+			return nil, err
+		}
+		res = append(res, inp)
+	}
+
+	if len(res) == 0 {
+		return nil, errBatchEmpty
+	}
+	return res, nil
+}
+
+// validateInput checks parameters of an input that come from users as
+// strings, so a wrong input fails the batch before results are streamed.
+func validateInput(inp input.Input) error {
+	if !inp.SortBy.IsValid() {
+		return fmt.Errorf(
+			"%w: sortBy '%s' is not a known sort order", errBatchInput, inp.SortBy,
+		)
+	}
+	if !inp.NameMatch.IsValid() {
+		return fmt.Errorf(
+			"%w: nameMatch '%s' is not a known name match", errBatchInput, inp.NameMatch,
+		)
+	}
+	if inp.MinRefMatchQuality > 0 && !inp.IsScored() {
+		return fmt.Errorf(
+			"%w: minRefMatchQuality needs a reference or nomenEvent", errBatchInput,
+		)
+	}
+	return nil
+}
+
+// firstByte returns the first non-whitespace byte without consuming it.
+func firstByte(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return b, br.UnreadByte()
+	}
+}
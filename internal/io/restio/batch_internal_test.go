@@ -0,0 +1,71 @@
+package restio
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadBatch(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		msg, body string
+		max       int
+		ids       []string
+		err       error
+	}{
+		{
+			"array",
+			` [{"id":"a","name":{"nameString":"Bubo bubo"}},
+			{"name":{"nameString":"Pardosa moesta"}}]`,
+			10, []string{"a", "2"}, nil,
+		},
+		{
+			"ndjson",
+			`{"id":"a","name":{"nameString":"Bubo bubo"}}` + "\n\n" +
+				`{"id":"b","name":{"nameString":"Pardosa moesta"}}` + "\n",
+			2, []string{"a", "b"}, nil,
+		},
+		{"empty", " \n", 10, nil, errBatchEmpty},
+		{"empty array", "[]", 10, nil, errBatchEmpty},
+		{"too large", `[{"id":"a"},{"id":"b"},{"id":"c"}]`, 2, nil, errBatchTooLarge},
+		{"too large ndjson", "{}\n{}\n{}\n", 2, nil, errBatchTooLarge},
+		{"no limit", "{}\n{}\n{}\n", 0, []string{"1", "2", "3"}, nil},
+		{
+			"bad sort", `[{"id":"a"},{"id":"b","params":{"sortBy":"bad"}}]`,
+			10, nil, errBatchInput,
+		},
+		{
+			"bad match", `{"params":{"nameMatch":"bad"}}`,
+			10, nil, errBatchInput,
+		},
+		{
+			"unscored quality", `{"params":{"filter":{"minRefMatchQuality":3}}}`,
+			10, nil, errBatchInput,
+		},
+		{
+			"scored quality",
+			`{"id":"a","params":{"nomenEvent":true,"filter":{"minRefMatchQuality":3}}}`,
+			10, []string{"a"}, nil,
+		},
+	}
+
+	for _, v := range tests {
+		res, err := readBatch(strings.NewReader(v.body), v.max)
+		if v.err != nil {
+			assert.True(errors.Is(err, v.err), v.msg)
+			continue
+		}
+		assert.Nil(err, v.msg)
+		assert.Equal(len(v.ids), len(res), v.msg)
+		for i := range res {
+			assert.Equal(v.ids[i], res[i].ID, v.msg)
+		}
+	}
+
+	_, err := readBatch(strings.NewReader(`[{"id":"a"}, bad]`), 10)
+	assert.NotNil(err)
+	assert.False(errors.Is(err, errBatchTooLarge))
+}
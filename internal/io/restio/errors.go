@@ -0,0 +1,96 @@
+package restio
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gnames/bhlnames/pkg/ent/reffnd"
+	"github.com/gnames/bhlnames/pkg/ent/rest"
+	"github.com/labstack/echo/v4"
+)
+
+// errorHandler sends errors to clients as JSON with a status that
+// corresponds to the kind of the error.
+func errorHandler(err error, c echo.Context) {
+	resp := c.Response()
+	reqID := resp.Header().Get(echo.HeaderXRequestID)
+	res := errorResponse(err)
+	res.RequestID = reqID
+
+	if res.Status >= http.StatusInternalServerError {
+		slog.Error("Request failed",
+			"status", res.Status, "request_id", reqID, "error", err)
+	} else {
+		slog.Warn("Request failed",
+			"status", res.Status, "request_id", reqID, "error", err)
+	}
+
+	// for example, streaming results already started
+	if resp.Committed {
+		return
+	}
+
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(res.Status)
+	} else {
+		err = c.JSON(res.Status, res)
+	}
+	if err != nil {
+		slog.Error("Cannot send error response", "error", err)
+	}
+}
+
+// errorResponse converts an error to the response body.
+func errorResponse(err error) rest.ErrorResponse {
+	var res rest.ErrorResponse
+	var httpErr *echo.HTTPError
+	var refErr *reffnd.Error
+
+	switch {
+	case errors.As(err, &httpErr):
+		res.Status = httpErr.Code
+		res.Message = fmt.Sprint(httpErr.Message)
+	case errors.As(err, &refErr):
+		res.Status = kindStatus(refErr.Kind)
+		res.Message = refErr.Message
+	case errors.Is(err, reffnd.ErrNotFound),
+		errors.Is(err, reffnd.ErrInvalidInput),
+		errors.Is(err, reffnd.ErrUnavailable):
+		res.Status = kindStatus(err)
+		res.Message = err.Error()
+	default:
+		res.Status = http.StatusInternalServerError
+		res.Message = http.StatusText(res.Status)
+	}
+	res.Code = statusCode(res.Status)
+	return res
+}
+
+// kindStatus returns HTTP status for a kind of RefFinder error.
+func kindStatus(kind error) int {
+	switch {
+	case errors.Is(kind, reffnd.ErrInvalidInput):
+		return http.StatusBadRequest
+	case errors.Is(kind, reffnd.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(kind, reffnd.ErrUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// statusCode converts HTTP status to a snake-case code, for example
+// 404 becomes "not_found".
+func statusCode(status int) string {
+	txt := http.StatusText(status)
+	if txt == "" {
+		return "unknown_error"
+	}
+	txt = strings.ToLower(txt)
+	txt = strings.ReplaceAll(txt, "-", "_")
+	return strings.ReplaceAll(txt, " ", "_")
+}
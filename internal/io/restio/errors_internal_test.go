@@ -0,0 +1,84 @@
+package restio
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gnames/bhlnames/pkg/ent/reffnd"
+	"github.com/gnames/bhlnames/pkg/ent/rest"
+	"github.com/gnames/gnfmt"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorResponse(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		msg    string
+		err    error
+		status int
+		code   string
+		txt    string
+	}{
+		{
+			"http", echo.NewHTTPError(http.StatusBadRequest, "bad page_id"),
+			400, "bad_request", "bad page_id",
+		},
+		{
+			"not found",
+			reffnd.NewError(reffnd.ErrNotFound, "page 1 is not found", errors.New("no rows")),
+			404, "not_found", "page 1 is not found",
+		},
+		{
+			"wrapped invalid",
+			fmt.Errorf("ItemStats: %w", reffnd.NewError(reffnd.ErrInvalidInput, "bad id", nil)),
+			400, "bad_request", "bad id",
+		},
+		{
+			"unavailable",
+			reffnd.NewError(reffnd.ErrUnavailable, "database is unavailable", nil),
+			503, "service_unavailable", "database is unavailable",
+		},
+		{
+			"unknown", errors.New("secret details"),
+			500, "internal_server_error", "Internal Server Error",
+		},
+	}
+
+	for _, v := range tests {
+		res := errorResponse(v.err)
+		assert.Equal(v.status, res.Status, v.msg)
+		assert.Equal(v.code, res.Code, v.msg)
+		assert.Equal(v.txt, res.Message, v.msg)
+	}
+}
+
+func TestErrorHandler(t *testing.T) {
+	assert := assert.New(t)
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Response().Header().Set(echo.HeaderXRequestID, "req1")
+
+	err := reffnd.NewError(reffnd.ErrNotFound, "item 2 is not found", nil)
+	errorHandler(err, c)
+	assert.Equal(http.StatusNotFound, rec.Code)
+	assert.True(strings.HasPrefix(
+		rec.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON,
+	))
+
+	var res rest.ErrorResponse
+	err = gnfmt.GNjson{}.Decode(rec.Body.Bytes(), &res)
+	assert.Nil(err)
+	assert.Equal(rest.ErrorResponse{
+		Status:    404,
+		Code:      "not_found",
+		Message:   "item 2 is not found",
+		RequestID: "req1",
+	}, res)
+}
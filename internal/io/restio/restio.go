@@ -8,11 +8,11 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/gnames/bhlnames/internal/ent/bhl"
-	"github.com/gnames/bhlnames/internal/ent/input"
-	"github.com/gnames/bhlnames/internal/ent/rest"
 	bhlnames "github.com/gnames/bhlnames/pkg"
 	"github.com/gnames/bhlnames/pkg/config"
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/input"
+	"github.com/gnames/bhlnames/pkg/ent/rest"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	echoSwagger "github.com/swaggo/echo-swagger"
@@ -33,11 +33,45 @@ func New(bn bhlnames.BHLnames) rest.REST {
 		cfg: bn.Config(),
 	}
 	res.Echo = echo.New()
+	res.HTTPErrorHandler = errorHandler
+	res.Use(middleware.RequestID())
 	res.Use(middleware.Gzip())
 	res.Use(middleware.CORS())
+	res.setRoutes()
 	return &res
 }
 
+// setRoutes registers the API end-points.
+func (r *restio) setRoutes() {
+	r.GET("/", info)
+	r.GET("/apidoc/*", echoSwagger.WrapHandler)
+	r.GET("/api", info)
+	r.GET(apiPath, info)
+	r.GET(apiPath+"/ping", ping)
+	r.GET(apiPath+"/version", ver())
+	r.GET(apiPath+"/cache_stats", cacheStats(r.bn))
+	r.GET(apiPath+"/data_info", dataInfo(r.bn))
+	r.GET(apiPath+"/references/:page_id", refs(r.bn))
+	r.GET(apiPath+"/pages/:page_id/names", pageNamesGet(r.bn))
+	r.GET(apiPath+"/items/:item_id", itemStatsGet(r.bn))
+	r.GET(apiPath+"/items/:item_id/names", itemNamesGet(r.bn))
+	r.GET(apiPath+"/parts/:part_id/names", partNamesGet(r.bn))
+	r.GET(apiPath+"/titles/:title_id", titleGet(r.bn))
+	r.GET(apiPath+"/titles/:title_id/items", titleItemsGet(r.bn))
+	r.GET(apiPath+"/name_refs/:name", nameRefsGet(r.bn))
+	r.POST(apiPath+"/name_refs", nameRefsPost(r.bn))
+	r.POST(apiPath+"/name_refs_batch", nameRefsBatchPost(r.bn, r.cfg.MaxBatchSize))
+	r.POST(apiPath+"/parse_ref", parseRefPost(r.bn))
+	r.POST(apiPath+"/resolve_ref", resolveRefPost(r.bn))
+	r.GET(apiPath+"/cached_refs/:external_id", externalIDGet(r.bn))
+	r.GET(apiPath+"/taxon_items/:taxon_name", itemsByTaxonGet(r.bn))
+}
+
+// Handler returns the HTTP handler of the API.
+func (r *restio) Handler() http.Handler {
+	return r.Echo
+}
+
 // @title BHLnames API
 // @version 1.0
 // @description This API serves the BHLnames app. It locates relevant sections in the Biodiversity Heritage Library that correspond provided names, references or pages.
@@ -68,19 +102,6 @@ func New(bn bhlnames.BHLnames) rest.REST {
 func (r *restio) Run() {
 	slog.Info("Starting the HTTP API server.", "port", r.cfg.PortREST)
 
-	r.GET("/", info)
-	r.GET("/apidoc/*", echoSwagger.WrapHandler)
-	r.GET("/api", info)
-	r.GET(apiPath, info)
-	r.GET(apiPath+"/ping", ping)
-	r.GET(apiPath+"/version", ver())
-	r.GET(apiPath+"/references/:page_id", refs(r.bn))
-	r.GET(apiPath+"/items/:item_id", itemStatsGet(r.bn))
-	r.GET(apiPath+"/name_refs/:name", nameRefsGet(r.bn))
-	r.POST(apiPath+"/name_refs", nameRefsPost(r.bn))
-	r.GET(apiPath+"/cached_refs/:external_id", externalIDGet(r.bn))
-	r.GET(apiPath+"/taxon_items/:taxon_name", itemsByTaxonGet(r.bn))
-
 	addr := fmt.Sprintf(":%d", r.cfg.PortREST)
 	s := &http.Server{
 		Addr:         addr,
@@ -128,6 +149,38 @@ func ver() func(echo.Context) error {
 	}
 }
 
+// cacheStats returns usage statistics of the name-references cache.
+// @Summary Get statistics of the results cache
+// @Description Returns the type of the name-references cache and the number of its hits and misses since the start of the service.
+// @ID get-cache-stats
+// @Produce json
+// @Success 200 {object} cache.Stats "Cache statistics"
+// @Router /cache_stats [get]
+func cacheStats(bn bhlnames.BHLnames) func(echo.Context) error {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, bn.CacheStats())
+	}
+}
+
+// dataInfo returns provenance of the data.
+// @Summary Get versions of datasets behind the data
+// @Description Returns datasets the data were built from (source URLs, file sizes, SHA-256 checksums and dates of dumps), the latest builds with the bhlnames version, duration and numbers of rows in tables, and the version of the running service. It allows to cite the exact version of the data behind a result.
+// @ID get-data-info
+// @Produce json
+// @Success 200 {object} bhl.DataInfo "Provenance of the data"
+// @Failure 404 {object} rest.ErrorResponse "Data were built by an older version"
+// @Failure 503 {object} rest.ErrorResponse "Database is unavailable"
+// @Router /data_info [get]
+func dataInfo(bn bhlnames.BHLnames) func(echo.Context) error {
+	return func(c echo.Context) error {
+		res, err := bn.DataInfo(c.Request().Context())
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
 // refs takes pageID and returns corresponding BHL reference metadata.
 // @Summary Get BHL reference metadata by pageID
 // @Description Retrieves the BHL reference metadata by pageID.
@@ -136,15 +189,48 @@ func ver() func(echo.Context) error {
 // @Produce json
 // @Param page_id path integer true "Page ID of a reference." example(6589171)
 // @Success 200 {object} bhl.Reference "Successful response with data about the reference"
+// @Failure 400 {object} rest.ErrorResponse "Invalid page ID"
+// @Failure 404 {object} rest.ErrorResponse "Page not found"
+// @Failure 503 {object} rest.ErrorResponse "Database is unavailable"
 // @Router /references/{page_id} [get]
 func refs(bn bhlnames.BHLnames) func(echo.Context) error {
 	return func(c echo.Context) error {
 		pageIDStr := c.Param("page_id")
 		pageID, err := strconv.Atoi(pageIDStr)
+		if err != nil {
+			msg := fmt.Sprintf("page_id '%s' is not an integer", pageIDStr)
+			return echo.NewHTTPError(http.StatusBadRequest, msg)
+		}
+		res, err := bn.RefByPageID(c.Request().Context(), pageID)
 		if err != nil {
 			return err
 		}
-		res, err := bn.RefByPageID(pageID)
+
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
+// pageNamesGet takes pageID and returns all names detected on the page.
+// @Summary Get scientific names detected on a BHL page
+// @Description Lists every name detected on a page with its offsets in the page text, matched and current canonical forms, classification, match type and nomenclatural annotation. Offsets allow to highlight names in OCR text and page images.
+// @ID get-page-names
+// @Accept plain
+// @Produce json
+// @Param page_id path integer true "BHL page ID." example(6589171)
+// @Success 200 {object} bhl.PageNames "Names detected on the page"
+// @Failure 400 {object} rest.ErrorResponse "Invalid page ID"
+// @Failure 404 {object} rest.ErrorResponse "Page not found"
+// @Failure 503 {object} rest.ErrorResponse "Database is unavailable"
+// @Router /pages/{page_id}/names [get]
+func pageNamesGet(bn bhlnames.BHLnames) func(echo.Context) error {
+	return func(c echo.Context) error {
+		pageIDStr := c.Param("page_id")
+		pageID, err := strconv.Atoi(pageIDStr)
+		if err != nil {
+			msg := fmt.Sprintf("page_id '%s' is not an integer", pageIDStr)
+			return echo.NewHTTPError(http.StatusBadRequest, msg)
+		}
+		res, err := bn.PageNames(c.Request().Context(), pageID)
 		if err != nil {
 			return err
 		}
@@ -162,9 +248,24 @@ func refs(bn bhlnames.BHLnames) func(echo.Context) error {
 // @Param name path string true "Name to find references for." example("Pardosa moesta")
 // @Param reference query string false "Reference data used to filter results." example("Docums Mycol. 34(nos 135-136):50. (2008)")
 // @Param nomen_event query boolean false "If true, tries to find nomenclatural event reference." example(true)
+// @Param taxon query boolean false "If true, includes references of the taxon's synonyms." example(false)
+// @Param name_match query string false "How to compare names: exact, stem or fuzzy." example("stem")
+// @Param max_edit_distance query integer false "Maximum edit distance for the fuzzy name match (1-3)." example(1)
+// @Param sort query string false "Order of references: year, year_desc, odds, title or page." example("year")
+// @Param refs_limit query integer false "Maximum number of references to return." example(20)
+// @Param offset query integer false "Number of references to skip." example(20)
+// @Param year_from query integer false "Return references published in this year or later." example(1890)
+// @Param year_to query integer false "Return references published in this year or earlier." example(1920)
+// @Param title_id query integer false "Return references only from this BHL title." example(7928)
+// @Param item_id query integer false "Return references only from this BHL item." example(32506)
+// @Param kingdom query string false "Return references from items where this kingdom is the most prevalent." example("Animalia")
+// @Param annot query string false "Return references with this nomenclatural annotation, ANY for any annotation." example("SP_NOV")
+// @Param min_quality query integer false "Return references with RefMatchQuality of at least this value. Needs a reference or nomen_event, otherwise the request is rejected." example(3)
 // @Accept plain
 // @Produce json
 // @Success 200 {object} bhl.RefsByName "Matched references for the provided name"
+// @Failure 400 {object} rest.ErrorResponse "Invalid query parameters"
+// @Failure 503 {object} rest.ErrorResponse "Database is unavailable"
 // @Router /name_refs/{name} [get]
 func nameRefsGet(bn bhlnames.BHLnames) func(echo.Context) error {
 	return func(c echo.Context) error {
@@ -184,9 +285,14 @@ func nameRefsGet(bn bhlnames.BHLnames) func(echo.Context) error {
 		if ref != "" {
 			opts = append(opts, input.OptRefString(ref))
 		}
+		pageOpts, err := pageFilterOpts(c)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, pageOpts...)
 		inp := input.New(bn.ParserPool(), opts...)
 
-		res, err := bn.NameRefs(inp)
+		res, err = bn.NameRefs(c.Request().Context(), inp)
 		if err != nil {
 			return err
 		}
@@ -195,6 +301,60 @@ func nameRefsGet(bn bhlnames.BHLnames) func(echo.Context) error {
 	}
 }
 
+// pageFilterOpts creates input options for pagination, sorting and
+// filtering of references from query parameters.
+func pageFilterOpts(c echo.Context) ([]input.Option, error) {
+	var res []input.Option
+	for _, v := range []struct {
+		param string
+		opt   func(int) input.Option
+	}{
+		{"refs_limit", input.OptRefsLimit},
+		{"offset", input.OptOffset},
+		{"year_from", input.OptYearFrom},
+		{"year_to", input.OptYearTo},
+		{"title_id", input.OptTitleID},
+		{"item_id", input.OptItemID},
+		{"min_quality", input.OptMinRefMatchQuality},
+		{"max_edit_distance", input.OptMaxEditDistance},
+	} {
+		s := c.QueryParam(v.param)
+		if s == "" {
+			continue
+		}
+		i, err := strconv.Atoi(s)
+		if err != nil || i < 0 {
+			msg := fmt.Sprintf("%s '%s' is not a positive integer", v.param, s)
+			return nil, echo.NewHTTPError(http.StatusBadRequest, msg)
+		}
+		res = append(res, v.opt(i))
+	}
+
+	if s := c.QueryParam("sort"); s != "" {
+		so := input.SortOrder(s)
+		if !so.IsValid() {
+			msg := fmt.Sprintf("sort '%s' is not a known sort order", s)
+			return nil, echo.NewHTTPError(http.StatusBadRequest, msg)
+		}
+		res = append(res, input.OptSortBy(so))
+	}
+	if s := c.QueryParam("name_match"); s != "" {
+		nm := input.NameMatch(s)
+		if !nm.IsValid() {
+			msg := fmt.Sprintf("name_match '%s' is not a known name match", s)
+			return nil, echo.NewHTTPError(http.StatusBadRequest, msg)
+		}
+		res = append(res, input.OptNameMatch(nm))
+	}
+	if s := c.QueryParam("kingdom"); s != "" {
+		res = append(res, input.OptMainKingdom(s))
+	}
+	if s := c.QueryParam("annot"); s != "" {
+		res = append(res, input.OptAnnotNomen(s))
+	}
+	return res, nil
+}
+
 // nameRefsPost takes an input.Input with a name, optionally reference and returns
 // best matched reference to provided data.
 // @Summary Finds BHL references for a name, taxon, or nomenclatural event
@@ -204,6 +364,8 @@ func nameRefsGet(bn bhlnames.BHLnames) func(echo.Context) error {
 // @Accept json
 // @Produce json
 // @Success 200 {object} bhl.RefsByName  "Matched references for the provided name"
+// @Failure 400 {object} rest.ErrorResponse "Malformed input"
+// @Failure 503 {object} rest.ErrorResponse "Database is unavailable"
 // @Router /name_refs [post]
 func nameRefsPost(bn bhlnames.BHLnames) func(echo.Context) error {
 	var err error
@@ -215,7 +377,7 @@ func nameRefsPost(bn bhlnames.BHLnames) func(echo.Context) error {
 			return err
 		}
 
-		res, err = bn.NameRefs(inp)
+		res, err = bn.NameRefs(c.Request().Context(), inp)
 		if err != nil {
 			return err
 		}
@@ -224,21 +386,91 @@ func nameRefsPost(bn bhlnames.BHLnames) func(echo.Context) error {
 	}
 }
 
+// parseRefPost shows how a reference string is parsed and matched to BHL
+// titles.
+// @Summary Parses a reference string and finds matching BHL titles.
+// @Description Extracts years, volume and pages from a reference string, creates its abbreviation and finds BHL titles with matching abbreviations. Only the refString field of the input is used. It helps to find out why a reference was not resolved.
+// @ID post-parse-ref
+// @Param input body input.Reference true "Reference with refString"
+// @Accept json
+// @Produce json
+// @Success 200 {object} bhl.ParsedRef  "Parsed reference and matched titles"
+// @Failure 400 {object} rest.ErrorResponse "Malformed input or empty reference string"
+// @Failure 503 {object} rest.ErrorResponse "Database is unavailable"
+// @Router /parse_ref [post]
+func parseRefPost(bn bhlnames.BHLnames) func(echo.Context) error {
+	return func(c echo.Context) error {
+		var ref input.Reference
+		err := c.Bind(&ref)
+		if err != nil {
+			return err
+		}
+
+		res, err := bn.ParseRef(c.Request().Context(), ref.RefString)
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
+// resolveRefPost finds BHL pages for a citation without a name.
+// @Summary Finds BHL pages for a bibliographic citation.
+// @Description Finds candidate BHL titles by abbreviations of the citation, selects their items by volume and year, and pages by page numbers. Candidates are ranked by year, volume, page and title scores, the best match goes first. Years, volume and pages are parsed from refString if they are not given.
+// @ID post-resolve-ref
+// @Param input body input.Reference true "Reference with refString"
+// @Accept json
+// @Produce json
+// @Success 200 {object} bhl.RefsByName  "Ranked references for the citation"
+// @Failure 400 {object} rest.ErrorResponse "Malformed input or empty reference string"
+// @Failure 503 {object} rest.ErrorResponse "Database is unavailable"
+// @Router /resolve_ref [post]
+func resolveRefPost(bn bhlnames.BHLnames) func(echo.Context) error {
+	return func(c echo.Context) error {
+		var ref input.Reference
+		err := c.Bind(&ref)
+		if err != nil {
+			return err
+		}
+
+		res, err := bn.ResolveReference(c.Request().Context(), ref)
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
 // externalIDGet provides nomenclatural event data for a given external ID.
 // @Summary Get nomenclatural event data by external ID from a data source.
 // @ID get-cached-refs
 // @Param external_id path string true "External ID" example("3W7R6")
 // @Param all_refs query string true "All Cached References" example("false")
+// @Param data_source_id query integer false "ID of the data source of the external ID, 1 (Catalogue of Life) by default." example(1)
 // @Accept plain
 // @Produce json
 // @Success 200 {object} bhl.RefsByName  "Matched references for the provided external ID"
+// @Failure 400 {object} rest.ErrorResponse "Invalid data source ID"
+// @Failure 404 {object} rest.ErrorResponse "External ID not found"
+// @Failure 503 {object} rest.ErrorResponse "Database is unavailable"
 // @Router /cached_refs/{external_id} [get]
 func externalIDGet(bn bhlnames.BHLnames) func(echo.Context) error {
 	return func(c echo.Context) error {
 		externalID := c.Param("external_id")
 		allRefs := c.QueryParam("all_refs") == "true"
+		dataSourceID := 1
+		if s := c.QueryParam("data_source_id"); s != "" {
+			id, err := strconv.Atoi(s)
+			if err != nil {
+				msg := fmt.Sprintf("data_source_id '%s' is not an integer", s)
+				return echo.NewHTTPError(http.StatusBadRequest, msg)
+			}
+			dataSourceID = id
+		}
 
-		res, err := bn.RefsByExtID(externalID, 1, allRefs)
+		res, err := bn.RefsByExtID(
+			c.Request().Context(), externalID, dataSourceID, allRefs,
+		)
 		if err != nil {
 			return err
 		}
@@ -250,18 +482,28 @@ func externalIDGet(bn bhlnames.BHLnames) func(echo.Context) error {
 // @Summary Get metadata and taxonomic statistics of a BHL item.
 // @ID get-item
 // @Param item_id path integer true "Item ID" example(73397)
+// @Param taxa_limit query integer false "Maximum number of taxa for each rank in the taxonomic distribution, all taxa are returned by default." example(10)
 // @Accept plain
 // @Produce json
 // @Success 200 {object} bhl.Item  "BHL item metadata and statistics"
+// @Failure 400 {object} rest.ErrorResponse "Invalid item ID or taxa limit"
+// @Failure 404 {object} rest.ErrorResponse "Item not found"
+// @Failure 503 {object} rest.ErrorResponse "Database is unavailable"
 // @Router /items/{item_id} [get]
 func itemStatsGet(bn bhlnames.BHLnames) func(echo.Context) error {
 	return func(c echo.Context) error {
 		itemIDStr := c.Param("item_id")
 		itemID, err := strconv.Atoi(itemIDStr)
+		if err != nil {
+			msg := fmt.Sprintf("item_id '%s' is not an integer", itemIDStr)
+			return echo.NewHTTPError(http.StatusBadRequest, msg)
+		}
+		var taxaLimit int
+		err = intParams(c, []intParam{{"taxa_limit", &taxaLimit}})
 		if err != nil {
 			return err
 		}
-		res, err := bn.ItemStats(itemID)
+		res, err := bn.ItemStats(c.Request().Context(), itemID, taxaLimit)
 		if err != nil {
 			return err
 		}
@@ -269,21 +511,197 @@ func itemStatsGet(bn bhlnames.BHLnames) func(echo.Context) error {
 	}
 }
 
-// itemxByTaxonGet provides items where a given higher taxon is prevalent.
+// itemNamesGet provides unique names detected in a BHL item.
+// @Summary Get unique scientific names detected in a BHL item.
+// @Description Lists unique names of an item with the number of their occurrences, first and last pages, classification and nomenclatural annotations. Names are sorted by their first appearance.
+// @ID get-item-names
+// @Param item_id path integer true "Item ID" example(73397)
+// @Accept plain
+// @Produce json
+// @Success 200 {object} bhl.NameList  "Unique names of the item"
+// @Failure 400 {object} rest.ErrorResponse "Invalid item ID"
+// @Failure 404 {object} rest.ErrorResponse "Item not found"
+// @Failure 503 {object} rest.ErrorResponse "Database is unavailable"
+// @Router /items/{item_id}/names [get]
+func itemNamesGet(bn bhlnames.BHLnames) func(echo.Context) error {
+	return func(c echo.Context) error {
+		itemIDStr := c.Param("item_id")
+		itemID, err := strconv.Atoi(itemIDStr)
+		if err != nil {
+			msg := fmt.Sprintf("item_id '%s' is not an integer", itemIDStr)
+			return echo.NewHTTPError(http.StatusBadRequest, msg)
+		}
+		res, err := bn.ItemNames(c.Request().Context(), itemID)
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
+// partNamesGet provides unique names detected in a BHL part.
+// @Summary Get unique scientific names detected in a BHL part.
+// @Description Lists unique names of a part (usually a scientific paper) with the number of their occurrences, first and last pages, classification and nomenclatural annotations. Names are sorted by their first appearance.
+// @ID get-part-names
+// @Param part_id path integer true "Part ID" example(39371)
+// @Accept plain
+// @Produce json
+// @Success 200 {object} bhl.NameList  "Unique names of the part"
+// @Failure 400 {object} rest.ErrorResponse "Invalid part ID"
+// @Failure 404 {object} rest.ErrorResponse "Part not found"
+// @Failure 503 {object} rest.ErrorResponse "Database is unavailable"
+// @Router /parts/{part_id}/names [get]
+func partNamesGet(bn bhlnames.BHLnames) func(echo.Context) error {
+	return func(c echo.Context) error {
+		partIDStr := c.Param("part_id")
+		partID, err := strconv.Atoi(partIDStr)
+		if err != nil {
+			msg := fmt.Sprintf("part_id '%s' is not an integer", partIDStr)
+			return echo.NewHTTPError(http.StatusBadRequest, msg)
+		}
+		res, err := bn.PartNames(c.Request().Context(), partID)
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
+// titleGet provides metadata and taxonomic profile of a BHL title.
+// @Summary Get metadata and taxonomic profile of a BHL title.
+// @Description Returns metadata of a title (a journal or a book) and its taxonomic profile aggregated from statistics of the title's items: numbers of names per kingdom and the most frequent main taxa of items from phylum to genus.
+// @ID get-title
+// @Param title_id path integer true "Title ID" example(7928)
+// @Accept plain
+// @Produce json
+// @Success 200 {object} bhl.Title  "BHL title metadata and taxonomic profile"
+// @Failure 400 {object} rest.ErrorResponse "Invalid title ID"
+// @Failure 404 {object} rest.ErrorResponse "Title not found"
+// @Failure 503 {object} rest.ErrorResponse "Database is unavailable"
+// @Router /titles/{title_id} [get]
+func titleGet(bn bhlnames.BHLnames) func(echo.Context) error {
+	return func(c echo.Context) error {
+		titleIDStr := c.Param("title_id")
+		titleID, err := strconv.Atoi(titleIDStr)
+		if err != nil {
+			msg := fmt.Sprintf("title_id '%s' is not an integer", titleIDStr)
+			return echo.NewHTTPError(http.StatusBadRequest, msg)
+		}
+		res, err := bn.Title(c.Request().Context(), titleID)
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
+// titleItemsGet provides items of a BHL title.
+// @Summary Get items (volumes) of a BHL title.
+// @Description Returns items of a title with their metadata and taxonomic statistics, sorted by year.
+// @ID get-title-items
+// @Param title_id path integer true "Title ID" example(7928)
+// @Param year_from query integer false "Return items published in this year or later." example(1890)
+// @Param year_to query integer false "Return items published in this year or earlier." example(1920)
+// @Param volume query string false "Return items which volume contains this string not surrounded by digits." example("v.12")
+// @Accept plain
+// @Produce json
+// @Success 200 {object} []bhl.Item  "BHL items of the title"
+// @Failure 400 {object} rest.ErrorResponse "Invalid title ID or query parameters"
+// @Failure 404 {object} rest.ErrorResponse "Title not found"
+// @Failure 503 {object} rest.ErrorResponse "Database is unavailable"
+// @Router /titles/{title_id}/items [get]
+func titleItemsGet(bn bhlnames.BHLnames) func(echo.Context) error {
+	return func(c echo.Context) error {
+		titleIDStr := c.Param("title_id")
+		titleID, err := strconv.Atoi(titleIDStr)
+		if err != nil {
+			msg := fmt.Sprintf("title_id '%s' is not an integer", titleIDStr)
+			return echo.NewHTTPError(http.StatusBadRequest, msg)
+		}
+
+		f := input.ItemsFilter{Volume: c.QueryParam("volume")}
+		err = intParams(c, []intParam{
+			{"year_from", &f.YearFrom},
+			{"year_to", &f.YearTo},
+		})
+		if err != nil {
+			return err
+		}
+
+		res, err := bn.TitleItems(c.Request().Context(), titleID, f)
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
+// itemsByTaxonGet provides items where a given higher taxon is prevalent.
 // @Summary Get BHL items where a given higher taxon is prevalent.
+// @Description Without a rank returns items where the taxon contains more than 50% of names. With a rank returns items where the taxon is the most prevalent one at this rank.
 // @ID get-items-by-taxon
 // @Param taxon_name path string true "Taxon Name" example("Lepidoptera")
+// @Param rank query string false "Rank of the taxon." Enums(kingdom, phylum, class, order, family, genus)
+// @Param min_percent query integer false "Return items where the taxon has at least this percentage of names." example(70)
+// @Param min_names query integer false "Return items with at least this number of unique names." example(100)
+// @Param year_from query integer false "Return items published in this year or later." example(1890)
+// @Param year_to query integer false "Return items published in this year or earlier." example(1920)
+// @Param volume query string false "Return items which volume contains this string not surrounded by digits." example("v.12")
+// @Param sort query string false "Order of items, default is percent." Enums(percent, names, year)
+// @Param limit query integer false "Maximum number of items." example(20)
+// @Param offset query integer false "Number of items to skip." example(40)
 // @Accept plain
 // @Produce json
 // @Success 200 {object} []bhl.Item  "BHL items with metadata and statistics"
+// @Failure 400 {object} rest.ErrorResponse "Empty taxon name or invalid query parameters"
+// @Failure 503 {object} rest.ErrorResponse "Database is unavailable"
 // @Router /taxon_items/{taxon_name} [get]
 func itemsByTaxonGet(bn bhlnames.BHLnames) func(echo.Context) error {
 	return func(c echo.Context) error {
-		taxon := c.Param("taxon_name")
-		res, err := bn.ItemsByTaxon(taxon)
+		iq := input.NewItemsQuery(c.Param("taxon_name"))
+		iq.Rank = input.TaxonRank(c.QueryParam("rank"))
+		iq.SortBy = input.ItemsSort(c.QueryParam("sort"))
+		iq.Volume = c.QueryParam("volume")
+		err := intParams(c, []intParam{
+			{"min_percent", &iq.MinPercent},
+			{"min_names", &iq.MinNamesNum},
+			{"year_from", &iq.YearFrom},
+			{"year_to", &iq.YearTo},
+			{"limit", &iq.Limit},
+			{"offset", &iq.Offset},
+		})
+		if err != nil {
+			return err
+		}
+
+		res, err := bn.ItemsByTaxon(c.Request().Context(), iq)
 		if err != nil {
 			return err
 		}
 		return c.JSON(http.StatusOK, res)
 	}
 }
+
+// intParam links a query parameter to the field that receives its value.
+type intParam struct {
+	name  string
+	value *int
+}
+
+// intParams converts non-empty query parameters to integers. It returns
+// Bad Request error if a parameter is not an integer.
+func intParams(c echo.Context, params []intParam) error {
+	for _, v := range params {
+		s := c.QueryParam(v.name)
+		if s == "" {
+			continue
+		}
+		i, err := strconv.Atoi(s)
+		if err != nil {
+			msg := fmt.Sprintf("%s '%s' is not an integer", v.name, s)
+			return echo.NewHTTPError(http.StatusBadRequest, msg)
+		}
+		*v.value = i
+	}
+	return nil
+}
@@ -7,8 +7,8 @@ import (
 	"strconv"
 	"testing"
 
-	"github.com/gnames/bhlnames/internal/ent/bhl"
-	"github.com/gnames/bhlnames/internal/ent/input"
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/input"
 	"github.com/gnames/gnfmt"
 	"github.com/gnames/gnparser"
 	"github.com/stretchr/testify/assert"
@@ -160,6 +160,35 @@ func TestRefsByPage(t *testing.T) {
 	}
 }
 
+func TestPageNames(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		msg    string
+		pageID int
+		itemID int
+	}{
+		{"6059125", 6059125, 29372},
+	}
+
+	for _, v := range tests {
+		pageID := strconv.Itoa(v.pageID)
+		resp, err := http.Get(testURL + "/pages/" + pageID + "/names")
+		assert.Nil(err)
+		assert.Equal(http.StatusOK, resp.StatusCode)
+
+		bs, err := io.ReadAll(resp.Body)
+		assert.Nil(err)
+		var res bhl.PageNames
+		err = enc.Decode(bs, &res)
+		assert.Nil(err)
+		assert.Equal(v.itemID, res.ItemID)
+		assert.Equal(len(res.Names), res.NamesNum)
+		for _, n := range res.Names {
+			assert.Less(n.OffsetStart, n.OffsetEnd)
+		}
+	}
+}
+
 func TestCachedRefs(t *testing.T) {
 	assert := assert.New(t)
 	tests := []struct {
@@ -208,7 +237,117 @@ func TestItemStats(t *testing.T) {
 		err = enc.Decode(bs, &res)
 		assert.Nil(err)
 		assert.Equal(v.titleID, res.TitleID)
+		assert.Greater(len(res.Taxa), 0)
+
+		resp, err = http.Get(testURL + "/items/" + id + "?taxa_limit=1")
+		assert.Nil(err)
+		bs, err = io.ReadAll(resp.Body)
+		assert.Nil(err)
+		res = bhl.Item{}
+		err = enc.Decode(bs, &res)
+		assert.Nil(err)
+		assert.LessOrEqual(len(res.Taxa), 6)
+	}
+}
+
+func TestItemNames(t *testing.T) {
+	assert := assert.New(t)
+	resp, err := http.Get(testURL + "/items/73397/names")
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	bs, err := io.ReadAll(resp.Body)
+	assert.Nil(err)
+	var res bhl.NameList
+	err = enc.Decode(bs, &res)
+	assert.Nil(err)
+	assert.Equal(73397, res.ItemID)
+	assert.Greater(res.NamesNum, 0)
+	assert.GreaterOrEqual(res.OccurrencesNum, res.NamesNum)
+}
+
+func TestTitle(t *testing.T) {
+	assert := assert.New(t)
+	resp, err := http.Get(testURL + "/titles/29889")
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	bs, err := io.ReadAll(resp.Body)
+	assert.Nil(err)
+	var res bhl.Title
+	err = enc.Decode(bs, &res)
+	assert.Nil(err)
+	assert.Equal(29889, res.TitleID)
+	assert.Greater(res.ItemsNum, 0)
+
+	resp, err = http.Get(testURL + "/titles/29889/items")
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	bs, err = io.ReadAll(resp.Body)
+	assert.Nil(err)
+	var items []*bhl.Item
+	err = enc.Decode(bs, &items)
+	assert.Nil(err)
+	assert.Equal(res.ItemsNum, len(items))
+}
+
+func TestDataInfo(t *testing.T) {
+	assert := assert.New(t)
+	resp, err := http.Get(testURL + "/data_info")
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	bs, err := io.ReadAll(resp.Body)
+	assert.Nil(err)
+	var res bhl.DataInfo
+	err = enc.Decode(bs, &res)
+	assert.Nil(err)
+	assert.NotEmpty(res.Version)
+	assert.Greater(len(res.Sources), 0)
+	assert.Greater(len(res.Builds), 0)
+	assert.Greater(res.Builds[0].TableRows["items"], int64(0))
+}
+
+func TestParseRef(t *testing.T) {
+	assert := assert.New(t)
+	ref := input.Reference{
+		RefString: "Bull. Amer. Mus. Nat. Hist. 12: 188-189 (1899)",
 	}
+	reqBody, err := gnfmt.GNjson{}.Encode(ref)
+	assert.Nil(err)
+
+	r := bytes.NewReader(reqBody)
+	resp, err := http.Post(testURL+"/parse_ref", "application/json", r)
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	bs, err := io.ReadAll(resp.Body)
+	assert.Nil(err)
+	var res bhl.ParsedRef
+	err = enc.Decode(bs, &res)
+	assert.Nil(err)
+	assert.Equal(1899, res.YearStart)
+	assert.Equal(12, res.Volume)
+	assert.Equal(188, res.PageStart)
+	assert.NotEmpty(res.Abbr)
+	assert.Greater(len(res.Titles), 0)
+}
+
+func TestResolveRef(t *testing.T) {
+	assert := assert.New(t)
+	ref := input.Reference{
+		RefString: "Bull. Amer. Mus. Nat. Hist. 12: 188-189 (1899)",
+	}
+	reqBody, err := gnfmt.GNjson{}.Encode(ref)
+	assert.Nil(err)
+
+	r := bytes.NewReader(reqBody)
+	resp, err := http.Post(testURL+"/resolve_ref", "application/json", r)
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	bs, err := io.ReadAll(resp.Body)
+	assert.Nil(err)
+	var res bhl.RefsByName
+	err = enc.Decode(bs, &res)
+	assert.Nil(err)
+	assert.Greater(res.ReferenceNumber, 0)
+	assert.Equal("match", res.References[0].Score.Labels["vol"])
 }
 
 func TestTaxonItems(t *testing.T) {
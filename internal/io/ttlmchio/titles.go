@@ -6,40 +6,80 @@ import (
 	"log/slog"
 
 	"github.com/gnames/bhlnames/pkg/ent/abbr"
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
 )
 
-func (tm *ttlmchio) TitlesBHL(refString string) (map[int][]string, error) {
-	refAbbr := abbr.Abbr(refString)
-	matches := tm.Search(refAbbr)
+func (tm *ttlmchio) TitlesBHL(
+	ctx context.Context,
+	refString string,
+) (map[int][]string, error) {
+	rt, err := tm.RefTitles(ctx, refString)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[int][]string)
+	for _, v := range rt.Titles {
+		res[v.TitleID] = v.Abbrs
+	}
+	return res, nil
+}
+
+func (tm *ttlmchio) RefTitles(
+	ctx context.Context,
+	refString string,
+) (*bhl.RefTitles, error) {
+	res := bhl.RefTitles{Abbr: abbr.Abbr(refString)}
+	matches := tm.SearchUniq(res.Abbr)
+	if len(matches) == 0 {
+		return &res, nil
+	}
 
-	abbrs := make([]string, len(matches))
+	res.AbbrMatches = make([]string, len(matches))
 	for i := range matches {
-		abbrs[i] = matches[i].Pattern
+		res.AbbrMatches[i] = matches[i].Pattern
 	}
-	return tm.abbrsToTitleIDs(abbrs)
+
+	var err error
+	res.Titles, err = tm.abbrsToTitles(ctx, res.AbbrMatches)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
 }
 
-func (tm *ttlmchio) abbrsToTitleIDs(abbrs []string) (map[int][]string, error) {
-	res := make(map[int][]string)
+func (tm *ttlmchio) abbrsToTitles(
+	ctx context.Context,
+	abbrs []string,
+) ([]*bhl.TitleMatch, error) {
+	var res []*bhl.TitleMatch
 	q := `
 SELECT  DISTINCT i.title_id, title_name
   FROM abbr_titles attl
     JOIN items i
       ON i.title_id = attl.title_id
 	WHERE attl.abbr = ANY($1)
+	ORDER BY i.title_id, title_name
 `
 	abbrMap := make(map[string]struct{})
 	for _, abbr := range abbrs {
 		abbrMap[abbr] = struct{}{}
 	}
 
-	rows, err := tm.db.Query(context.Background(), q, abbrs)
+	if tm.queryTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, tm.queryTimeout)
+		defer cancel()
+	}
+
+	rows, err := tm.db.Query(ctx, q, abbrs)
 	if err != nil {
 		slog.Error("Cannot get titles from abbreviations", "error", err)
 		return nil, err
 	}
 	defer rows.Close()
 
+	var lastID int
 	for rows.Next() {
 		var id int
 		var name string
@@ -48,9 +88,18 @@ SELECT  DISTINCT i.title_id, title_name
 			slog.Error("Cannot scan title from abbreviation", "error", err)
 			return nil, err
 		}
+		// items of a title might have different spelling of its name,
+		// the first one is used.
+		if id == lastID {
+			continue
+		}
+		lastID = id
+
 		abbrStr := nameToAbbr(name, abbrMap, tm.shortWords)
 		if len(abbrStr) > 0 {
-			res[id] = abbrStr
+			res = append(res,
+				&bhl.TitleMatch{TitleID: id, TitleName: name, Abbrs: abbrStr},
+			)
 		} else {
 			err := errors.New("title not found")
 			slog.Error("Title not found for abbreviation", "abbr", name, "err", err)
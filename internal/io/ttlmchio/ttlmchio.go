@@ -4,12 +4,13 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/gnames/aho_corasick"
-	"github.com/gnames/bhlnames/internal/ent/ttlmch"
 	"github.com/gnames/bhlnames/internal/io/dbio"
 	"github.com/gnames/bhlnames/internal/io/dictio"
 	"github.com/gnames/bhlnames/pkg/config"
+	"github.com/gnames/bhlnames/pkg/ent/ttlmch"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -21,6 +22,9 @@ type ttlmchio struct {
 
 	// db is a connection to the database.
 	db *pgxpool.Pool
+
+	// queryTimeout limits the duration of database queries.
+	queryTimeout time.Duration
 }
 
 func New(cfg config.Config) (ttlmch.TitleMatcher, error) {
@@ -41,8 +45,9 @@ func New(cfg config.Config) (ttlmch.TitleMatcher, error) {
 	}
 
 	res := ttlmchio{
-		db:         db,
-		shortWords: shortWords,
+		db:           db,
+		shortWords:   shortWords,
+		queryTimeout: time.Duration(cfg.DbQueryTimeout) * time.Second,
 	}
 
 	ac, err := res.getAhoCorasick()
@@ -2,20 +2,24 @@ package bhlnames
 
 import (
 	"cmp"
+	"context"
 	"fmt"
 	"log/slog"
 	"slices"
+	"strings"
 
 	"github.com/gnames/bayes"
-	"github.com/gnames/bhlnames/internal/ent/bhl"
-	"github.com/gnames/bhlnames/internal/ent/builder"
-	"github.com/gnames/bhlnames/internal/ent/col"
-	"github.com/gnames/bhlnames/internal/ent/input"
-	"github.com/gnames/bhlnames/internal/ent/nlp"
-	"github.com/gnames/bhlnames/internal/ent/reffnd"
 	"github.com/gnames/bhlnames/internal/ent/score"
-	"github.com/gnames/bhlnames/internal/ent/ttlmch"
+	"github.com/gnames/bhlnames/internal/io/bayesio"
 	"github.com/gnames/bhlnames/pkg/config"
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/builder"
+	"github.com/gnames/bhlnames/pkg/ent/cache"
+	"github.com/gnames/bhlnames/pkg/ent/col"
+	"github.com/gnames/bhlnames/pkg/ent/input"
+	"github.com/gnames/bhlnames/pkg/ent/nlp"
+	"github.com/gnames/bhlnames/pkg/ent/reffnd"
+	"github.com/gnames/bhlnames/pkg/ent/ttlmch"
 	"github.com/gnames/gnparser"
 	"github.com/gnames/gnsys"
 )
@@ -38,6 +42,13 @@ func OptTitleMatcher(tm ttlmch.TitleMatcher) Option {
 	}
 }
 
+// OptCache sets a cache for results of NameRefs.
+func OptCache(c cache.Cache) Option {
+	return func(bn *bhlnames) {
+		bn.cache = c
+	}
+}
+
 // OptNLP creates NLP instance to generate Odds for references.
 func OptNLP(n nlp.NLP) Option {
 	return func(bn *bhlnames) {
@@ -66,15 +77,25 @@ type bhlnames struct {
 	// gnPool is a pool of gnparser instances. Thy are used for the scientific
 	// name parsing.
 	gnpPool chan gnparser.GNparser
+
+	// cache keeps results of NameRefs, it is nil if caching is disabled.
+	cache cache.Cache
 }
 
-// New creates a new BHLnames instance.
+// New creates a new BHLnames instance. RefFinder is required for all
+// searches, it is set by OptRefFinder. If OptNLP is not given, the
+// pretrained model of BHLnames is used. Without a TitleMatcher titles of
+// references do not affect scores.
 func New(cfg config.Config, opts ...Option) BHLnames {
 	res := bhlnames{cfg: cfg}
 	for _, opt := range opts {
 		opt(&res)
 	}
 
+	if res.bs == nil {
+		res.bs = bayesio.New().LoadPretrainedWeights()
+	}
+
 	res.gnpPool = gnparser.NewPool(gnparser.NewConfig(), cfg.JobsNum)
 	return &res
 }
@@ -110,37 +131,105 @@ func (bn bhlnames) Initialize(bld builder.Builder) error {
 		return err
 	}
 
-	return nil
-}
+	_, err = bld.CheckData()
+	if err != nil {
+		err = fmt.Errorf("CheckData: %w", err)
+		return err
+	}
 
-func (bn bhlnames) NameRefs(inp input.Input) (*bhl.RefsByName, error) {
-	res, err := bn.rf.ReferencesByName(inp, bn.cfg)
-	if res == nil {
-		res = bn.rf.EmptyNameRefs(inp)
+	err = bld.PublishData()
+	if err != nil {
+		err = fmt.Errorf("PublishData: %w", err)
+		return err
 	}
+
+	return bn.clearCache()
+}
+
+// Update applies changes of the newest dumps to the current data.
+func (bn bhlnames) Update(bld builder.Builder) (builder.Report, error) {
+	defer bn.Close()
+
+	res, err := bld.UpdateData()
 	if err != nil {
+		err = fmt.Errorf("UpdateData: %w", err)
 		return res, err
 	}
-	// do not show ReferenceNumber for nomenclatural events, because we
-	// try to find only one reference.
-	if inp.WithNomenEvent {
-		res.ReferenceNumber = 0
-	} else {
-		res.ReferenceNumber = len(res.References)
+
+	return res, bn.clearCache()
+}
+
+// Rollback returns readers to the previous build of the database.
+func (bn bhlnames) Rollback(bld builder.Builder) error {
+	defer bn.Close()
+
+	err := bld.Rollback()
+	if err != nil {
+		err = fmt.Errorf("Rollback: %w", err)
+		return err
 	}
 
-	// limit the number of references if needed
-	if inp.RefsLimit > 0 && len(res.References) > inp.RefsLimit {
-		res.References = res.References[:inp.RefsLimit]
+	return bn.clearCache()
+}
+
+func (bn bhlnames) NameRefs(
+	ctx context.Context,
+	inp input.Input,
+) (*bhl.RefsByName, error) {
+	if bn.cache == nil {
+		return bn.nameRefs(ctx, inp)
 	}
 
+	key := cache.Key(inp)
+	if res, ok := bn.cachedNameRefs(key, inp); ok {
+		return res, nil
+	}
+
+	res, err := bn.nameRefs(ctx, inp)
+	// results that hit an error are incomplete and must not be cached.
+	if err != nil || res.Error != "" {
+		return res, err
+	}
+	bn.cacheNameRefs(key, res)
+	return res, nil
+}
+
+func (bn bhlnames) nameRefs(
+	ctx context.Context,
+	inp input.Input,
+) (*bhl.RefsByName, error) {
+	if err := validateInput(inp); err != nil {
+		res := bn.rf.EmptyNameRefs(inp)
+		res.Error = err.Error()
+		return res, err
+	}
+
+	res, err := bn.rf.ReferencesByName(ctx, inp, bn.cfg)
+	if res == nil {
+		res = bn.rf.EmptyNameRefs(inp)
+	}
+	if err != nil {
+		res.Error = err.Error()
+		return res, err
+	}
 	// if results are from CoL cache, return them here
 	if res.Meta.NomenEventFromCache {
+		// do not show ReferenceNumber for nomenclatural events, because we
+		// try to find only one reference.
+		res.ReferenceNumber = 0
+		res.SortOrder = inp.Order(true)
+		bhl.SortReferences(res.References, res.SortOrder)
+		paginate(res, inp)
 		return res, nil
 	}
 
-	if inp.WithNomenEvent || inp.Reference != nil {
-		bn.scoreCalcSort(res, inp.WithNomenEvent)
+	isScored := inp.IsScored()
+	if isScored {
+		err = bn.scoreCalcSort(ctx, res, inp.WithNomenEvent)
+		if err != nil {
+			res.Error = err.Error()
+			return res, err
+		}
 	}
 
 	if inp.Reference != nil {
@@ -149,20 +238,100 @@ func (bn bhlnames) NameRefs(inp input.Input) (*bhl.RefsByName, error) {
 		}
 	}
 
+	if inp.MinRefMatchQuality > 0 {
+		res.References = slices.DeleteFunc(res.References,
+			func(r *bhl.ReferenceName) bool {
+				return matchQuality(r.Odds) < inp.MinRefMatchQuality
+			})
+	}
+
+	if inp.WithNomenEvent {
+		res.ReferenceNumber = 0
+	} else {
+		res.ReferenceNumber = len(res.References)
+	}
+
+	// the order is applied after scoring, so limits cut the list in the
+	// order the caller asked for.
+	res.SortOrder = inp.Order(isScored)
+	bhl.SortReferences(res.References, res.SortOrder)
+
+	paginate(res, inp)
 	return res, nil
 }
 
+// validateInput checks parameters that come from users as strings, and
+// filters that cannot apply to the input.
+func validateInput(inp input.Input) error {
+	if !inp.SortBy.IsValid() {
+		msg := fmt.Sprintf("unknown sort order '%s'", inp.SortBy)
+		return reffnd.NewError(reffnd.ErrInvalidInput, msg, nil)
+	}
+	if !inp.NameMatch.IsValid() {
+		msg := fmt.Sprintf("unknown name match '%s'", inp.NameMatch)
+		return reffnd.NewError(reffnd.ErrInvalidInput, msg, nil)
+	}
+	if inp.MinRefMatchQuality > 0 && !inp.IsScored() {
+		msg := "minimal match quality needs a reference or a nomenclatural event search"
+		return reffnd.NewError(reffnd.ErrInvalidInput, msg, nil)
+	}
+	return nil
+}
+
+// notSupported returns an error for lookups that are not implemented by
+// the RefFinder.
+func notSupported(what string) error {
+	msg := fmt.Sprintf("%s are not supported by the reference finder", what)
+	return reffnd.NewError(reffnd.ErrUnavailable, msg, nil)
+}
+
+// paginate keeps only references from the requested page and sets the
+// offset of the next page if there are more references.
+func paginate(res *bhl.RefsByName, inp input.Input) {
+	offset := max(inp.Offset, 0)
+	if offset >= len(res.References) {
+		res.References = res.References[:0]
+		return
+	}
+
+	end := len(res.References)
+	if inp.RefsLimit > 0 && offset+inp.RefsLimit < end {
+		end = offset + inp.RefsLimit
+		// nomenclatural events return only the best references
+		if !inp.WithNomenEvent {
+			res.NextOffset = end
+		}
+	}
+	res.References = res.References[offset:end]
+}
+
 // RefByPageID returns a reference metadata for a given pageID.
-func (bn bhlnames) RefByPageID(pageID int) (*bhl.Reference, error) {
-	return bn.rf.RefByPageID(pageID)
+func (bn bhlnames) RefByPageID(
+	ctx context.Context,
+	pageID int,
+) (*bhl.Reference, error) {
+	return bn.rf.RefByPageID(ctx, pageID)
+}
+
+// PageNames returns names detected on a page.
+func (bn bhlnames) PageNames(
+	ctx context.Context,
+	pageID int,
+) (*bhl.PageNames, error) {
+	nl, ok := bn.rf.(reffnd.NameLister)
+	if !ok {
+		return nil, notSupported("page names")
+	}
+	return nl.PageNames(ctx, pageID)
 }
 
 func (bn bhlnames) RefsByExtID(
+	ctx context.Context,
 	extID string,
 	dataSourceID int,
 	allRefs bool,
 ) (*bhl.RefsByName, error) {
-	res, err := bn.rf.RefsByExtID(extID, dataSourceID)
+	res, err := bn.rf.RefsByExtID(ctx, extID, dataSourceID)
 	if err != nil {
 		return nil, err
 	}
@@ -175,8 +344,11 @@ func (bn bhlnames) RefsByExtID(
 	return res, nil
 }
 
-func (bn bhlnames) ItemStats(itemID int) (*bhl.Item, error) {
-	res, err := bn.rf.ItemStats(itemID)
+func (bn bhlnames) ItemStats(
+	ctx context.Context,
+	itemID, taxaLimit int,
+) (*bhl.Item, error) {
+	res, err := bn.rf.ItemStats(ctx, itemID, taxaLimit)
 	if err != nil {
 		return nil, err
 	}
@@ -184,8 +356,75 @@ func (bn bhlnames) ItemStats(itemID int) (*bhl.Item, error) {
 	return res, nil
 }
 
-func (bn bhlnames) ItemsByTaxon(taxon string) ([]*bhl.Item, error) {
-	res, err := bn.rf.ItemsByTaxon(taxon)
+// ItemNames returns unique names detected in an item.
+func (bn bhlnames) ItemNames(
+	ctx context.Context,
+	itemID int,
+) (*bhl.NameList, error) {
+	nl, ok := bn.rf.(reffnd.NameLister)
+	if !ok {
+		return nil, notSupported("item names")
+	}
+	return nl.ItemNames(ctx, itemID)
+}
+
+// PartNames returns unique names detected in a part.
+func (bn bhlnames) PartNames(
+	ctx context.Context,
+	partID int,
+) (*bhl.NameList, error) {
+	nl, ok := bn.rf.(reffnd.NameLister)
+	if !ok {
+		return nil, notSupported("part names")
+	}
+	return nl.PartNames(ctx, partID)
+}
+
+// Title returns metadata and taxonomic profile of a title.
+func (bn bhlnames) Title(
+	ctx context.Context,
+	titleID int,
+) (*bhl.Title, error) {
+	tf, ok := bn.rf.(reffnd.TitleFinder)
+	if !ok {
+		return nil, notSupported("titles")
+	}
+	return tf.Title(ctx, titleID)
+}
+
+// TitleItems returns items of a title.
+func (bn bhlnames) TitleItems(
+	ctx context.Context,
+	titleID int,
+	f input.ItemsFilter,
+) ([]*bhl.Item, error) {
+	tf, ok := bn.rf.(reffnd.TitleFinder)
+	if !ok {
+		return nil, notSupported("items of titles")
+	}
+	return tf.TitleItems(ctx, titleID, f)
+}
+
+// DataInfo returns provenance of the data and the version of bhlnames
+// that serves them.
+func (bn bhlnames) DataInfo(ctx context.Context) (*bhl.DataInfo, error) {
+	di, ok := bn.rf.(reffnd.DataInformer)
+	if !ok {
+		return nil, notSupported("data provenance")
+	}
+	res, err := di.DataInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res.Version = Version
+	return res, nil
+}
+
+func (bn bhlnames) ItemsByTaxon(
+	ctx context.Context,
+	iq input.ItemsQuery,
+) ([]*bhl.Item, error) {
+	res, err := bn.rf.ItemsByTaxon(ctx, iq)
 	if err != nil {
 		return nil, err
 	}
@@ -193,6 +432,98 @@ func (bn bhlnames) ItemsByTaxon(taxon string) ([]*bhl.Item, error) {
 	return res, nil
 }
 
+// ResolveReference finds and ranks BHL pages for a citation.
+func (bn bhlnames) ResolveReference(
+	ctx context.Context,
+	ref input.Reference,
+) (*bhl.RefsByName, error) {
+	ref.RefString = strings.TrimSpace(ref.RefString)
+	if ref.RefString == "" {
+		msg := "reference string is empty"
+		return nil, reffnd.NewError(reffnd.ErrInvalidInput, msg, nil)
+	}
+	if bn.tm == nil {
+		msg := "title matcher is not available"
+		return nil, reffnd.NewError(reffnd.ErrUnavailable, msg, nil)
+	}
+	cf, ok := bn.rf.(reffnd.CitationFinder)
+	if !ok {
+		return nil, notSupported("citation searches")
+	}
+	ref.ParseMissing()
+
+	inp := input.Input{Reference: &ref}
+	res := bn.rf.EmptyNameRefs(inp)
+	res.SortOrder = inp.Order(true)
+
+	titles, err := bn.tm.TitlesBHL(ctx, ref.RefString)
+	if err != nil {
+		err = fmt.Errorf("ResolveReference: %w", err)
+		return nil, err
+	}
+	if len(titles) == 0 {
+		return res, nil
+	}
+	titleIDs := make([]int, 0, len(titles))
+	for k := range titles {
+		titleIDs = append(titleIDs, k)
+	}
+	slices.Sort(titleIDs)
+
+	refs, err := cf.ReferencesByCitation(ctx, ref, titleIDs)
+	if err != nil {
+		res.Error = err.Error()
+		return res, err
+	}
+	res.References = refs
+	res.ReferenceNumber = len(refs)
+
+	err = bn.scoreCalcSort(ctx, res, false)
+	if err != nil {
+		err = fmt.Errorf("ResolveReference: %w", err)
+		return nil, err
+	}
+	for i := range res.References {
+		res.References[i].RefMatchQuality = matchQuality(res.References[i].Odds)
+		// there is no name in a citation search
+		res.References[i].NameData = nil
+	}
+	return res, nil
+}
+
+// ParseRef returns parsed fields and matched titles of a reference string.
+func (bn bhlnames) ParseRef(
+	ctx context.Context,
+	refString string,
+) (*bhl.ParsedRef, error) {
+	refString = strings.TrimSpace(refString)
+	if refString == "" {
+		msg := "reference string is empty"
+		return nil, reffnd.NewError(reffnd.ErrInvalidInput, msg, nil)
+	}
+
+	ref := input.ParseRefString(refString)
+	res := bhl.ParsedRef{
+		RefString: ref.RefString,
+		YearStart: ref.RefYearStart,
+		YearEnd:   ref.RefYearEnd,
+		Volume:    ref.Volume,
+		PageStart: ref.PageStart,
+		PageEnd:   ref.PageEnd,
+	}
+	if bn.tm == nil {
+		return &res, nil
+	}
+
+	rt, err := bn.tm.RefTitles(ctx, refString)
+	if err != nil {
+		err = fmt.Errorf("ParseRef: %w", err)
+		return nil, err
+	}
+	res.RefTitles = *rt
+	return &res, nil
+}
+
 func matchQuality(odds float64) int {
 	if odds <= 0 {
 		return 0
@@ -232,12 +563,15 @@ func (bn bhlnames) InitCoLNomenEvents(cn col.Nomen) error {
 		}
 	}
 
-	err = cn.NomenEvents(bn.NameRefsStream)
+	// CoL names are searched only once, there is no need to cache them.
+	noCache := bn
+	noCache.cache = nil
+	err = cn.NomenEvents(noCache.NameRefsStream)
 	if err != nil {
 		slog.Error("Unable to get nomenclatural events for CoL", "error", err)
 		return err
 	}
-	return nil
+	return bn.clearCache()
 }
 
 func (bn bhlnames) Config() config.Config {
@@ -252,12 +586,19 @@ func (bn *bhlnames) Close() {
 	if bn.rf != nil {
 		bn.rf.Close()
 	}
+	if bn.cache != nil {
+		bn.cache.Close()
+	}
 	if bn.tm != nil {
 		bn.tm.Close()
 	}
 }
 
-func (bn bhlnames) scoreCalcSort(nr *bhl.RefsByName, isNomen bool) error {
+func (bn bhlnames) scoreCalcSort(
+	ctx context.Context,
+	nr *bhl.RefsByName,
+	isNomen bool,
+) error {
 	// Year has precedence over others
 	prec := map[score.ScoreType]int{
 		score.RefVolume: 0,
@@ -267,7 +608,7 @@ func (bn bhlnames) scoreCalcSort(nr *bhl.RefsByName, isNomen bool) error {
 		score.RefPages:  4,
 	}
 	s := score.New(prec)
-	err := s.Calculate(nr, bn.tm, bn.bs, isNomen)
+	err := s.Calculate(ctx, nr, bn.tm, bn.bs, isNomen)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,189 @@
+package bhlnames
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gnames/bhlnames/internal/io/cacheio"
+	"github.com/gnames/bhlnames/pkg/config"
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/cache"
+	"github.com/gnames/bhlnames/pkg/ent/input"
+	"github.com/gnames/bhlnames/pkg/ent/reffnd"
+	"github.com/gnames/bhlnames/pkg/ent/ttlmch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaginate(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		msg           string
+		limit, offset int
+		nomen         bool
+		pages         []int
+		next          int
+	}{
+		{"all", 0, 0, false, []int{1, 2, 3, 4, 5}, 0},
+		{"first page", 2, 0, false, []int{1, 2}, 2},
+		{"middle page", 2, 2, false, []int{3, 4}, 4},
+		{"last page", 2, 4, false, []int{5}, 0},
+		{"exact last page", 1, 4, false, []int{5}, 0},
+		{"offset only", 0, 3, false, []int{4, 5}, 0},
+		{"out of range", 2, 10, false, []int{}, 0},
+		{"nomen", 2, 0, true, []int{1, 2}, 0},
+	}
+
+	for _, v := range tests {
+		res := &bhl.RefsByName{}
+		for i := 1; i <= 5; i++ {
+			ref := &bhl.ReferenceName{Reference: bhl.Reference{PageID: i}}
+			res.References = append(res.References, ref)
+		}
+		inp := input.Input{Params: input.Params{
+			RefsLimit:      v.limit,
+			Offset:         v.offset,
+			WithNomenEvent: v.nomen,
+		}}
+		paginate(res, inp)
+
+		pages := make([]int, len(res.References))
+		for i := range res.References {
+			pages[i] = res.References[i].PageID
+		}
+		assert.Equal(v.pages, pages, v.msg)
+		assert.Equal(v.next, res.NextOffset, v.msg)
+	}
+}
+
+// stubFinder returns the same references for every name.
+type stubFinder struct {
+	reffnd.RefFinder
+	refs []*bhl.ReferenceName
+}
+
+func (s stubFinder) ReferencesByName(
+	_ context.Context,
+	inp input.Input,
+	_ config.Config,
+) (*bhl.RefsByName, error) {
+	res := s.EmptyNameRefs(inp)
+	res.References = append(res.References, s.refs...)
+	return res, nil
+}
+
+func (s stubFinder) EmptyNameRefs(inp input.Input) *bhl.RefsByName {
+	return &bhl.RefsByName{Meta: bhl.Meta{Input: inp}}
+}
+
+func (s stubFinder) Close() {}
+
+// failMatcher is a TitleMatcher that always fails.
+type failMatcher struct {
+	ttlmch.TitleMatcher
+}
+
+func (failMatcher) TitlesBHL(context.Context, string) (map[int][]string, error) {
+	return nil, context.DeadlineExceeded
+}
+
+func (failMatcher) Close() {}
+
+func TestNameRefsScoreError(t *testing.T) {
+	assert := assert.New(t)
+	rf := stubFinder{refs: []*bhl.ReferenceName{
+		{Reference: bhl.Reference{PageID: 1, YearAggr: 1892}},
+	}}
+	c := cacheio.NewLRU(10, time.Minute)
+	bn := New(config.New(),
+		OptRefFinder(rf),
+		OptTitleMatcher(failMatcher{}),
+		OptCache(c),
+	)
+	inp := input.Input{
+		Name:      input.Name{NameString: "Pardosa moesta"},
+		Reference: &input.Reference{RefString: "Banks, 1892"},
+	}
+
+	res, err := bn.NameRefs(context.Background(), inp)
+	assert.ErrorIs(err, context.DeadlineExceeded)
+	assert.NotEmpty(res.Error)
+
+	_, ok := c.Get(cache.Key(inp))
+	assert.False(ok, "failed result must not be cached")
+}
+
+func TestNameRefsMinQuality(t *testing.T) {
+	assert := assert.New(t)
+	rf := stubFinder{
+		refs: []*bhl.ReferenceName{{Reference: bhl.Reference{PageID: 1}}},
+	}
+	bn := New(config.New(), OptRefFinder(rf))
+	inp := input.Input{
+		Name: input.Name{NameString: "Pardosa moesta"},
+		Params: input.Params{
+			Filter: input.Filter{MinRefMatchQuality: 3},
+		},
+	}
+
+	res, err := bn.NameRefs(context.Background(), inp)
+	assert.ErrorIs(err, reffnd.ErrInvalidInput)
+	assert.NotEmpty(res.Error)
+}
+
+func TestNameRefsStreamError(t *testing.T) {
+	assert := assert.New(t)
+	rf := stubFinder{
+		refs: []*bhl.ReferenceName{{Reference: bhl.Reference{PageID: 1}}},
+	}
+	bn := New(config.New(config.OptJobsNum(2)), OptRefFinder(rf))
+	inps := []input.Input{
+		{ID: "1", Name: input.Name{NameString: "Bubo bubo"}},
+		{
+			ID:     "2",
+			Name:   input.Name{NameString: "Pardosa moesta"},
+			Params: input.Params{SortBy: "bad"},
+		},
+		{ID: "3", Name: input.Name{NameString: "Aus bus"}},
+	}
+
+	chIn := make(chan input.Input)
+	chOut := make(chan *bhl.RefsByName)
+	go func() {
+		defer close(chIn)
+		for _, v := range inps {
+			chIn <- v
+		}
+	}()
+
+	errs := make(map[string]string)
+	var errStream error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		errStream = bn.NameRefsStream(context.Background(), chIn, chOut)
+	}()
+	for res := range chOut {
+		errs[res.Input.ID] = res.Error
+	}
+	<-done
+
+	assert.Nil(errStream)
+	assert.Len(errs, 3)
+	assert.Empty(errs["1"])
+	assert.Contains(errs["2"], "unknown sort order")
+	assert.Empty(errs["3"])
+}
+
+func TestOptionalLookups(t *testing.T) {
+	assert := assert.New(t)
+	bn := New(config.New(), OptRefFinder(stubFinder{}))
+	ctx := context.Background()
+
+	_, err := bn.PageNames(ctx, 1)
+	assert.ErrorIs(err, reffnd.ErrUnavailable)
+	_, err = bn.Title(ctx, 1)
+	assert.ErrorIs(err, reffnd.ErrUnavailable)
+	_, err = bn.DataInfo(ctx)
+	assert.ErrorIs(err, reffnd.ErrUnavailable)
+}
@@ -1,14 +1,15 @@
 package bhlnames_test
 
 import (
+	"context"
 	"testing"
 
-	"github.com/gnames/bhlnames/internal/ent/input"
 	"github.com/gnames/bhlnames/internal/io/bayesio"
 	"github.com/gnames/bhlnames/internal/io/reffndio"
 	"github.com/gnames/bhlnames/internal/io/ttlmchio"
 	bhlnames "github.com/gnames/bhlnames/pkg"
 	"github.com/gnames/bhlnames/pkg/config"
+	"github.com/gnames/bhlnames/pkg/ent/input"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -81,7 +82,7 @@ func TestNameRefs(t *testing.T) {
 		}
 
 		inp := input.New(bn.ParserPool(), inpOpts...)
-		res, err := bn.NameRefs(inp)
+		res, err := bn.NameRefs(context.Background(), inp)
 		assert.Nil(err, v.msg)
 		assert.Equal(v.current, res.CurrentCanonical, v.msg)
 		assert.Equal(v.refNum, len(res.References), v.msg)
@@ -127,7 +128,7 @@ func TestNameWithRefs(t *testing.T) {
 			input.OptWithNomenEvent(v.nomen),
 		}
 		inp := input.New(bn.ParserPool(), inpOpts...)
-		res, err := bn.NameRefs(inp)
+		res, err := bn.NameRefs(context.Background(), inp)
 		assert.Nil(err)
 		assert.True(len(res.References) > 0)
 		ref := res.References[0]
@@ -203,7 +204,7 @@ func TestNomenRefs(t *testing.T) {
 			input.OptWithNomenEvent(true),
 		}
 		inp := input.New(bn.ParserPool(), inpOpts...)
-		res, err := bn.NameRefs(inp)
+		res, err := bn.NameRefs(context.Background(), inp)
 		assert.Nil(err)
 		assert.True(len(res.References) > 0)
 		ref := res.References[0]
@@ -230,7 +231,7 @@ func TestRefByPageID(t *testing.T) {
 
 	bn := Init(t)
 	for _, v := range tests {
-		ref, err := bn.RefByPageID(v.pageID)
+		ref, err := bn.RefByPageID(context.Background(), v.pageID)
 		assert.Nil(err)
 		assert.Equal(v.itemID, ref.ItemID, v.msg)
 		if v.partIsNil {
@@ -252,10 +253,18 @@ func TestItemStats(t *testing.T) {
 
 	bn := Init(t)
 	for _, v := range tests {
-		item, err := bn.ItemStats(v.itemID)
+		item, err := bn.ItemStats(context.Background(), v.itemID, 2)
 		assert.Nil(err)
 		assert.Equal(v.itemID, item.ItemID, v.msg)
 		assert.Equal(v.titleID, item.TitleID, v.msg)
+		assert.Greater(len(item.Taxa), 0, v.msg)
+		ranks := make(map[string]int)
+		for _, tx := range item.Taxa {
+			ranks[tx.Rank]++
+		}
+		for _, num := range ranks {
+			assert.LessOrEqual(num, 2, v.msg)
+		}
 	}
 }
 
@@ -271,7 +280,8 @@ func TestItemsByTaxon(t *testing.T) {
 
 	bn := Init(t)
 	for _, v := range tests {
-		items, err := bn.ItemsByTaxon(v.taxon)
+		iq := input.NewItemsQuery(v.taxon)
+		items, err := bn.ItemsByTaxon(context.Background(), iq)
 		assert.Nil(err)
 		assert.GreaterOrEqual(len(items), v.itemNum, v.msg)
 		assert.Equal(v.itemID, items[0].ItemID, v.msg)
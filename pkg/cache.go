@@ -0,0 +1,64 @@
+package bhlnames
+
+import (
+	"log/slog"
+
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/cache"
+	"github.com/gnames/bhlnames/pkg/ent/input"
+	"github.com/gnames/gnfmt"
+)
+
+// CacheStats returns usage statistics of the NameRefs cache.
+func (bn bhlnames) CacheStats() cache.Stats {
+	if bn.cache == nil {
+		return cache.Stats{Type: "none"}
+	}
+	return bn.cache.Stats()
+}
+
+// cachedNameRefs returns a result from the cache. The input of the result
+// is replaced by the given input, because the same key is shared by inputs
+// with different IDs.
+func (bn bhlnames) cachedNameRefs(
+	key string,
+	inp input.Input,
+) (*bhl.RefsByName, bool) {
+	bs, ok := bn.cache.Get(key)
+	if !ok {
+		return nil, false
+	}
+
+	var res bhl.RefsByName
+	err := gnfmt.GNgob{}.Decode(bs, &res)
+	if err != nil {
+		slog.Warn("Cannot decode cached result", "error", err)
+		return nil, false
+	}
+	res.Input = inp
+	return &res, true
+}
+
+// cacheNameRefs saves a result to the cache. Errors are logged, because
+// a failing cache should not break the search.
+func (bn bhlnames) cacheNameRefs(key string, res *bhl.RefsByName) {
+	bs, err := gnfmt.GNgob{}.Encode(res)
+	if err == nil {
+		err = bn.cache.Set(key, bs)
+	}
+	if err != nil {
+		slog.Warn("Cannot save result to cache", "error", err)
+	}
+}
+
+// clearCache removes cached results after the data are rebuilt.
+func (bn bhlnames) clearCache() error {
+	if bn.cache == nil {
+		return nil
+	}
+	err := bn.cache.Clear()
+	if err != nil {
+		slog.Error("Cannot clear cache", "error", err)
+	}
+	return err
+}
@@ -0,0 +1,605 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/input"
+)
+
+const (
+	// URL is the address of the public BHLnames API.
+	URL = "https://bhlnames.globalnames.org/api/v1"
+
+	mimeJSON   = "application/json"
+	mimeNDJSON = "application/x-ndjson"
+
+	// maxRetryWait limits the wait time between retries.
+	maxRetryWait = time.Minute
+)
+
+// Option provides an 'interface' for setting up a Client.
+type Option func(*client)
+
+// OptTimeout sets the time limit for one HTTP request, including reading
+// of the response. For NameRefsStream the limit applies to every batch.
+// Zero means no limit.
+func OptTimeout(d time.Duration) Option {
+	return func(c *client) {
+		c.timeout = d
+	}
+}
+
+// OptRetries sets how many times a request is repeated after a network
+// error or a response that says that the server is temporarily
+// unavailable.
+func OptRetries(i int) Option {
+	return func(c *client) {
+		c.retries = i
+	}
+}
+
+// OptRetryWait sets the wait time before the first retry. The wait time
+// doubles with every following retry.
+func OptRetryWait(d time.Duration) Option {
+	return func(c *client) {
+		c.retryWait = d
+	}
+}
+
+// OptHTTPClient sets the HTTP client that sends requests.
+func OptHTTPClient(hc *http.Client) Option {
+	return func(c *client) {
+		c.hc = hc
+	}
+}
+
+// OptBatchSize sets the number of inputs that NameRefsStream sends to the
+// server in one request. It must not exceed the MaxBatchSize setting of
+// the server.
+func OptBatchSize(i int) Option {
+	return func(c *client) {
+		c.batchSize = i
+	}
+}
+
+// client implements Client interface.
+type client struct {
+	// url is the address of the API, including the version path,
+	// for example https://bhlnames.globalnames.org/api/v1.
+	url string
+
+	// hc is the HTTP client for sending requests.
+	hc *http.Client
+
+	// timeout is the time limit for one request.
+	timeout time.Duration
+
+	// retries is the number of repeats of failed requests.
+	retries int
+
+	// retryWait is the wait time before the first retry.
+	retryWait time.Duration
+
+	// batchSize is the number of inputs in one NameRefsStream request.
+	batchSize int
+}
+
+// New creates a new Client for the API at the given URL. If the URL is
+// empty, the public BHLnames API is used.
+func New(apiURL string, opts ...Option) Client {
+	if apiURL == "" {
+		apiURL = URL
+	}
+	res := client{
+		url:       strings.TrimRight(apiURL, "/"),
+		hc:        http.DefaultClient,
+		timeout:   time.Minute,
+		retries:   3,
+		retryWait: 500 * time.Millisecond,
+		batchSize: 50,
+	}
+	for _, opt := range opts {
+		opt(&res)
+	}
+	if res.batchSize < 1 {
+		res.batchSize = 1
+	}
+	return &res
+}
+
+// NameRefs returns references for a name found by the server.
+func (c *client) NameRefs(
+	ctx context.Context,
+	inp input.Input,
+) (*bhl.RefsByName, error) {
+	body, err := json.Marshal(inp)
+	if err != nil {
+		return nil, err
+	}
+	var res bhl.RefsByName
+	err = c.fetch(ctx, http.MethodPost, "/name_refs", mimeJSON, body, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// NameRefsStream sends inputs in batches to the server and returns
+// results one by one.
+func (c *client) NameRefsStream(
+	ctx context.Context,
+	chIn <-chan input.Input,
+	chOut chan<- *bhl.RefsByName,
+) error {
+	defer close(chOut)
+
+	var count int
+	batch := make([]input.Input, 0, c.batchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			for range chIn {
+			}
+			return ctx.Err()
+		case inp, ok := <-chIn:
+			if !ok {
+				if len(batch) == 0 {
+					return nil
+				}
+				return c.nameRefsBatch(ctx, batch, chOut)
+			}
+
+			// the server gives IDs to inputs according to their position in
+			// a batch, make them unique for the whole stream instead.
+			count++
+			if inp.ID == "" {
+				inp.ID = strconv.Itoa(count)
+			}
+			batch = append(batch, inp)
+			if len(batch) < c.batchSize {
+				continue
+			}
+
+			if err := c.nameRefsBatch(ctx, batch, chOut); err != nil {
+				for range chIn {
+				}
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+}
+
+// nameRefsBatch sends a batch of inputs to the server and sends results
+// to chOut as soon as they arrive.
+func (c *client) nameRefsBatch(
+	ctx context.Context,
+	batch []input.Input,
+	chOut chan<- *bhl.RefsByName,
+) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, v := range batch {
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+	}
+
+	resp, cancel, err := c.send(
+		ctx, http.MethodPost, "/name_refs_batch", mimeNDJSON, buf.Bytes(),
+	)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	defer resp.Body.Close()
+
+	var count int
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var res bhl.RefsByName
+		err = dec.Decode(&res)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("cannot read batch results: %w", err)
+		}
+		count++
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case chOut <- &res:
+		}
+	}
+
+	// the server cannot change the status of a response that already
+	// started, so an error shows up as missing results.
+	if count < len(batch) {
+		return fmt.Errorf(
+			"server returned %d results for %d inputs", count, len(batch),
+		)
+	}
+	return nil
+}
+
+// RefByPageID returns a reference to a BHL page.
+func (c *client) RefByPageID(
+	ctx context.Context,
+	pageID int,
+) (*bhl.Reference, error) {
+	var res bhl.Reference
+	path := "/references/" + strconv.Itoa(pageID)
+	err := c.fetch(ctx, http.MethodGet, path, "", nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// PageNames returns names detected on a BHL page.
+func (c *client) PageNames(
+	ctx context.Context,
+	pageID int,
+) (*bhl.PageNames, error) {
+	var res bhl.PageNames
+	path := "/pages/" + strconv.Itoa(pageID) + "/names"
+	err := c.fetch(ctx, http.MethodGet, path, "", nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// RefsByExtID returns cached references for an external ID.
+func (c *client) RefsByExtID(
+	ctx context.Context,
+	extID string,
+	dataSourceID int,
+	allRefs bool,
+) (*bhl.RefsByName, error) {
+	q := url.Values{}
+	q.Set("all_refs", strconv.FormatBool(allRefs))
+	q.Set("data_source_id", strconv.Itoa(dataSourceID))
+	path := "/cached_refs/" + url.PathEscape(extID) + "?" + q.Encode()
+
+	var res *bhl.RefsByName
+	err := c.fetch(ctx, http.MethodGet, path, "", nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ItemStats returns metadata and taxonomic statistics of a BHL item.
+func (c *client) ItemStats(
+	ctx context.Context,
+	itemID, taxaLimit int,
+) (*bhl.Item, error) {
+	var res bhl.Item
+	path := "/items/" + strconv.Itoa(itemID)
+	if taxaLimit > 0 {
+		path += "?taxa_limit=" + strconv.Itoa(taxaLimit)
+	}
+	err := c.fetch(ctx, http.MethodGet, path, "", nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ItemNames returns unique names detected in a BHL item.
+func (c *client) ItemNames(
+	ctx context.Context,
+	itemID int,
+) (*bhl.NameList, error) {
+	var res bhl.NameList
+	path := "/items/" + strconv.Itoa(itemID) + "/names"
+	err := c.fetch(ctx, http.MethodGet, path, "", nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// PartNames returns unique names detected in a BHL part.
+func (c *client) PartNames(
+	ctx context.Context,
+	partID int,
+) (*bhl.NameList, error) {
+	var res bhl.NameList
+	path := "/parts/" + strconv.Itoa(partID) + "/names"
+	err := c.fetch(ctx, http.MethodGet, path, "", nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Title returns metadata and taxonomic profile of a BHL title.
+func (c *client) Title(
+	ctx context.Context,
+	titleID int,
+) (*bhl.Title, error) {
+	var res bhl.Title
+	path := "/titles/" + strconv.Itoa(titleID)
+	err := c.fetch(ctx, http.MethodGet, path, "", nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// TitleItems returns items of a BHL title.
+func (c *client) TitleItems(
+	ctx context.Context,
+	titleID int,
+	f input.ItemsFilter,
+) ([]*bhl.Item, error) {
+	q := filterValues(f)
+	path := "/titles/" + strconv.Itoa(titleID) + "/items"
+	if len(q) > 0 {
+		path += "?" + q.Encode()
+	}
+
+	var res []*bhl.Item
+	err := c.fetch(ctx, http.MethodGet, path, "", nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ItemsByTaxon returns BHL items where the taxon is prevalent.
+func (c *client) ItemsByTaxon(
+	ctx context.Context,
+	iq input.ItemsQuery,
+) ([]*bhl.Item, error) {
+	q := filterValues(iq.ItemsFilter)
+	if iq.Rank != input.RankAny {
+		q.Set("rank", string(iq.Rank))
+	}
+	if iq.MinPercent > 0 {
+		q.Set("min_percent", strconv.Itoa(iq.MinPercent))
+	}
+	if iq.MinNamesNum > 0 {
+		q.Set("min_names", strconv.Itoa(iq.MinNamesNum))
+	}
+	if iq.SortBy != "" {
+		q.Set("sort", string(iq.SortBy))
+	}
+	if iq.Limit > 0 {
+		q.Set("limit", strconv.Itoa(iq.Limit))
+	}
+	if iq.Offset > 0 {
+		q.Set("offset", strconv.Itoa(iq.Offset))
+	}
+	path := "/taxon_items/" + url.PathEscape(iq.Taxon)
+	if len(q) > 0 {
+		path += "?" + q.Encode()
+	}
+
+	var res []*bhl.Item
+	err := c.fetch(ctx, http.MethodGet, path, "", nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ResolveReference returns ranked BHL pages for a citation.
+func (c *client) ResolveReference(
+	ctx context.Context,
+	ref input.Reference,
+) (*bhl.RefsByName, error) {
+	body, err := json.Marshal(ref)
+	if err != nil {
+		return nil, err
+	}
+	var res bhl.RefsByName
+	err = c.fetch(ctx, http.MethodPost, "/resolve_ref", mimeJSON, body, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ParseRef returns parsed fields and matched titles of a reference.
+func (c *client) ParseRef(
+	ctx context.Context,
+	refString string,
+) (*bhl.ParsedRef, error) {
+	body, err := json.Marshal(input.Reference{RefString: refString})
+	if err != nil {
+		return nil, err
+	}
+	var res bhl.ParsedRef
+	err = c.fetch(ctx, http.MethodPost, "/parse_ref", mimeJSON, body, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DataInfo returns provenance of the data served by the API.
+func (c *client) DataInfo(ctx context.Context) (*bhl.DataInfo, error) {
+	var res bhl.DataInfo
+	err := c.fetch(ctx, http.MethodGet, "/data_info", "", nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// filterValues converts the items filter to query parameters.
+func filterValues(f input.ItemsFilter) url.Values {
+	q := url.Values{}
+	if f.YearFrom > 0 {
+		q.Set("year_from", strconv.Itoa(f.YearFrom))
+	}
+	if f.YearTo > 0 {
+		q.Set("year_to", strconv.Itoa(f.YearTo))
+	}
+	if f.Volume != "" {
+		q.Set("volume", f.Volume)
+	}
+	return q
+}
+
+// fetch sends a request and decodes JSON response to res.
+func (c *client) fetch(
+	ctx context.Context,
+	method, path, contentType string,
+	body []byte,
+	res any,
+) error {
+	resp, cancel, err := c.send(ctx, method, path, contentType, body)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	defer resp.Body.Close()
+
+	err = json.NewDecoder(resp.Body).Decode(res)
+	if err != nil {
+		return fmt.Errorf("cannot decode response of %s: %w", path, err)
+	}
+	return nil
+}
+
+// send sends a request, repeating it if the server is temporarily
+// unavailable. It returns a successful response, the caller has to close
+// its body and to call the returned function when the body is read.
+func (c *client) send(
+	ctx context.Context,
+	method, path, contentType string,
+	body []byte,
+) (*http.Response, context.CancelFunc, error) {
+	var err error
+	wait := c.retryWait
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			if err = sleep(ctx, wait); err != nil {
+				return nil, nil, err
+			}
+			wait = min(2*wait, maxRetryWait)
+		}
+
+		var resp *http.Response
+		var cancel context.CancelFunc
+		var retry bool
+		resp, cancel, retry, err = c.try(ctx, method, path, contentType, body)
+		if err == nil {
+			return resp, cancel, nil
+		}
+		if !retry || attempt >= c.retries || ctx.Err() != nil {
+			return nil, nil, err
+		}
+		if d := retryAfter(err); d > 0 {
+			wait = min(d, maxRetryWait)
+		}
+	}
+}
+
+// try sends a request once. It returns true together with an error if it
+// makes sense to repeat the request.
+func (c *client) try(
+	ctx context.Context,
+	method, path, contentType string,
+	body []byte,
+) (*http.Response, context.CancelFunc, bool, error) {
+	var cancel context.CancelFunc
+	if c.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+
+	var rd io.Reader
+	if body != nil {
+		rd = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, rd)
+	if err != nil {
+		cancel()
+		return nil, nil, false, err
+	}
+	req.Header.Set("Accept", mimeJSON)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.hc.Do(req)
+	if err != nil {
+		cancel()
+		return nil, nil, true, err
+	}
+	if resp.StatusCode == http.StatusOK {
+		return resp, cancel, false, nil
+	}
+
+	defer cancel()
+	defer resp.Body.Close()
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, true, err
+	}
+	apiErr := newError(resp.StatusCode, bs)
+	apiErr.retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+	return nil, nil, isRetryStatus(resp.StatusCode), apiErr
+}
+
+// retryAfter returns the wait time requested by the server, or zero.
+func retryAfter(err error) time.Duration {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.retryAfter
+	}
+	return 0
+}
+
+// parseRetryAfter converts the Retry-After header with a number of seconds
+// to duration.
+func parseRetryAfter(s string) time.Duration {
+	i, err := strconv.Atoi(s)
+	if err != nil || i < 0 {
+		return 0
+	}
+	return time.Duration(i) * time.Second
+}
+
+// isRetryStatus returns true for statuses of temporary problems.
+func isRetryStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// sleep waits for a given time or until the context is cancelled.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
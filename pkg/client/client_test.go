@@ -0,0 +1,488 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gnames/bhlnames/internal/io/restio"
+	bhlnames "github.com/gnames/bhlnames/pkg"
+	"github.com/gnames/bhlnames/pkg/client"
+	"github.com/gnames/bhlnames/pkg/config"
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/input"
+	"github.com/gnames/bhlnames/pkg/ent/reffnd"
+	"github.com/stretchr/testify/assert"
+)
+
+// memFinder is a RefFinder that keeps data in memory.
+type memFinder struct{}
+
+func (m memFinder) ReferencesByName(
+	_ context.Context,
+	inp input.Input,
+	_ config.Config,
+) (*bhl.RefsByName, error) {
+	res := m.EmptyNameRefs(inp)
+	res.Canonical = inp.CanonicalSimple
+	if inp.CanonicalSimple == "Pardosa moesta" {
+		res.References = []*bhl.ReferenceName{
+			{Reference: bhl.Reference{PageID: 2, YearAggr: 1910}},
+			{Reference: bhl.Reference{PageID: 1, YearAggr: 1892}},
+		}
+	}
+	return res, nil
+}
+
+func (m memFinder) ReferencesByCitation(
+	_ context.Context,
+	_ input.Reference,
+	titleIDs []int,
+) ([]*bhl.ReferenceName, error) {
+	var res []*bhl.ReferenceName
+	if len(titleIDs) == 0 || titleIDs[0] != 7928 {
+		return res, nil
+	}
+	for _, v := range []struct {
+		pageID, year, pageNum int
+		vol                   string
+	}{
+		{11, 2005, 50, "v.31"},
+		{10, 2008, 50, "v.34"},
+	} {
+		res = append(res, &bhl.ReferenceName{NameData: &bhl.NameData{}, Reference: bhl.Reference{
+			TitleID:       7928,
+			PageID:        v.pageID,
+			PageNum:       v.pageNum,
+			Volume:        v.vol,
+			ItemYearStart: v.year,
+			YearAggr:      v.year,
+			YearType:      "Item",
+		}})
+	}
+	return res, nil
+}
+
+func (m memFinder) EmptyNameRefs(inp input.Input) *bhl.RefsByName {
+	return &bhl.RefsByName{Meta: bhl.Meta{Input: inp}}
+}
+
+func (m memFinder) RefByPageID(
+	_ context.Context,
+	pageID int,
+) (*bhl.Reference, error) {
+	if pageID != 1 {
+		msg := fmt.Sprintf("page %d is not found", pageID)
+		return nil, reffnd.NewError(reffnd.ErrNotFound, msg, nil)
+	}
+	return &bhl.Reference{PageID: 1, YearAggr: 1892}, nil
+}
+
+func (m memFinder) PageNames(
+	_ context.Context,
+	pageID int,
+) (*bhl.PageNames, error) {
+	if pageID != 1 {
+		msg := fmt.Sprintf("page %d is not found", pageID)
+		return nil, reffnd.NewError(reffnd.ErrNotFound, msg, nil)
+	}
+	names := []*bhl.PageName{
+		{Name: "Pardosa moesta", OffsetStart: 10, OffsetEnd: 24},
+	}
+	return &bhl.PageNames{PageID: 1, NamesNum: 1, Names: names}, nil
+}
+
+func (m memFinder) RefsByExtID(
+	_ context.Context,
+	extID string,
+	dataSourceID int,
+) (*bhl.RefsByName, error) {
+	if extID == "unknown" {
+		msg := fmt.Sprintf("external ID %s is not found", extID)
+		return nil, reffnd.NewError(reffnd.ErrNotFound, msg, nil)
+	}
+	res := bhl.RefsByName{Meta: bhl.Meta{Input: input.Input{ID: extID}}}
+	for i := range dataSourceID + 1 {
+		ref := bhl.ReferenceName{Reference: bhl.Reference{PageID: i}}
+		res.References = append(res.References, &ref)
+	}
+	return &res, nil
+}
+
+func (m memFinder) ItemStats(
+	_ context.Context,
+	itemID, taxaLimit int,
+) (*bhl.Item, error) {
+	res := bhl.Item{ItemMeta: bhl.ItemMeta{ItemID: itemID}}
+	res.Taxa = []*bhl.ItemTaxon{
+		{Rank: "order", Taxon: "Coleoptera", NamesNum: 20, Percent: 40},
+		{Rank: "order", Taxon: "Lepidoptera", NamesNum: 10, Percent: 20},
+	}
+	if taxaLimit > 0 && taxaLimit < len(res.Taxa) {
+		res.Taxa = res.Taxa[:taxaLimit]
+	}
+	return &res, nil
+}
+
+func (m memFinder) ItemNames(
+	_ context.Context,
+	itemID int,
+) (*bhl.NameList, error) {
+	names := []*bhl.NameStats{{Name: "Pardosa moesta", OccurrencesNum: 2}}
+	return &bhl.NameList{ItemID: itemID, NamesNum: 1, Names: names}, nil
+}
+
+func (m memFinder) PartNames(
+	_ context.Context,
+	partID int,
+) (*bhl.NameList, error) {
+	return &bhl.NameList{PartID: partID, Names: []*bhl.NameStats{}}, nil
+}
+
+func (m memFinder) Title(
+	_ context.Context,
+	titleID int,
+) (*bhl.Title, error) {
+	return &bhl.Title{TitleID: titleID, ItemsNum: 2}, nil
+}
+
+func (m memFinder) TitleItems(
+	_ context.Context,
+	titleID int,
+	f input.ItemsFilter,
+) ([]*bhl.Item, error) {
+	itm := bhl.Item{ItemMeta: bhl.ItemMeta{
+		ItemID: f.YearFrom, TitleID: titleID, Volume: f.Volume,
+	}}
+	return []*bhl.Item{&itm}, nil
+}
+
+func (m memFinder) ItemsByTaxon(
+	_ context.Context,
+	iq input.ItemsQuery,
+) ([]*bhl.Item, error) {
+	if iq.Taxon == "" {
+		return nil, reffnd.NewError(reffnd.ErrInvalidInput, "empty taxon", nil)
+	}
+	res := []*bhl.Item{
+		{ItemMeta: bhl.ItemMeta{ItemID: 1}},
+		{ItemMeta: bhl.ItemMeta{ItemID: 2}},
+	}
+	if iq.Limit > 0 && iq.Limit < len(res) {
+		res = res[:iq.Limit]
+	}
+	return res, nil
+}
+
+func (m memFinder) DataInfo(context.Context) (*bhl.DataInfo, error) {
+	return &bhl.DataInfo{
+		Sources: []*bhl.DataSource{{Name: "bhl", SHA256: "abc"}},
+		Builds: []*bhl.Build{
+			{Kind: "init", TableRows: map[string]int64{"items": 10}},
+		},
+	}, nil
+}
+
+func (m memFinder) Close() {}
+
+// memMatcher is a TitleMatcher that knows only one title.
+type memMatcher struct{}
+
+func (m memMatcher) TitlesBHL(
+	context.Context,
+	string,
+) (map[int][]string, error) {
+	return map[int][]string{7928: {"dm"}}, nil
+}
+
+func (m memMatcher) RefTitles(
+	_ context.Context,
+	refString string,
+) (*bhl.RefTitles, error) {
+	return &bhl.RefTitles{
+		Abbr:        "dmm",
+		AbbrMatches: []string{"dm"},
+		Titles: []*bhl.TitleMatch{{
+			TitleID: 7928, TitleName: "Documents mycologiques", Abbrs: []string{"dm"},
+		}},
+	}, nil
+}
+
+func (m memMatcher) Close() {}
+
+func newServer(t *testing.T) (*httptest.Server, bhlnames.BHLnames) {
+	cfg := config.New(config.OptMaxBatchSize(5))
+	bn := bhlnames.New(cfg,
+		bhlnames.OptRefFinder(memFinder{}),
+		bhlnames.OptTitleMatcher(memMatcher{}),
+	)
+	srv := httptest.NewServer(restio.New(bn).Handler())
+	t.Cleanup(func() {
+		srv.Close()
+		bn.Close()
+	})
+	return srv, bn
+}
+
+func TestNameRefs(t *testing.T) {
+	assert := assert.New(t)
+	srv, bn := newServer(t)
+	cl := client.New(srv.URL + "/api/v1")
+
+	inp := input.New(bn.ParserPool(),
+		input.OptNameString("Pardosa moesta Banks, 1892"),
+		input.OptSortBy(input.SortYear),
+	)
+	res, err := cl.NameRefs(context.Background(), inp)
+	assert.Nil(err)
+	assert.Equal("Pardosa moesta", res.Canonical)
+	assert.Equal(2, len(res.References))
+	assert.Equal(1, res.References[0].PageID)
+}
+
+func TestNameRefsStream(t *testing.T) {
+	assert := assert.New(t)
+	srv, bn := newServer(t)
+	cl := client.New(srv.URL+"/api/v1", client.OptBatchSize(3))
+
+	names := []string{"Pardosa moesta", "Bubo bubo", "Aus bus", "Aus cus",
+		"Aus dus", "Aus eus", "Aus fus"}
+	chIn := make(chan input.Input)
+	chOut := make(chan *bhl.RefsByName)
+	go func() {
+		defer close(chIn)
+		for _, v := range names {
+			inp := input.New(bn.ParserPool(), input.OptNameString(v))
+			inp.ID = ""
+			chIn <- inp
+		}
+	}()
+
+	var ids []string
+	var refsNum int
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for res := range chOut {
+			ids = append(ids, res.Meta.Input.ID)
+			refsNum += len(res.References)
+		}
+	}()
+
+	err := cl.NameRefsStream(context.Background(), chIn, chOut)
+	<-done
+	assert.Nil(err)
+	assert.Equal(len(names), len(ids))
+	assert.Equal(2, refsNum)
+
+	// ids are unique for the whole stream, not for a batch.
+	sort.Strings(ids)
+	assert.Equal([]string{"1", "2", "3", "4", "5", "6", "7"}, ids)
+}
+
+func TestRefByPageID(t *testing.T) {
+	assert := assert.New(t)
+	srv, _ := newServer(t)
+	cl := client.New(srv.URL + "/api/v1/")
+
+	res, err := cl.RefByPageID(context.Background(), 1)
+	assert.Nil(err)
+	assert.Equal(1892, res.YearAggr)
+
+	_, err = cl.RefByPageID(context.Background(), 2)
+	assert.ErrorIs(err, reffnd.ErrNotFound)
+	var apiErr *client.Error
+	assert.ErrorAs(err, &apiErr)
+	assert.Equal(http.StatusNotFound, apiErr.Status)
+	assert.Equal("not_found", apiErr.Code)
+	assert.Equal("page 2 is not found", apiErr.Message)
+}
+
+func TestPageNames(t *testing.T) {
+	assert := assert.New(t)
+	srv, _ := newServer(t)
+	cl := client.New(srv.URL + "/api/v1")
+
+	res, err := cl.PageNames(context.Background(), 1)
+	assert.Nil(err)
+	assert.Equal(1, res.NamesNum)
+	assert.Equal("Pardosa moesta", res.Names[0].Name)
+	assert.Equal(24, res.Names[0].OffsetEnd)
+
+	_, err = cl.PageNames(context.Background(), 2)
+	assert.ErrorIs(err, reffnd.ErrNotFound)
+}
+
+func TestParseRef(t *testing.T) {
+	assert := assert.New(t)
+	srv, _ := newServer(t)
+	cl := client.New(srv.URL + "/api/v1")
+
+	res, err := cl.ParseRef(context.Background(),
+		"Docums Mycol. 34:50-51. (2008).")
+	assert.Nil(err)
+	assert.Equal(2008, res.YearStart)
+	assert.Equal(34, res.Volume)
+	assert.Equal(50, res.PageStart)
+	assert.Equal(51, res.PageEnd)
+	assert.Equal("dmm", res.Abbr)
+	assert.Equal(1, len(res.Titles))
+	assert.Equal(7928, res.Titles[0].TitleID)
+
+	_, err = cl.ParseRef(context.Background(), " ")
+	assert.ErrorIs(err, reffnd.ErrInvalidInput)
+}
+
+func TestResolveReference(t *testing.T) {
+	assert := assert.New(t)
+	srv, _ := newServer(t)
+	cl := client.New(srv.URL + "/api/v1")
+
+	ref := input.Reference{RefString: "Docums Mycol. 34:50-51. (2008)."}
+	res, err := cl.ResolveReference(context.Background(), ref)
+	assert.Nil(err)
+	assert.Equal(2, res.ReferenceNumber)
+	assert.Equal(2008, res.Input.RefYearStart)
+	assert.Equal(34, res.Input.Volume)
+	assert.Equal(10, res.References[0].PageID)
+	assert.Equal("match", res.References[0].Score.Labels["vol"])
+	assert.Nil(res.References[0].NameData)
+
+	_, err = cl.ResolveReference(context.Background(), input.Reference{})
+	assert.ErrorIs(err, reffnd.ErrInvalidInput)
+}
+
+func TestRefsByExtID(t *testing.T) {
+	assert := assert.New(t)
+	srv, _ := newServer(t)
+	cl := client.New(srv.URL + "/api/v1")
+
+	res, err := cl.RefsByExtID(context.Background(), "3W7R6", 2, true)
+	assert.Nil(err)
+	assert.Equal("3W7R6", res.Meta.Input.ID)
+	assert.Equal(3, len(res.References))
+
+	res, err = cl.RefsByExtID(context.Background(), "3W7R6", 2, false)
+	assert.Nil(err)
+	assert.Equal(1, len(res.References))
+
+	_, err = cl.RefsByExtID(context.Background(), "unknown", 1, false)
+	assert.ErrorIs(err, reffnd.ErrNotFound)
+}
+
+func TestItems(t *testing.T) {
+	assert := assert.New(t)
+	srv, _ := newServer(t)
+	cl := client.New(srv.URL + "/api/v1")
+
+	item, err := cl.ItemStats(context.Background(), 73397, 0)
+	assert.Nil(err)
+	assert.Equal(73397, item.ItemID)
+	assert.Equal(2, len(item.Taxa))
+
+	item, err = cl.ItemStats(context.Background(), 73397, 1)
+	assert.Nil(err)
+	assert.Equal(1, len(item.Taxa))
+	assert.Equal("Coleoptera", item.Taxa[0].Taxon)
+
+	iq := input.NewItemsQuery("Lepidoptera", input.OptItemsLimit(1))
+	items, err := cl.ItemsByTaxon(context.Background(), iq)
+	assert.Nil(err)
+	assert.Equal(1, len(items))
+
+	title, err := cl.Title(context.Background(), 7928)
+	assert.Nil(err)
+	assert.Equal(7928, title.TitleID)
+	assert.Equal(2, title.ItemsNum)
+
+	f := input.ItemsFilter{YearFrom: 1890, Volume: "v. 12"}
+	items, err = cl.TitleItems(context.Background(), 7928, f)
+	assert.Nil(err)
+	assert.Equal(1, len(items))
+	assert.Equal(1890, items[0].ItemID)
+	assert.Equal("v. 12", items[0].Volume)
+
+	names, err := cl.ItemNames(context.Background(), 73397)
+	assert.Nil(err)
+	assert.Equal(73397, names.ItemID)
+	assert.Equal(2, names.Names[0].OccurrencesNum)
+
+	names, err = cl.PartNames(context.Background(), 39371)
+	assert.Nil(err)
+	assert.Equal(39371, names.PartID)
+	assert.Equal(0, len(names.Names))
+}
+
+func TestRetries(t *testing.T) {
+	assert := assert.New(t)
+	srv, _ := newServer(t)
+
+	var calls atomic.Int32
+	flaky := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) <= 2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			http.Redirect(w, r, srv.URL+r.URL.String(), http.StatusTemporaryRedirect)
+		},
+	))
+	defer flaky.Close()
+
+	cl := client.New(flaky.URL+"/api/v1",
+		client.OptRetryWait(time.Millisecond))
+	res, err := cl.ItemStats(context.Background(), 1, 0)
+	assert.Nil(err)
+	assert.Equal(1, res.ItemID)
+	assert.Equal(int32(3), calls.Load())
+
+	calls.Store(0)
+	cl = client.New(flaky.URL+"/api/v1",
+		client.OptRetries(1), client.OptRetryWait(time.Millisecond))
+	_, err = cl.ItemStats(context.Background(), 1, 0)
+	assert.ErrorIs(err, reffnd.ErrUnavailable)
+	assert.Equal(int32(2), calls.Load())
+}
+
+func TestTimeout(t *testing.T) {
+	assert := assert.New(t)
+	slow := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+		},
+	))
+	defer slow.Close()
+
+	cl := client.New(slow.URL,
+		client.OptTimeout(10*time.Millisecond), client.OptRetries(0))
+	_, err := cl.ItemStats(context.Background(), 1, 0)
+	assert.ErrorIs(err, context.DeadlineExceeded)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cl = client.New(slow.URL)
+	_, err = cl.ItemStats(ctx, 1, 0)
+	assert.ErrorIs(err, context.Canceled)
+}
+
+func TestDataInfo(t *testing.T) {
+	assert := assert.New(t)
+	srv, _ := newServer(t)
+	cl := client.New(srv.URL + "/api/v1")
+
+	res, err := cl.DataInfo(context.Background())
+	assert.Nil(err)
+	assert.Equal(bhlnames.Version, res.Version)
+	assert.Equal(1, len(res.Sources))
+	assert.Equal("abc", res.Sources[0].SHA256)
+	assert.Equal(1, len(res.Builds))
+	assert.Equal(int64(10), res.Builds[0].TableRows["items"])
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gnames/bhlnames/pkg/ent/reffnd"
+	"github.com/gnames/bhlnames/pkg/ent/rest"
+)
+
+// Error is returned when the API server responds with an error status.
+// Use errors.Is with reffnd.ErrNotFound, reffnd.ErrInvalidInput or
+// reffnd.ErrUnavailable to check the kind of the error.
+type Error struct {
+	rest.ErrorResponse
+
+	// retryAfter is the wait time before the next request, requested by
+	// the server.
+	retryAfter time.Duration
+}
+
+// newError creates an Error from the response status and body. The body is
+// used as a message if it is not a JSON error response.
+func newError(status int, body []byte) *Error {
+	res := Error{ErrorResponse: rest.ErrorResponse{Status: status}}
+	if err := json.Unmarshal(body, &res.ErrorResponse); err != nil {
+		res.Message = strings.TrimSpace(string(body))
+	}
+	// the status of the response takes priority over the body.
+	res.Status = status
+	if res.Message == "" {
+		res.Message = http.StatusText(status)
+	}
+	return &res
+}
+
+// Error implements error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf("bhlnames API error %d: %s", e.Status, e.Message)
+}
+
+// Unwrap returns the kind of the error according to the HTTP status.
+func (e *Error) Unwrap() error {
+	switch e.Status {
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge:
+		return reffnd.ErrInvalidInput
+	case http.StatusNotFound:
+		return reffnd.ErrNotFound
+	case http.StatusServiceUnavailable:
+		return reffnd.ErrUnavailable
+	default:
+		return nil
+	}
+}
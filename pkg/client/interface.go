@@ -0,0 +1,89 @@
+// Package client provides access to the BHLnames REST API. It implements
+// the query methods of BHLnames over HTTP, so remote services can use the
+// API the same way as the bhlnames library.
+package client
+
+import (
+	"context"
+
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/input"
+)
+
+// Client sends queries to a BHLnames API server. Failed requests are
+// retried if the server is temporarily unavailable. Errors returned by the
+// server are of the *Error type.
+type Client interface {
+	// NameRefs accepts a scientific name and optional reference. It returns a
+	// collection of matching references found within the BHL corpus.
+	NameRefs(context.Context, input.Input) (*bhl.RefsByName, error)
+
+	// NameRefsStream sends inputs from chIn to the server in batches and
+	// returns the results to chOut. The order of results might differ from
+	// the order of inputs, use IDs of inputs to match them. If the search
+	// fails for an input, its result has the Error field set. The chOut
+	// channel is closed when all results are sent.
+	NameRefsStream(
+		ctx context.Context,
+		chIn <-chan input.Input,
+		chOut chan<- *bhl.RefsByName,
+	) error
+
+	// RefByPageID returns BHL metadata for a given pageID.
+	RefByPageID(ctx context.Context, pageID int) (*bhl.Reference, error)
+
+	// PageNames returns all names detected on a BHL page with their
+	// offsets in the page text.
+	PageNames(ctx context.Context, pageID int) (*bhl.PageNames, error)
+
+	// RefsByExtID returns BHL metadata for a given external ID and data-source
+	// ID. If allRefs is true, it returns all cached references for
+	// the external ID. Otherwise it returns only the best match.
+	RefsByExtID(
+		ctx context.Context,
+		extID string,
+		dataSourceID int,
+		allRefs bool,
+	) (*bhl.RefsByName, error)
+
+	// ItemStats returns metadata for a given itemID as well as the
+	// statisics about taxonomic groups mentioned in the item. If taxaLimit
+	// is positive, the taxonomic distribution contains only this number of
+	// the largest taxa for each rank.
+	ItemStats(ctx context.Context, itemID, taxaLimit int) (*bhl.Item, error)
+
+	// ItemNames returns unique names detected in a BHL item.
+	ItemNames(ctx context.Context, itemID int) (*bhl.NameList, error)
+
+	// PartNames returns unique names detected in a BHL part.
+	PartNames(ctx context.Context, partID int) (*bhl.NameList, error)
+
+	// Title returns metadata of a BHL title and its taxonomic profile.
+	Title(ctx context.Context, titleID int) (*bhl.Title, error)
+
+	// TitleItems returns items of a BHL title that satisfy the filter.
+	TitleItems(
+		ctx context.Context,
+		titleID int,
+		f input.ItemsFilter,
+	) ([]*bhl.Item, error)
+
+	// ItemsByTaxon returns a collection of BHL items that have provided
+	// taxon as the main taxon mentioned in the item.
+	ItemsByTaxon(ctx context.Context, iq input.ItemsQuery) ([]*bhl.Item, error)
+
+	// ResolveReference returns BHL pages that match a citation, the best
+	// match goes first.
+	ResolveReference(
+		ctx context.Context,
+		ref input.Reference,
+	) (*bhl.RefsByName, error)
+
+	// ParseRef returns years, volume and pages extracted from a reference
+	// string, as well as BHL titles that match it by abbreviations.
+	ParseRef(ctx context.Context, refString string) (*bhl.ParsedRef, error)
+
+	// DataInfo returns datasets the data were built from, the latest
+	// builds and the version of the server.
+	DataInfo(ctx context.Context) (*bhl.DataInfo, error)
+}
@@ -13,36 +13,102 @@ import (
 type Config struct {
 
 	// BHLDumpURL specifies the source for Biodiversity Heritage Library dump
-	// files.
+	// files. It can be an HTTP(S) URL, a file:// URL or a local path.
 	BHLDumpURL string
 
+	// BHLDumpSHA256 is an optional SHA-256 checksum of the BHL dump file.
+	// If it is set, the downloaded file must match it.
+	BHLDumpSHA256 string
+
 	// BHLNamesURL specifies the source for BHLindex data (name occurrences and
-	// verifications).
+	// verifications). It can be an HTTP(S) URL, a file:// URL or a local
+	// path.
 	BHLNamesURL string
 
+	// BHLNamesSHA256 is an optional SHA-256 checksum of the BHLindex data
+	// file.
+	BHLNamesSHA256 string
+
+	// CacheType sets the cache for name-reference results. It can be
+	// "none" (default), "memory" or "disk".
+	CacheType string
+
+	// CacheSize is the maximum number of results kept in the memory cache.
+	CacheSize int
+
+	// CacheTTL is the number of seconds a cached result stays valid. Zero
+	// or negative value means that results do not expire.
+	CacheTTL int
+
 	// CoLDataURL specifies the source for Catalogue of Life data in Darwin Core
-	// Archive format.
+	// Archive format. It can be an HTTP(S) URL, a file:// URL or a local path.
 	CoLDataURL string
 
+	// CoLDataSHA256 is an optional SHA-256 checksum of the Catalogue of Life
+	// data file.
+	CoLDataSHA256 string
+
 	// DbDatabase is the name of the PostgreSQL database for BHLnames data.
 	DbDatabase string
 
+	// DbDSN is a full PostgreSQL connection string (URL or key=value
+	// format). If it is set, it overrides DbHost, DbPort, DbDatabase,
+	// DbUser, DbPass and SSL settings.
+	DbDSN string
+
 	// DbHost is the hostname or IP address of the PostgreSQL server.
 	DbHost string
 
+	// DbPort is the port of the PostgreSQL server.
+	DbPort int
+
+	// DbSSLMode is the SSL mode of the database connection ("disable",
+	// "require", "verify-ca", "verify-full" etc.).
+	DbSSLMode string
+
+	// DbSSLRootCert is the path to the root certificate used to verify
+	// the database server.
+	DbSSLRootCert string
+
+	// DbMaxConns is the maximum number of connections in the database
+	// pool. Zero means the default of the driver.
+	DbMaxConns int
+
+	// DbMinConns is the minimum number of idle connections kept in the
+	// database pool.
+	DbMinConns int
+
+	// DbStatementTimeout is the maximum number of seconds PostgreSQL runs
+	// a statement before cancelling it. Zero disables the timeout.
+	DbStatementTimeout int
+
 	// DbUser is the PostgreSQL username with write permissions to the database.
 	DbUser string
 
 	// DbPass is the password for `DBUser`.
 	DbPass string
 
+	// DbQueryTimeout is the maximum number of seconds a single database query
+	// is allowed to run. Zero or negative value disables the timeout.
+	DbQueryTimeout int
+
 	// JobsNum controls the concurrency level for finding references
 	// containing specified names.
 	JobsNum int
 
+	// MaxBatchSize is the maximum number of inputs allowed in one batch
+	// request to the RESTful service.
+	MaxBatchSize int
+
 	// PortREST specifies the port number for the BHLnames RESTful service.
 	PortREST int
 
+	// QAThresholds sets the maximum percentage of anomalies for data
+	// quality checks of a build. The key is the name of a check. If a check
+	// exceeds its threshold, the build is not published. Checks without
+	// thresholds never fail.
+	QAThresholds map[string]float64
+
 	// RootDir is the base directory for all BHLnames downloaded and extracted
 	// files.
 	RootDir string
@@ -59,6 +125,17 @@ type Config struct {
 	// compressed files.
 	ExtractDir string
 
+	// CacheDir is the directory for the disk cache.
+	CacheDir string
+
+	// QAReportFile is the full path to the data quality report of the
+	// latest build.
+	QAReportFile string
+
+	// QAUpdateReportFile is the full path to the data quality report of
+	// records changed by the latest update.
+	QAUpdateReportFile string
+
 	// DownloadNamesFile is the full path where the downloaded BHLindex Data file
 	// is stored.
 	DownloadNamesFile string
@@ -87,6 +164,13 @@ func OptBHLDumpURL(s string) Option {
 	}
 }
 
+// OptBHLDumpSHA256 sets the expected SHA-256 checksum of the BHL dump.
+func OptBHLDumpSHA256(s string) Option {
+	return func(cfg *Config) {
+		cfg.BHLDumpSHA256 = s
+	}
+}
+
 // OptBHLNamesURL sets the URL for BHLindex data.
 func OptBHLNamesURL(s string) Option {
 	return func(cfg *Config) {
@@ -94,6 +178,37 @@ func OptBHLNamesURL(s string) Option {
 	}
 }
 
+// OptBHLNamesSHA256 sets the expected SHA-256 checksum of BHLindex data.
+func OptBHLNamesSHA256(s string) Option {
+	return func(cfg *Config) {
+		cfg.BHLNamesSHA256 = s
+	}
+}
+
+// OptCacheType sets the type of the results cache ("none", "memory" or
+// "disk").
+func OptCacheType(s string) Option {
+	return func(cfg *Config) {
+		cfg.CacheType = s
+	}
+}
+
+// OptCacheSize sets the maximum number of results in the memory cache.
+func OptCacheSize(i int) Option {
+	return func(cfg *Config) {
+		if i > 0 {
+			cfg.CacheSize = i
+		}
+	}
+}
+
+// OptCacheTTL sets the number of seconds a cached result stays valid.
+func OptCacheTTL(i int) Option {
+	return func(cfg *Config) {
+		cfg.CacheTTL = i
+	}
+}
+
 // OptCoLDataURL sets the URL for the Catalogue of Life data.
 func OptCoLDataURL(s string) Option {
 	return func(cfg *Config) {
@@ -101,6 +216,14 @@ func OptCoLDataURL(s string) Option {
 	}
 }
 
+// OptCoLDataSHA256 sets the expected SHA-256 checksum of the Catalogue of
+// Life data.
+func OptCoLDataSHA256(s string) Option {
+	return func(cfg *Config) {
+		cfg.CoLDataSHA256 = s
+	}
+}
+
 // OptDbDatabase sets the name of the PostgreSQL database for BHLnames data.
 func OptDbDatabase(s string) Option {
 	return func(cfg *Config) {
@@ -108,6 +231,14 @@ func OptDbDatabase(s string) Option {
 	}
 }
 
+// OptDbDSN sets a full PostgreSQL connection string that overrides other
+// connection settings.
+func OptDbDSN(s string) Option {
+	return func(cfg *Config) {
+		cfg.DbDSN = s
+	}
+}
+
 // OptDbHost sets the hostname or IP address of the PostgreSQL server.
 func OptDbHost(s string) Option {
 	return func(cfg *Config) {
@@ -115,6 +246,66 @@ func OptDbHost(s string) Option {
 	}
 }
 
+// OptDbPort sets the port of the PostgreSQL server.
+func OptDbPort(i int) Option {
+	return func(cfg *Config) {
+		if i > 0 {
+			cfg.DbPort = i
+		}
+	}
+}
+
+// OptDbSSLMode sets the SSL mode of the database connection.
+func OptDbSSLMode(s string) Option {
+	return func(cfg *Config) {
+		cfg.DbSSLMode = s
+	}
+}
+
+// OptDbSSLRootCert sets the path to the root certificate of the database
+// server.
+func OptDbSSLRootCert(s string) Option {
+	return func(cfg *Config) {
+		var err error
+		s, err = gnsys.ConvertTilda(s)
+		if err != nil {
+			slog.Error("Cannot convert tilda to path.", "error", err)
+			os.Exit(1)
+		}
+		cfg.DbSSLRootCert = s
+	}
+}
+
+// OptDbMaxConns sets the maximum number of connections in the database
+// pool.
+func OptDbMaxConns(i int) Option {
+	return func(cfg *Config) {
+		if i >= 0 {
+			cfg.DbMaxConns = i
+		}
+	}
+}
+
+// OptDbMinConns sets the minimum number of idle connections in the
+// database pool.
+func OptDbMinConns(i int) Option {
+	return func(cfg *Config) {
+		if i >= 0 {
+			cfg.DbMinConns = i
+		}
+	}
+}
+
+// OptDbStatementTimeout sets the maximum number of seconds PostgreSQL runs
+// a statement.
+func OptDbStatementTimeout(i int) Option {
+	return func(cfg *Config) {
+		if i >= 0 {
+			cfg.DbStatementTimeout = i
+		}
+	}
+}
+
 // OptDbUser sets the PostgreSQL username with write permissions to the
 // database.
 func OptDbUser(s string) Option {
@@ -130,6 +321,14 @@ func OptDbPass(s string) Option {
 	}
 }
 
+// OptDbQueryTimeout sets the maximum number of seconds for a database
+// query.
+func OptDbQueryTimeout(i int) Option {
+	return func(cfg *Config) {
+		cfg.DbQueryTimeout = i
+	}
+}
+
 // OptJobsNum sets the concurrency level for finding references containing
 func OptJobsNum(i int) Option {
 	return func(cfg *Config) {
@@ -137,6 +336,16 @@ func OptJobsNum(i int) Option {
 	}
 }
 
+// OptMaxBatchSize sets the maximum number of inputs in one batch request
+// to the RESTful service.
+func OptMaxBatchSize(i int) Option {
+	return func(cfg *Config) {
+		if i > 0 {
+			cfg.MaxBatchSize = i
+		}
+	}
+}
+
 // OptPortREST sets the port number for the BHLnames RESTful service.
 func OptPortREST(i int) Option {
 	return func(cfg *Config) {
@@ -161,6 +370,14 @@ func OptRootDir(s string) Option {
 	}
 }
 
+// OptQAThresholds sets the maximum percentage of anomalies for data
+// quality checks.
+func OptQAThresholds(m map[string]float64) Option {
+	return func(cfg *Config) {
+		cfg.QAThresholds = m
+	}
+}
+
 // OptWithCoLDataTrim sets the CoL data trim option.
 func OptWithCoLDataTrim(b bool) Option {
 	return func(cfg *Config) {
@@ -189,12 +406,19 @@ func New(opts ...Option) Config {
 	cfg := Config{
 		BHLDumpURL:      "http://opendata.globalnames.org/bhlnames/bhl-data.zip",
 		BHLNamesURL:     "http://opendata.globalnames.org/bhlnames/names.zip",
+		CacheType:       "none",
+		CacheSize:       10_000,
+		CacheTTL:        86_400,
 		CoLDataURL:      "http://opendata.globalnames.org/bhlnames/col.zip",
 		DbDatabase:      "bhlnames",
 		DbHost:          "0.0.0.0",
+		DbPort:          5432,
+		DbSSLMode:       "disable",
 		DbUser:          "postgres",
 		DbPass:          "postgres",
+		DbQueryTimeout:  120,
 		JobsNum:         4,
+		MaxBatchSize:    5000,
 		PortREST:        8888,
 		RootDir:         RootDir(),
 		WithRebuild:     false,
@@ -214,5 +438,8 @@ func New(opts ...Option) Config {
 	cfg.DownloadNamesFile = filepath.Join(cfg.RootDir, "bhlindex-latest.zip")
 	cfg.DownloadCoLFile = filepath.Join(cfg.RootDir, "col.zip")
 	cfg.ExtractDir = filepath.Join(cfg.RootDir, "Data")
+	cfg.CacheDir = filepath.Join(cfg.RootDir, "cache")
+	cfg.QAReportFile = filepath.Join(cfg.RootDir, "qa-report.json")
+	cfg.QAUpdateReportFile = filepath.Join(cfg.RootDir, "qa-update-report.json")
 	return cfg
 }
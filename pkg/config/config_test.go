@@ -11,21 +11,31 @@ import (
 func TestDefaultConfig(t *testing.T) {
 	assert := assert.New(t)
 	test := config.Config{
-		BHLDumpURL:  "http://opendata.globalnames.org/bhlnames/bhl-data.zip",
-		BHLNamesURL: "http://opendata.globalnames.org/bhlnames/names.zip",
-		CoLDataURL:  "http://opendata.globalnames.org/bhlnames/col.zip",
-		RootDir:     config.RootDir(),
-		DbHost:      "0.0.0.0",
-		DbUser:      "postgres",
-		DbPass:      "postgres",
-		DbDatabase:  "bhlnames",
-		JobsNum:     4,
-		PortREST:    8888,
+		BHLDumpURL:     "http://opendata.globalnames.org/bhlnames/bhl-data.zip",
+		BHLNamesURL:    "http://opendata.globalnames.org/bhlnames/names.zip",
+		CacheType:      "none",
+		CacheSize:      10_000,
+		CacheTTL:       86_400,
+		CoLDataURL:     "http://opendata.globalnames.org/bhlnames/col.zip",
+		RootDir:        config.RootDir(),
+		DbHost:         "0.0.0.0",
+		DbPort:         5432,
+		DbSSLMode:      "disable",
+		DbUser:         "postgres",
+		DbPass:         "postgres",
+		DbDatabase:     "bhlnames",
+		DbQueryTimeout: 120,
+		JobsNum:        4,
+		MaxBatchSize:   5000,
+		PortREST:       8888,
 	}
 	test.DownloadBHLFile = filepath.Join(test.RootDir, "bhl-data.zip")
 	test.DownloadNamesFile = filepath.Join(test.RootDir, "bhlindex-latest.zip")
 	test.DownloadCoLFile = filepath.Join(test.RootDir, "col.zip")
 	test.ExtractDir = filepath.Join(test.RootDir, "Data")
+	test.CacheDir = filepath.Join(test.RootDir, "cache")
+	test.QAReportFile = filepath.Join(test.RootDir, "qa-report.json")
+	test.QAUpdateReportFile = filepath.Join(test.RootDir, "qa-update-report.json")
 
 	cfg := config.New()
 	assert.Equal(test, cfg)
@@ -34,24 +44,41 @@ func TestDefaultConfig(t *testing.T) {
 func TestModifiedConfig(t *testing.T) {
 	assert := assert.New(t)
 	test := config.Config{
-		BHLDumpURL:      "https://example.org",
-		BHLNamesURL:     "https://example.org",
-		CoLDataURL:      "https://example.org",
-		RootDir:         "/tmp",
-		DbHost:          "10.0.0.10",
-		DbUser:          "john",
-		DbPass:          "doe",
-		DbDatabase:      "bhl",
-		JobsNum:         100,
-		PortREST:        80,
-		WithRebuild:     true,
-		WithCoLDataTrim: true,
+		BHLDumpURL:         "https://example.org",
+		BHLDumpSHA256:      "abc",
+		BHLNamesURL:        "https://example.org",
+		CacheType:          "disk",
+		CacheSize:          5,
+		CacheTTL:           60,
+		CoLDataURL:         "https://example.org",
+		RootDir:            "/tmp",
+		DbHost:             "10.0.0.10",
+		DbPort:             6432,
+		DbSSLMode:          "verify-full",
+		DbSSLRootCert:      "/tmp/root.crt",
+		DbDSN:              "postgres://localhost/bhl",
+		DbMaxConns:         20,
+		DbMinConns:         2,
+		DbStatementTimeout: 60,
+		DbUser:             "john",
+		DbPass:             "doe",
+		DbDatabase:         "bhl",
+		DbQueryTimeout:     30,
+		JobsNum:            100,
+		MaxBatchSize:       10,
+		PortREST:           80,
+		QAThresholds:       map[string]float64{"items_without_pages": 1},
+		WithRebuild:        true,
+		WithCoLDataTrim:    true,
 	}
 
 	test.DownloadBHLFile = filepath.Join(test.RootDir, "bhl-data.zip")
 	test.DownloadNamesFile = filepath.Join(test.RootDir, "bhlindex-latest.zip")
 	test.DownloadCoLFile = filepath.Join(test.RootDir, "col.zip")
 	test.ExtractDir = filepath.Join(test.RootDir, "Data")
+	test.CacheDir = filepath.Join(test.RootDir, "cache")
+	test.QAReportFile = filepath.Join(test.RootDir, "qa-report.json")
+	test.QAUpdateReportFile = filepath.Join(test.RootDir, "qa-update-report.json")
 	cfg := modConfig()
 	assert.Equal(test, cfg)
 }
@@ -59,15 +86,29 @@ func TestModifiedConfig(t *testing.T) {
 func modConfig() config.Config {
 	opts := []config.Option{
 		config.OptBHLDumpURL("https://example.org"),
+		config.OptBHLDumpSHA256("abc"),
 		config.OptBHLNamesURL("https://example.org"),
+		config.OptCacheType("disk"),
+		config.OptCacheSize(5),
+		config.OptCacheTTL(60),
 		config.OptCoLDataURL("https://example.org"),
 		config.OptRootDir("/tmp"),
 		config.OptDbHost("10.0.0.10"),
+		config.OptDbPort(6432),
+		config.OptDbSSLMode("verify-full"),
+		config.OptDbSSLRootCert("/tmp/root.crt"),
+		config.OptDbDSN("postgres://localhost/bhl"),
+		config.OptDbMaxConns(20),
+		config.OptDbMinConns(2),
+		config.OptDbStatementTimeout(60),
 		config.OptDbUser("john"),
 		config.OptDbPass("doe"),
 		config.OptDbDatabase("bhl"),
+		config.OptDbQueryTimeout(30),
 		config.OptJobsNum(100),
+		config.OptMaxBatchSize(10),
 		config.OptPortREST(80),
+		config.OptQAThresholds(map[string]float64{"items_without_pages": 1}),
 		config.OptWithRebuild(true),
 	}
 	return config.New(opts...)
@@ -19,14 +19,21 @@ func strOpts() []Option {
 	var res []Option
 
 	envToOpt := map[string]func(string) Option{
-		"BHL_NAMES_DUMP_URL":     OptBHLDumpURL,
-		"BHL_NAMES_URL":          OptBHLNamesURL,
-		"BHL_NAMES_COL_DATA_URL": OptCoLDataURL,
-		"BHL_NAMES_DB_DATABASE":  OptDbDatabase,
-		"BHL_NAMES_DB_HOST":      OptDbHost,
-		"BHL_NAMES_DB_USER":      OptDbUser,
-		"BHL_NAMES_DB_PASS":      OptDbPass,
-		"BHL_NAMES_ROOT_DIR":     OptRootDir,
+		"BHL_NAMES_DUMP_URL":         OptBHLDumpURL,
+		"BHL_NAMES_DUMP_SHA256":      OptBHLDumpSHA256,
+		"BHL_NAMES_CACHE_TYPE":       OptCacheType,
+		"BHL_NAMES_URL":              OptBHLNamesURL,
+		"BHL_NAMES_SHA256":           OptBHLNamesSHA256,
+		"BHL_NAMES_COL_DATA_URL":     OptCoLDataURL,
+		"BHL_NAMES_COL_DATA_SHA256":  OptCoLDataSHA256,
+		"BHL_NAMES_DB_DATABASE":      OptDbDatabase,
+		"BHL_NAMES_DB_DSN":           OptDbDSN,
+		"BHL_NAMES_DB_HOST":          OptDbHost,
+		"BHL_NAMES_DB_SSL_MODE":      OptDbSSLMode,
+		"BHL_NAMES_DB_SSL_ROOT_CERT": OptDbSSLRootCert,
+		"BHL_NAMES_DB_USER":          OptDbUser,
+		"BHL_NAMES_DB_PASS":          OptDbPass,
+		"BHL_NAMES_ROOT_DIR":         OptRootDir,
 	}
 
 	for envVar, optFunc := range envToOpt {
@@ -42,8 +49,16 @@ func strOpts() []Option {
 func intOpts() []Option {
 	var res []Option
 	envToOpt := map[string]func(int) Option{
-		"BHL_NAMES_JOBS_NUM":  OptJobsNum,
-		"BHL_NAMES_PORT_REST": OptPortREST,
+		"BHL_NAMES_CACHE_SIZE":           OptCacheSize,
+		"BHL_NAMES_CACHE_TTL":            OptCacheTTL,
+		"BHL_NAMES_DB_PORT":              OptDbPort,
+		"BHL_NAMES_DB_MAX_CONNS":         OptDbMaxConns,
+		"BHL_NAMES_DB_MIN_CONNS":         OptDbMinConns,
+		"BHL_NAMES_DB_QUERY_TIMEOUT":     OptDbQueryTimeout,
+		"BHL_NAMES_DB_STATEMENT_TIMEOUT": OptDbStatementTimeout,
+		"BHL_NAMES_JOBS_NUM":             OptJobsNum,
+		"BHL_NAMES_MAX_BATCH_SIZE":       OptMaxBatchSize,
+		"BHL_NAMES_PORT_REST":            OptPortREST,
 	}
 	for envVar, optFunc := range envToOpt {
 		if envVar == "" {
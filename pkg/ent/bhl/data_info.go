@@ -0,0 +1,59 @@
+package bhl
+
+import "time"
+
+// @Description DataInfo describes the version of the data: datasets
+// @Description they were built from and builds that created or changed them.
+// @Description It allows to cite the exact data behind a result.
+type DataInfo struct {
+	// Version of bhlnames that serves the data.
+	Version string `json:"version" example:"v0.2.6"`
+
+	// Sources are datasets the data were built from.
+	Sources []*DataSource `json:"sources"`
+
+	// Builds are the latest builds, updates and CoL imports, the newest
+	// build goes first.
+	Builds []*Build `json:"builds"`
+}
+
+// @Description DataSource describes a dataset the data were built from.
+type DataSource struct {
+	// Name of the dataset: "bhl" for the BHL dump, "bhlindex" for names
+	// found in BHL, "col" for the Catalogue of Life data.
+	Name string `json:"name" example:"bhl"`
+
+	// URL is the source of the dataset.
+	URL string `json:"url" example:"http://opendata.globalnames.org/bhlnames/bhl-data.zip"`
+
+	// FileSize is the size of the downloaded archive in bytes.
+	FileSize int64 `json:"fileSize" example:"2147483648"`
+
+	// SHA256 is the checksum of the downloaded archive.
+	SHA256 string `json:"sha256" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+
+	// DumpDate is the modification time of the newest file in the archive.
+	DumpDate time.Time `json:"dumpDate" example:"2024-11-30T10:00:00Z"`
+
+	// ImportedAt is the time when the dataset was imported.
+	ImportedAt time.Time `json:"importedAt" example:"2024-12-02T12:00:00Z"`
+}
+
+// @Description Build describes a full build, an update or an import of
+// @Description CoL data.
+type Build struct {
+	// Kind of the build: "init", "update" or "col".
+	Kind string `json:"kind" example:"init"`
+
+	// Version of bhlnames that made the build.
+	Version string `json:"version" example:"v0.2.6"`
+
+	// StartedAt is the time when the build started.
+	StartedAt time.Time `json:"startedAt" example:"2024-12-02T10:00:00Z"`
+
+	// Duration of the build in seconds.
+	Duration int `json:"durationSec" example:"5400"`
+
+	// TableRows are numbers of rows in database tables after the build.
+	TableRows map[string]int64 `json:"tableRows"`
+}
@@ -1,6 +1,6 @@
 package bhl
 
-import "github.com/gnames/bhlnames/internal/ent/input"
+import "github.com/gnames/bhlnames/pkg/ent/input"
 
 // @Description RefsByName provides references to BHL Items, Parts and Pages
 // @Description where a name-string, taxon or a putative nomenclatural
@@ -22,6 +22,11 @@ type Meta struct {
 	// a pre-cached data.
 	NomenEventFromCache bool `json:"nomenEventFromCache,omitempty"`
 
+	// NomenEventLive indicates that the name was not found in the
+	// pre-cached data and its nomenclatural event was calculated
+	// from occurrences of the name and its variants.
+	NomenEventLive bool `json:"nomenEventLive,omitempty"`
+
 	// InputReferenceFrom indicates that input references were taked from
 	// a data source.
 	InputReferenceFrom string `json:"inputReferenceFrom,omitempty"`
@@ -36,9 +41,16 @@ type Meta struct {
 	// Synonyms is a list of synonyms for the name-string.
 	Synonyms []string `json:"synonyms,omitempty"`
 
-	// Error in results
-	Error error `json:"error,omitempty"`
+	// Error is a message of an error that happened during the search.
+	Error string `json:"error,omitempty" example:"database is unavailable"`
 
 	// ReferenceNumber is the number of references found for the name-string.
 	ReferenceNumber int `json:"totalRefsNum,omitempty"`
+
+	// SortOrder is the order of references in the results.
+	SortOrder input.SortOrder `json:"sortOrder,omitempty" example:"year"`
+
+	// NextOffset is the offset of the next page of references. It is
+	// empty if there are no more references.
+	NextOffset int `json:"nextOffset,omitempty" example:"20"`
 }
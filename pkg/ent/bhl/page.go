@@ -0,0 +1,151 @@
+package bhl
+
+// @Description PageNames provides all scientific names detected on a BHL
+// @Description page.
+type PageNames struct {
+	// PageID is the BHL database ID for the page.
+	PageID int `json:"pageId" example:"12345"`
+
+	// ItemID is the BHL database ID for the Item of the page.
+	ItemID int `json:"itemId" example:"12345"`
+
+	// PageNum is the page number provided by the hard copy of the publication.
+	PageNum int `json:"pageNum,omitempty" example:"123"`
+
+	// URL is the URL of the page in BHL.
+	URL string `json:"url,omitempty" example:"https://www.biodiversitylibrary.org/page/12345"`
+
+	// NamesNum is the number of name occurrences on the page.
+	NamesNum int `json:"namesNum" example:"12"`
+
+	// Names are the name occurrences on the page, sorted by their offsets.
+	Names []*PageName `json:"names"`
+}
+
+// @Description PageName is an occurrence of a scientific name on a BHL page.
+type PageName struct {
+	// Name is the name-string as it was detected in the text.
+	Name string `json:"name" example:"Pardosa moesta Banks, 1892"`
+
+	// OffsetStart is the starting position of the name in the page text.
+	// It is calculated using UTF-8 characters.
+	OffsetStart int `json:"offsetStart" example:"1024"`
+
+	// OffsetEnd is the ending position of the name in the page text.
+	// It is calculated using UTF-8 characters.
+	OffsetEnd int `json:"offsetEnd" example:"1050"`
+
+	// OddsLog10 is a logarithm with base 10 of odds that the detected
+	// string is a scientific name.
+	OddsLog10 float64 `json:"oddsLog10,omitempty" example:"5.3"`
+
+	// AnnotNomen is a nomenclatural annotation located near the name.
+	AnnotNomen string `json:"annotNomen,omitempty" example:"SP_NOV"`
+
+	// MatchType is the type of the match of the name to the Catalogue
+	// of Life, for example `Exact` or `Fuzzy`.
+	MatchType string `json:"matchType,omitempty" example:"Exact"`
+
+	// EditDistance is the number of differences between the name and the
+	// matched name for fuzzy matches.
+	EditDistance int `json:"editDistance,omitempty" example:"0"`
+
+	// MatchedName is the name from the Catalogue of Life that matched the
+	// detected name.
+	MatchedName string `json:"matchedName,omitempty" example:"Pardosa moesta Banks, 1892"`
+
+	// MatchedCanonical is the canonical form of the matched name.
+	MatchedCanonical string `json:"matchedCanonical,omitempty" example:"Pardosa moesta"`
+
+	// CurrentName is the currently accepted name for the taxon of the
+	// matched name.
+	CurrentName string `json:"currentName,omitempty" example:"Pardosa moesta Banks, 1892"`
+
+	// CurrentCanonical is the canonical form of the current name.
+	CurrentCanonical string `json:"currentCanonical,omitempty" example:"Pardosa moesta"`
+
+	// Classification is the pipe-delimited classification of the taxon.
+	Classification string `json:"classification,omitempty" example:"Animalia|Arthropoda|Arachnida|Araneae|Lycosidae|Pardosa|Pardosa moesta"`
+
+	// ClassificationRanks are the pipe-delimited ranks of the
+	// classification.
+	ClassificationRanks string `json:"classificationRanks,omitempty" example:"kingdom|phylum|class|order|family|genus|species"`
+
+	// ClassificationIDs are the pipe-delimited IDs of the classification
+	// in the data source.
+	ClassificationIDs string `json:"classificationIds,omitempty" example:"N|CH2|6Z4|9JQ|623|64N|6V7VB"`
+
+	// DataSourceID is the ID of the data source of the match.
+	DataSourceID int `json:"dataSourceId,omitempty" example:"1"`
+
+	// DataSourceTitle is the title of the data source of the match.
+	DataSourceTitle string `json:"dataSourceTitle,omitempty" example:"Catalogue of Life"`
+}
+
+// @Description NameList provides unique scientific names detected in a
+// @Description BHL item or part.
+type NameList struct {
+	// ItemID is the BHL database ID for the Item, it is empty for a part.
+	ItemID int `json:"itemId,omitempty" example:"73397"`
+
+	// PartID is the BHL database ID for the Part, it is empty for an item.
+	PartID int `json:"partId,omitempty" example:"39371"`
+
+	// NamesNum is the number of unique names.
+	NamesNum int `json:"namesNum" example:"120"`
+
+	// OccurrencesNum is the number of all occurrences of the names.
+	OccurrencesNum int `json:"occurrencesNum" example:"431"`
+
+	// Kingdoms is the number of unique names for each kingdom, according
+	// to the classification of the names.
+	Kingdoms map[string]int `json:"kingdoms,omitempty"`
+
+	// Names are the unique names in the order of their first appearance.
+	Names []*NameStats `json:"names"`
+}
+
+// @Description NameStats provides data about a unique name detected in
+// @Description a BHL item or part.
+type NameStats struct {
+	// Name is the name-string as it was detected in the text.
+	Name string `json:"name" example:"Pardosa moesta Banks, 1892"`
+
+	// OccurrencesNum is the number of times the name was detected.
+	OccurrencesNum int `json:"occurrencesNum" example:"3"`
+
+	// FirstPageID is the BHL ID of the first page where the name was found.
+	FirstPageID int `json:"firstPageId" example:"6589171"`
+
+	// LastPageID is the BHL ID of the last page where the name was found.
+	LastPageID int `json:"lastPageId" example:"6589175"`
+
+	// AnnotNomen are nomenclatural annotations found near the name
+	// occurrences, for example `SP_NOV`.
+	AnnotNomen []string `json:"annotNomen,omitempty" example:"SP_NOV"`
+
+	// MatchType is the type of the match of the name to the Catalogue
+	// of Life, for example `Exact` or `Fuzzy`.
+	MatchType string `json:"matchType,omitempty" example:"Exact"`
+
+	// MatchedCanonical is the canonical form of the matched name.
+	MatchedCanonical string `json:"matchedCanonical,omitempty" example:"Pardosa moesta"`
+
+	// CurrentCanonical is the canonical form of the currently accepted
+	// name.
+	CurrentCanonical string `json:"currentCanonical,omitempty" example:"Pardosa moesta"`
+
+	// Classification is the pipe-delimited classification of the taxon.
+	Classification string `json:"classification,omitempty" example:"Animalia|Arthropoda|Arachnida|Araneae|Lycosidae|Pardosa|Pardosa moesta"`
+
+	// ClassificationRanks are the pipe-delimited ranks of the
+	// classification.
+	ClassificationRanks string `json:"classificationRanks,omitempty" example:"kingdom|phylum|class|order|family|genus|species"`
+
+	// ClassificationIDs are the pipe-delimited IDs of the classification
+	// in the data source.
+	ClassificationIDs string `json:"classificationIds,omitempty" example:"N|CH2|6Z4|9JQ|623|64N|6V7VB"`
+
+	// DataSourceID is the ID of the data source of the match.
+	DataSourceID int `json:"dataSourceId,omitempty" example:"1"`
+}
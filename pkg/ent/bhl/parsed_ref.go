@@ -0,0 +1,54 @@
+package bhl
+
+// @Description ParsedRef shows data extracted from a reference string and
+// @Description BHL titles that match the reference. It helps to find out
+// @Description why a reference did or did not resolve.
+type ParsedRef struct {
+	// RefString is the reference string as it was given.
+	RefString string `json:"refString" example:"Docums Mycol. 34(nos 135-136):50-51. (2008)."`
+
+	// YearStart is the year of publication found in the reference.
+	YearStart int `json:"yearStart,omitempty" example:"2008"`
+
+	// YearEnd is the last year of a range of years found in the reference.
+	YearEnd int `json:"yearEnd,omitempty" example:"2009"`
+
+	// Volume is the volume found in the reference.
+	Volume int `json:"volume,omitempty" example:"34"`
+
+	// PageStart is the first page found in the reference.
+	PageStart int `json:"pageStart,omitempty" example:"50"`
+
+	// PageEnd is the last page found in the reference.
+	PageEnd int `json:"pageEnd,omitempty" example:"51"`
+
+	// RefTitles shows how the reference was matched to BHL titles. It is
+	// empty if titles matching is not available.
+	RefTitles
+}
+
+// @Description RefTitles shows how a reference string was matched to BHL
+// @Description titles using abbreviations.
+type RefTitles struct {
+	// Abbr is the abbreviation of the reference string made of the first
+	// letters of its words.
+	Abbr string `json:"abbr,omitempty" example:"pdmmn"`
+
+	// AbbrMatches are abbreviations of BHL titles found inside Abbr.
+	AbbrMatches []string `json:"abbrMatches,omitempty" example:"dmm"`
+
+	// Titles are BHL titles that have abbreviations from AbbrMatches.
+	Titles []*TitleMatch `json:"titles,omitempty"`
+}
+
+// @Description TitleMatch is a BHL title matched to a reference string.
+type TitleMatch struct {
+	// TitleID is the BHL database ID of the title.
+	TitleID int `json:"titleId" example:"7928"`
+
+	// TitleName is the name of the title.
+	TitleName string `json:"titleName" example:"Documents mycologiques"`
+
+	// Abbrs are the abbreviations of the title that matched the reference.
+	Abbrs []string `json:"abbrs" example:"dm"`
+}
@@ -2,6 +2,7 @@ package bhl
 
 import (
 	bout "github.com/gnames/bayes/ent/output"
+	"github.com/gnames/bhlnames/pkg/ent/input"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
@@ -47,6 +48,14 @@ type NameData struct {
 	// between Name and MatchName according to Levenshtein algorithm.
 	EditDistance int `json:"editDistance,omitempty" example:"0"`
 
+	// NameMatch is the kind of match between the name from the query and
+	// MatchedName: `exact`, `stem` or `fuzzy`.
+	NameMatch input.NameMatch `json:"nameMatch,omitempty" example:"exact"`
+
+	// QueryEditDistance is the number of edits between the name from the
+	// query and MatchedName for the `fuzzy` match.
+	QueryEditDistance int `json:"queryEditDistance,omitempty" example:"1"`
+
 	// AnnotNomen is a nomenclatural annotation located near the matchted name.
 	AnnotNomen string `json:"annotNomen,omitempty" example:"sp. nov."`
 }
@@ -251,6 +260,27 @@ type ItemStats struct {
 
 	// UniqNamesNum is the number of unique names in the Item.
 	UniqNamesNum int `json:"uniqNamesNum" example:"1234"`
+
+	// Taxa is the taxonomic distribution of the names of the Item from
+	// kingdom to genus. It is sorted by rank, and then by the number of
+	// names. It is only provided by ItemStats methods.
+	Taxa []*ItemTaxon `json:"taxa,omitempty"`
+}
+
+// @Description ItemTaxon shows how many names of an Item belong to a taxon.
+type ItemTaxon struct {
+	// Rank is the rank of the taxon.
+	Rank string `json:"rank" example:"order"`
+
+	// Taxon is the name of the taxon.
+	Taxon string `json:"taxon" example:"Coleoptera"`
+
+	// NamesNum is the number of names of the Item that belong to the taxon.
+	NamesNum int `json:"namesNum" example:"120"`
+
+	// Percent is the percentage of these names among names used for
+	// statistics of the Item.
+	Percent int `json:"percent" example:"14"`
 }
 
 // @Description Score provides a qualitative estimation of a match quality
@@ -0,0 +1,50 @@
+package bhl
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/gnames/bhlnames/pkg/ent/input"
+)
+
+// SortReferences sorts references in the given order. Ties are broken by
+// odds, year and page ID, so the order is always the same for the same
+// data.
+func SortReferences(refs []*ReferenceName, order input.SortOrder) {
+	slices.SortStableFunc(refs, func(a, b *ReferenceName) int {
+		var res int
+		switch order {
+		case input.SortYearDesc:
+			res = cmp.Compare(b.YearAggr, a.YearAggr)
+		case input.SortOdds:
+			res = cmp.Compare(b.odds(), a.odds())
+		case input.SortTitle:
+			res = cmp.Compare(
+				strings.ToLower(a.TitleName), strings.ToLower(b.TitleName),
+			)
+		case input.SortPage:
+			res = cmp.Or(
+				cmp.Compare(a.ItemID, b.ItemID),
+				cmp.Compare(a.PageID, b.PageID),
+			)
+		default:
+			res = cmp.Compare(a.YearAggr, b.YearAggr)
+		}
+
+		return cmp.Or(
+			res,
+			cmp.Compare(b.odds(), a.odds()),
+			cmp.Compare(a.YearAggr, b.YearAggr),
+			cmp.Compare(a.PageID, b.PageID),
+		)
+	})
+}
+
+// odds returns the odds of the reference or 0 if it was not scored.
+func (rn *ReferenceName) odds() float64 {
+	if rn.Score == nil {
+		return 0
+	}
+	return rn.Odds
+}
@@ -0,0 +1,66 @@
+package bhl_test
+
+import (
+	"testing"
+
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/input"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortReferences(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		msg   string
+		order input.SortOrder
+		pages []int
+	}{
+		{"year", input.SortYear, []int{4, 3, 1, 2}},
+		{"year desc", input.SortYearDesc, []int{2, 3, 1, 4}},
+		{"odds", input.SortOdds, []int{3, 4, 1, 2}},
+		{"title", input.SortTitle, []int{1, 2, 3, 4}},
+		{"page", input.SortPage, []int{3, 4, 1, 2}},
+		{"default", "", []int{4, 3, 1, 2}},
+	}
+
+	for _, v := range tests {
+		refs := []*bhl.ReferenceName{
+			ref(1, 20, 1900, "Annals", 0.5),
+			ref(2, 20, 1950, "bulletin", 0.1),
+			ref(3, 10, 1900, "Cell", 5.0),
+			ref(4, 10, 1850, "Zoology", 2.0),
+		}
+		bhl.SortReferences(refs, v.order)
+		pages := make([]int, len(refs))
+		for i := range refs {
+			pages[i] = refs[i].PageID
+		}
+		assert.Equal(v.pages, pages, v.msg)
+	}
+}
+
+func TestSortNoScore(t *testing.T) {
+	assert := assert.New(t)
+	refs := []*bhl.ReferenceName{
+		{Reference: bhl.Reference{PageID: 2, YearAggr: 1900}},
+		{Reference: bhl.Reference{PageID: 1, YearAggr: 1900}},
+	}
+	bhl.SortReferences(refs, input.SortOdds)
+	assert.Equal(1, refs[0].PageID)
+}
+
+func ref(
+	pageID, itemID, year int,
+	title string,
+	odds float64,
+) *bhl.ReferenceName {
+	return &bhl.ReferenceName{
+		Reference: bhl.Reference{
+			PageID:    pageID,
+			ItemID:    itemID,
+			YearAggr:  year,
+			TitleName: title,
+		},
+		Score: &bhl.Score{Odds: odds},
+	}
+}
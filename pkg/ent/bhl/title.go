@@ -0,0 +1,87 @@
+package bhl
+
+import "github.com/jackc/pgx/v5/pgtype"
+
+// @Description Title represents a BHL title, usually a journal or a book,
+// @Description with the aggregated taxonomic profile of its items.
+type Title struct {
+	// TitleID is the BHL database ID for the Title.
+	TitleID int `json:"titleId" example:"7928"`
+
+	// TitleName is the name of the title.
+	TitleName string `json:"titleName" example:"Bulletin of the American Museum of Natural History"`
+
+	// TitleDOI provides DOI for the title.
+	TitleDOI string `json:"doiTitle,omitempty" example:"10.1234/5678"`
+
+	// TitleYearStart is the year the when book is published, or
+	// a journal started publication.
+	TitleYearStart *pgtype.Int4 `json:"titleYearStart,omitempty" swaggertype:"integer" example:"1881"`
+
+	// TitleYearEnd is the year when the journal ceased publication.
+	TitleYearEnd *pgtype.Int4 `json:"titleYearEnd,omitempty" swaggertype:"integer" example:"1922"`
+
+	// Language is the language of the title.
+	Language string `json:"language,omitempty" example:"English"`
+
+	// ItemsNum is the number of items (volumes) of the title in BHL.
+	ItemsNum int `json:"itemsNum" example:"120"`
+
+	// ItemsYearStart is the earliest year of the title's items.
+	ItemsYearStart *pgtype.Int4 `json:"itemsYearStart,omitempty" swaggertype:"integer" example:"1881"`
+
+	// ItemsYearEnd is the latest year of the title's items.
+	ItemsYearEnd *pgtype.Int4 `json:"itemsYearEnd,omitempty" swaggertype:"integer" example:"1921"`
+
+	// Profile is the taxonomic profile of the title aggregated from the
+	// statistics of its items.
+	Profile TitleProfile `json:"profile"`
+}
+
+// @Description TitleProfile aggregates taxonomic statistics of the items
+// @Description of a title.
+type TitleProfile struct {
+	// NamesNum is the sum of numbers of unique names of the items.
+	NamesNum int `json:"namesNum" example:"23456"`
+
+	// AnimaliaNum is the number of names that belong to the Animalia kingdom.
+	AnimaliaNum int `json:"kingdomAnimaliaNum" example:"1234"`
+
+	// PlantaeNum is the number of names that belong to the Plantae kingdom.
+	PlantaeNum int `json:"kingdomPlantaeNum" example:"1234"`
+
+	// FungiNum is the number of names that belong to the Fungi kingdom.
+	FungiNum int `json:"kingdomFungiNum" example:"1234"`
+
+	// BacteriaNum is the number of names that belong to the Bacteria kingdom.
+	BacteriaNum int `json:"kingdomBacteriaNum" example:"1234"`
+
+	// MainKingdom is the most prevalent kingdom in the title.
+	MainKingdom string `json:"mainKingdom,omitempty" example:"Animalia"`
+
+	// MainKingdomPercent is the percentage of names of the main kingdom
+	// among names of the four kingdoms.
+	MainKingdomPercent int `json:"mainKingdomPercent,omitempty" example:"87"`
+
+	// MainTaxa are the most frequent main taxa of the items for each rank
+	// from phylum to genus.
+	MainTaxa []*TaxonItems `json:"mainTaxa,omitempty"`
+}
+
+// @Description TaxonItems shows how many items of a title have the taxon
+// @Description as the most prevalent one at its rank.
+type TaxonItems struct {
+	// Rank is the rank of the taxon.
+	Rank string `json:"rank" example:"order"`
+
+	// Taxon is the name of the taxon.
+	Taxon string `json:"taxon" example:"Coleoptera"`
+
+	// ItemsNum is the number of items where the taxon is the most
+	// prevalent at its rank.
+	ItemsNum int `json:"itemsNum" example:"42"`
+
+	// ItemsPercent is the percentage of such items among all items of
+	// the title.
+	ItemsPercent int `json:"itemsPercent" example:"35"`
+}
@@ -0,0 +1,42 @@
+package builder
+
+type Builder interface {
+	// ResetData removes all downloaded and generated resources and
+	// unfinished builds. The data used by readers stays until a new build
+	// replaces it.
+	ResetData() error
+
+	// ImportData downloads remote datasets to the local file system and
+	// generates data needed for bhlnames functionality. The data goes to a
+	// new staging build that is not visible to readers.
+	ImportData() error
+
+	// CalculateTxStats calculates taxonomic statistics for each Item.
+	CalculateTxStats() error
+
+	// CheckData runs data quality checks on the new build and writes the
+	// report to a JSON file. It returns an error if a check exceeds its
+	// threshold, so the build is not published.
+	CheckData() (QAReport, error)
+
+	// UpdateData downloads the newest datasets, compares their records with
+	// hashes saved by the previous build, imports only changed records and
+	// applies added, updated and deleted records to the current data.
+	// Changed records are checked for anomalies without thresholds. The
+	// datasets and the update are recorded in the provenance tables.
+	// Taxonomic statistics and abbreviations are recalculated only for
+	// changed items and titles. The returned report describes the changes.
+	UpdateData() (Report, error)
+
+	// PublishData carries over CoL data and the history of builds to the new
+	// build, validates it, records its datasets and numbers of rows, and
+	// atomically makes it the build used by readers. The previous build is
+	// kept for a rollback.
+	PublishData() error
+
+	// Rollback makes the previous build the one used by readers again.
+	Rollback() error
+
+	// Close closes all resources used by the Builder.
+	Close()
+}
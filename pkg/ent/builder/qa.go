@@ -0,0 +1,46 @@
+package builder
+
+import "time"
+
+// QAReport describes anomalies found in the imported data. Importers skip
+// or leave empty fields they cannot parse, the report shows how often it
+// happened.
+type QAReport struct {
+	// CreatedAt is the time when the report was made.
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Failed is true if at least one check exceeded its threshold.
+	Failed bool `json:"failed"`
+
+	// Checks are results of the data quality checks.
+	Checks []QACheck `json:"checks"`
+}
+
+// QACheck is the result of one data quality check.
+type QACheck struct {
+	// Name of the check, it is used for the check's threshold in the
+	// configuration, for example "items_without_pages".
+	Name string `json:"name"`
+
+	// Description explains what is checked.
+	Description string `json:"description"`
+
+	// Total is the number of checked records.
+	Total int `json:"total"`
+
+	// Anomalies is the number of records that failed the check.
+	Anomalies int `json:"anomalies"`
+
+	// Percent is the percentage of anomalies among the checked records.
+	Percent float64 `json:"percent"`
+
+	// Threshold is the maximum allowed percentage of anomalies. The check
+	// cannot fail without a threshold.
+	Threshold *float64 `json:"threshold,omitempty"`
+
+	// Failed is true if Percent is higher than Threshold.
+	Failed bool `json:"failed"`
+
+	// Samples contain IDs of some records that failed the check.
+	Samples []string `json:"samples,omitempty"`
+}
@@ -0,0 +1,41 @@
+package builder
+
+// Report describes changes made by an incremental update.
+type Report struct {
+	// Items are changes of BHL items. An item is updated if its metadata,
+	// pages or names' occurrences changed.
+	Items Change `json:"items"`
+
+	// Parts are changes of BHL parts (scientific papers, chapters etc).
+	Parts Change `json:"parts"`
+
+	// Names are changes of name-strings verified by bhlindex.
+	Names Change `json:"names"`
+
+	// Pages are rows of pages that were added or removed. Pages of an
+	// updated item are removed and added again.
+	Pages Change `json:"pages"`
+
+	// Occurrences are rows of names' occurrences that were added or
+	// removed. Occurrences of an updated item are removed and added again.
+	Occurrences Change `json:"occurrences"`
+
+	// ItemStats is the number of items with recalculated taxonomic
+	// statistics.
+	ItemStats int `json:"itemStats"`
+
+	// Titles is the number of titles with recalculated abbreviations.
+	Titles int `json:"titles"`
+}
+
+// Change contains numbers of added, updated and deleted records.
+type Change struct {
+	Added   int `json:"added"`
+	Updated int `json:"updated,omitempty"`
+	Deleted int `json:"deleted"`
+}
+
+// IsEmpty is true if nothing changed.
+func (r Report) IsEmpty() bool {
+	return r.Items == Change{} && r.Parts == Change{} && r.Names == Change{}
+}
@@ -0,0 +1,34 @@
+package cache
+
+// Cache stores encoded results of name-reference searches, so popular
+// queries do not hit the database every time.
+type Cache interface {
+	// Get returns a cached value for a key. The second value is false if
+	// the key is not in the cache or its value expired.
+	Get(key string) ([]byte, bool)
+
+	// Set saves a value for a key.
+	Set(key string, val []byte) error
+
+	// Clear removes all values from the cache. It is used when BHLnames
+	// data are rebuilt.
+	Clear() error
+
+	// Stats returns the number of cache hits and misses.
+	Stats() Stats
+
+	// Close releases resources used by the cache.
+	Close() error
+}
+
+// @Description Stats provides usage statistics of the results cache.
+type Stats struct {
+	// Type is the type of the cache (memory or disk).
+	Type string `json:"type" example:"memory"`
+
+	// Hits is the number of requests answered from the cache.
+	Hits uint64 `json:"hits" example:"1024"`
+
+	// Misses is the number of requests that were not found in the cache.
+	Misses uint64 `json:"misses" example:"256"`
+}
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"github.com/gnames/bhlnames/pkg/ent/input"
+)
+
+// Key creates a cache key from the input. The key is based on the
+// canonical form of the name, the reference data and the parameters that
+// change the result. The ID and other fields that do not influence the
+// search are ignored, so the same query from different clients gets the
+// same key.
+func Key(inp input.Input) string {
+	name := inp.CanonicalSimple
+	if name == "" {
+		name = inp.NameString
+	}
+
+	fields := []string{
+		normalize(name),
+		strconv.Itoa(inp.NameYear),
+	}
+
+	if ref := inp.Reference; ref != nil {
+		fields = append(fields,
+			normalize(ref.RefString),
+			strconv.Itoa(ref.RefYearStart),
+			strconv.Itoa(ref.RefYearEnd),
+			strconv.Itoa(ref.Volume),
+			strconv.Itoa(ref.PageStart),
+			strconv.Itoa(ref.PageEnd),
+		)
+	} else {
+		fields = append(fields, "")
+	}
+
+	fields = append(fields,
+		strconv.Itoa(inp.RefsLimit),
+		strconv.Itoa(inp.Offset),
+		strconv.FormatBool(inp.SortDesc),
+		string(inp.SortBy),
+		strconv.FormatBool(inp.WithNomenEvent),
+		strconv.FormatBool(inp.WithShortenedOutput),
+		strconv.FormatBool(inp.WithTaxon),
+		string(inp.NameMatch),
+		strconv.Itoa(inp.EditDistance()),
+		strconv.Itoa(inp.YearFrom),
+		strconv.Itoa(inp.YearTo),
+		strconv.Itoa(inp.TitleID),
+		strconv.Itoa(inp.ItemID),
+		normalize(inp.MainKingdom),
+		normalize(inp.AnnotNomen),
+		strconv.Itoa(inp.MinRefMatchQuality),
+	)
+
+	sum := sha256.Sum256([]byte(strings.Join(fields, "|")))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalize removes extra spaces and makes the string lowercase.
+func normalize(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
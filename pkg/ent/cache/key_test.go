@@ -0,0 +1,47 @@
+package cache_test
+
+import (
+	"testing"
+
+	"github.com/gnames/bhlnames/pkg/ent/cache"
+	"github.com/gnames/bhlnames/pkg/ent/input"
+	"github.com/gnames/gnparser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKey(t *testing.T) {
+	assert := assert.New(t)
+	gnp := gnparser.NewPool(gnparser.NewConfig(), 1)
+	newInp := func(opts ...input.Option) input.Input {
+		return input.New(gnp, opts...)
+	}
+
+	base := cache.Key(newInp(input.OptNameString("Canis lupus")))
+	assert.Equal(64, len(base))
+
+	same := []input.Input{
+		newInp(input.OptNameString("Canis  lupus"), input.OptID("other")),
+		newInp(input.OptNameString("Canis lupus Linnaeus")),
+	}
+	for _, v := range same {
+		assert.Equal(base, cache.Key(v), v.NameString)
+	}
+
+	diff := []input.Input{
+		newInp(input.OptNameString("Canis lupus"), input.OptWithTaxon(true)),
+		newInp(input.OptNameString("Canis lupus"), input.OptWithNomenEvent(true)),
+		newInp(input.OptNameString("Canis lupus"), input.OptRefsLimit(1)),
+		newInp(input.OptNameString("Canis lupus"), input.OptSortDesc(true)),
+		newInp(input.OptNameString("Canis lupus Linnaeus, 1758")),
+		newInp(input.OptNameString("Canis lupus"), input.OptRefString("Syst. Nat. 1758")),
+		newInp(input.OptNameString("Canis lupus"), input.OptRefVolume(10)),
+		newInp(input.OptNameString("Canis latrans")),
+	}
+	keys := map[string]struct{}{base: {}}
+	for _, v := range diff {
+		k := cache.Key(v)
+		_, ok := keys[k]
+		assert.False(ok, v.NameString)
+		keys[k] = struct{}{}
+	}
+}
@@ -3,8 +3,8 @@ package col
 import (
 	"context"
 
-	"github.com/gnames/bhlnames/internal/ent/bhl"
-	"github.com/gnames/bhlnames/internal/ent/input"
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/input"
 )
 
 // Nomen provides methods for working with Catalogue of Life (CoL) data and
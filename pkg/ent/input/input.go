@@ -29,17 +29,32 @@ type Input struct {
 	Params `json:"params"`
 }
 
+// IsScored returns true if found references are scored, that is for
+// nomenclatural events or if a reference is provided.
+func (inp Input) IsScored() bool {
+	return inp.WithNomenEvent || inp.Reference != nil
+}
+
 // @Description Params contain options used in the search and output.
 type Params struct {
 	// RefsLimit provides the maximum number of references to return for each
 	// name.
 	RefsLimit int `json:"refsLimit,omitempty" example:"3"`
 
+	// Offset is the number of references to skip. Together with RefsLimit
+	// it allows to get results page by page.
+	Offset int `json:"offset,omitempty" example:"20"`
+
 	// SortDesc determines the order of sorting the output data. If `true`
 	// data are sorted by year from latest to earliest. If `false` then from
 	// earliest to latest.
 	SortDesc bool `json:"sortDesc,omitempty" example:"true"`
 
+	// SortBy sets the order of references: `year`, `year_desc`, `odds`,
+	// `title` or `page`. If it is empty, references are sorted by odds
+	// when they are scored, otherwise by year according to SortDesc.
+	SortBy SortOrder `json:"sortBy,omitempty" example:"year"`
+
 	// WithDetails is true when it is desirable to show more information in the
 	// output.
 	WithDetails bool `json:"showDetails,omitempty" example:"false"`
@@ -56,6 +71,54 @@ type Params struct {
 	// WithTaxon is true when result includes data from all names that point to
 	// a particular taxon, not only from the given name.
 	WithTaxon bool `json:"taxon,omitempty" example:"false"`
+
+	// NameMatch determines how the name is compared to names in BHL:
+	// `exact` (default), `stem` or `fuzzy`. It is ignored for taxon
+	// searches.
+	NameMatch NameMatch `json:"nameMatch,omitempty" example:"stem"`
+
+	// MaxEditDistance is the maximum number of edits for the `fuzzy` name
+	// match. The default is 1, the maximum is 3.
+	MaxEditDistance int `json:"maxEditDistance,omitempty" example:"1"`
+
+	// Filter contains criteria that limit found references.
+	Filter `json:"filter"`
+}
+
+// @Description Filter contains criteria that limit found references.
+// @Description Empty fields are ignored.
+type Filter struct {
+	// YearFrom removes references published before this year.
+	YearFrom int `json:"yearFrom,omitempty" example:"1890"`
+
+	// YearTo removes references published after this year.
+	YearTo int `json:"yearTo,omitempty" example:"1920"`
+
+	// TitleID keeps only references from a BHL title (book or journal).
+	TitleID int `json:"titleId,omitempty" example:"7928"`
+
+	// ItemID keeps only references from a BHL item (usually a volume).
+	ItemID int `json:"itemId,omitempty" example:"32506"`
+
+	// MainKingdom keeps only references from items where the given
+	// kingdom is the most prevalent one.
+	MainKingdom string `json:"mainKingdom,omitempty" example:"Animalia"`
+
+	// AnnotNomen keeps only references with a given nomenclatural
+	// annotation (for example `SP_NOV`). The `ANY` value keeps references
+	// with any annotation.
+	AnnotNomen string `json:"annotNomen,omitempty" example:"SP_NOV"`
+
+	// MinRefMatchQuality removes references with RefMatchQuality lower
+	// than the given value. It is used only when references are scored,
+	// that is for nomenclatural events or if a reference is provided,
+	// other inputs with this filter are rejected.
+	MinRefMatchQuality int `json:"minRefMatchQuality,omitempty" example:"3"`
+}
+
+// IsEmpty returns true if no filtering criteria are set.
+func (f Filter) IsEmpty() bool {
+	return f == Filter{}
 }
 
 // @Description Name provides data about a scientific name.
@@ -133,23 +196,128 @@ func OptNameYear(i int) Option {
 
 func OptRefString(s string) Option {
 	return func(inp *Input) {
-		inp.Reference = &Reference{}
+		if inp.Reference == nil {
+			inp.Reference = &Reference{}
+		}
 		inp.Reference.RefString = s
 	}
 }
 
+func OptRefYearStart(i int) Option {
+	return func(inp *Input) {
+		if inp.Reference == nil {
+			inp.Reference = &Reference{}
+		}
+		inp.RefYearStart = i
+	}
+}
+
+func OptRefYearEnd(i int) Option {
+	return func(inp *Input) {
+		if inp.Reference == nil {
+			inp.Reference = &Reference{}
+		}
+		inp.RefYearEnd = i
+	}
+}
+
+func OptRefVolume(i int) Option {
+	return func(inp *Input) {
+		if inp.Reference == nil {
+			inp.Reference = &Reference{}
+		}
+		inp.Volume = i
+	}
+}
+
+func OptRefPages(start, end int) Option {
+	return func(inp *Input) {
+		if inp.Reference == nil {
+			inp.Reference = &Reference{}
+		}
+		inp.PageStart = start
+		inp.PageEnd = end
+	}
+}
+
 func OptRefsLimit(i int) Option {
 	return func(cfg *Input) {
 		cfg.RefsLimit = i
 	}
 }
 
+func OptOffset(i int) Option {
+	return func(cfg *Input) {
+		cfg.Offset = i
+	}
+}
+
+func OptYearFrom(i int) Option {
+	return func(cfg *Input) {
+		cfg.YearFrom = i
+	}
+}
+
+func OptYearTo(i int) Option {
+	return func(cfg *Input) {
+		cfg.YearTo = i
+	}
+}
+
+func OptTitleID(i int) Option {
+	return func(cfg *Input) {
+		cfg.TitleID = i
+	}
+}
+
+func OptItemID(i int) Option {
+	return func(cfg *Input) {
+		cfg.ItemID = i
+	}
+}
+
+func OptMainKingdom(s string) Option {
+	return func(cfg *Input) {
+		cfg.MainKingdom = s
+	}
+}
+
+func OptAnnotNomen(s string) Option {
+	return func(cfg *Input) {
+		cfg.AnnotNomen = strings.ToUpper(s)
+	}
+}
+
+func OptMinRefMatchQuality(i int) Option {
+	return func(cfg *Input) {
+		cfg.MinRefMatchQuality = i
+	}
+}
+
 func OptSortDesc(b bool) Option {
 	return func(cfg *Input) {
 		cfg.SortDesc = b
 	}
 }
 
+func OptSortBy(so SortOrder) Option {
+	return func(cfg *Input) {
+		cfg.SortBy = so
+	}
+}
+
+func OptNameMatch(nm NameMatch) Option {
+	return func(cfg *Input) {
+		cfg.NameMatch = nm
+	}
+}
+
+func OptMaxEditDistance(i int) Option {
+	return func(cfg *Input) {
+		cfg.MaxEditDistance = i
+	}
+}
+
 func OptWithNomenEvent(b bool) Option {
 	return func(cfg *Input) {
 		cfg.WithNomenEvent = b
@@ -3,7 +3,7 @@ package input_test
 import (
 	"testing"
 
-	"github.com/gnames/bhlnames/internal/ent/input"
+	"github.com/gnames/bhlnames/pkg/ent/input"
 	"github.com/gnames/gnparser"
 	"github.com/stretchr/testify/assert"
 )
@@ -79,3 +79,50 @@ func TestNew(t *testing.T) {
 		assert.Equal(inp.CanonicalSimple, v.iCan, v.msg)
 	}
 }
+
+func TestOrder(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		msg      string
+		sortBy   input.SortOrder
+		desc     bool
+		isScored bool
+		res      input.SortOrder
+	}{
+		{"default", "", false, false, input.SortYear},
+		{"desc", "", true, false, input.SortYearDesc},
+		{"scored", "", true, true, input.SortOdds},
+		{"explicit", input.SortTitle, true, true, input.SortTitle},
+		{"explicit year", input.SortYear, false, true, input.SortYear},
+	}
+
+	for _, v := range tests {
+		prm := input.Params{SortBy: v.sortBy, SortDesc: v.desc}
+		assert.Equal(v.res, prm.Order(v.isScored), v.msg)
+	}
+
+	assert.True(input.SortOrder("").IsValid())
+	assert.True(input.SortPage.IsValid())
+	assert.False(input.SortOrder("random").IsValid())
+}
+
+func TestEditDistance(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		msg       string
+		dist, res int
+	}{
+		{"default", 0, 1},
+		{"negative", -2, 1},
+		{"two", 2, 2},
+		{"too large", 10, input.MaxEditDistanceLimit},
+	}
+
+	for _, v := range tests {
+		prm := input.Params{MaxEditDistance: v.dist}
+		assert.Equal(v.res, prm.EditDistance(), v.msg)
+	}
+
+	assert.True(input.MatchFuzzy.IsValid())
+	assert.False(input.NameMatch("soundex").IsValid())
+}
@@ -0,0 +1,177 @@
+package input
+
+// ItemsFilter contains criteria that limit a list of BHL items.
+// Empty fields are ignored.
+type ItemsFilter struct {
+	// YearFrom removes items published before this year.
+	YearFrom int `json:"yearFrom,omitempty"`
+
+	// YearTo removes items published after this year.
+	YearTo int `json:"yearTo,omitempty"`
+
+	// Volume keeps only items which volume contains this string, the
+	// comparison is case-insensitive. The string must not be surrounded by
+	// digits, so "1" does not match "v.10".
+	Volume string `json:"volume,omitempty"`
+}
+
+// TaxonRank is a rank of a main taxon of BHL items.
+type TaxonRank string
+
+const (
+	// RankAny uses the main taxon of an item, the taxon of the lowest rank
+	// that contains more than 50% of the item's names.
+	RankAny TaxonRank = ""
+
+	// RankKingdom uses the most prevalent kingdom of an item.
+	RankKingdom TaxonRank = "kingdom"
+
+	// RankPhylum uses the most prevalent phylum of an item.
+	RankPhylum TaxonRank = "phylum"
+
+	// RankClass uses the most prevalent class of an item.
+	RankClass TaxonRank = "class"
+
+	// RankOrder uses the most prevalent order of an item.
+	RankOrder TaxonRank = "order"
+
+	// RankFamily uses the most prevalent family of an item.
+	RankFamily TaxonRank = "family"
+
+	// RankGenus uses the most prevalent genus of an item.
+	RankGenus TaxonRank = "genus"
+)
+
+// IsValid returns true if the rank is empty or known.
+func (r TaxonRank) IsValid() bool {
+	switch r {
+	case RankAny, RankKingdom, RankPhylum, RankClass, RankOrder, RankFamily,
+		RankGenus:
+		return true
+	}
+	return false
+}
+
+// ItemsSort determines the order of items found by a taxon.
+type ItemsSort string
+
+const (
+	// ItemsByPercent sorts items by the percentage of the taxon's names,
+	// the largest go first. It is the default.
+	ItemsByPercent ItemsSort = "percent"
+
+	// ItemsByNames sorts items by the number of unique names, the largest
+	// go first.
+	ItemsByNames ItemsSort = "names"
+
+	// ItemsByYear sorts items by year from earliest to latest.
+	ItemsByYear ItemsSort = "year"
+)
+
+// IsValid returns true if the sort order is empty or known.
+func (s ItemsSort) IsValid() bool {
+	switch s {
+	case "", ItemsByPercent, ItemsByNames, ItemsByYear:
+		return true
+	}
+	return false
+}
+
+// ItemsQuery contains parameters for finding BHL items where a taxon is
+// the most prevalent one.
+type ItemsQuery struct {
+	// Taxon is the name of a taxon, for example `Lepidoptera`.
+	Taxon string `json:"taxon"`
+
+	// Rank is the rank of the taxon. If it is empty, the taxon is compared
+	// with the main taxon of items.
+	Rank TaxonRank `json:"rank,omitempty"`
+
+	// MinPercent removes items where the taxon has a smaller percentage
+	// of names.
+	MinPercent int `json:"minPercent,omitempty"`
+
+	// MinNamesNum removes items with a smaller number of unique names.
+	MinNamesNum int `json:"minNamesNum,omitempty"`
+
+	// ItemsFilter limits items by years and volume.
+	ItemsFilter
+
+	// SortBy sets the order of items: `percent` (default), `names` or
+	// `year`.
+	SortBy ItemsSort `json:"sortBy,omitempty"`
+
+	// Limit is the maximum number of returned items, zero means no limit.
+	Limit int `json:"limit,omitempty"`
+
+	// Offset is the number of items to skip.
+	Offset int `json:"offset,omitempty"`
+}
+
+// ItemsOption sets a field of ItemsQuery.
+type ItemsOption func(*ItemsQuery)
+
+// OptItemsRank sets the rank of the taxon.
+func OptItemsRank(r TaxonRank) ItemsOption {
+	return func(q *ItemsQuery) {
+		q.Rank = r
+	}
+}
+
+// OptItemsMinPercent sets the minimum percentage of the taxon's names.
+func OptItemsMinPercent(i int) ItemsOption {
+	return func(q *ItemsQuery) {
+		q.MinPercent = i
+	}
+}
+
+// OptItemsMinNamesNum sets the minimum number of unique names in items.
+func OptItemsMinNamesNum(i int) ItemsOption {
+	return func(q *ItemsQuery) {
+		q.MinNamesNum = i
+	}
+}
+
+// OptItemsYearFrom removes items published before the year.
+func OptItemsYearFrom(i int) ItemsOption {
+	return func(q *ItemsQuery) {
+		q.YearFrom = i
+	}
+}
+
+// OptItemsYearTo removes items published after the year.
+func OptItemsYearTo(i int) ItemsOption {
+	return func(q *ItemsQuery) {
+		q.YearTo = i
+	}
+}
+
+// OptItemsSortBy sets the order of items.
+func OptItemsSortBy(s ItemsSort) ItemsOption {
+	return func(q *ItemsQuery) {
+		q.SortBy = s
+	}
+}
+
+// OptItemsLimit sets the maximum number of items.
+func OptItemsLimit(i int) ItemsOption {
+	return func(q *ItemsQuery) {
+		q.Limit = i
+	}
+}
+
+// OptItemsOffset sets the number of items to skip.
+func OptItemsOffset(i int) ItemsOption {
+	return func(q *ItemsQuery) {
+		q.Offset = i
+	}
+}
+
+// NewItemsQuery creates a query for items of a taxon.
+func NewItemsQuery(taxon string, opts ...ItemsOption) ItemsQuery {
+	res := ItemsQuery{Taxon: taxon}
+	for _, opt := range opts {
+		opt(&res)
+	}
+	return res
+}
@@ -0,0 +1,43 @@
+package input
+
+// NameMatch determines how a name is compared to names found in BHL.
+type NameMatch string
+
+const (
+	// MatchExact finds names with the same canonical form.
+	MatchExact NameMatch = "exact"
+
+	// MatchStem finds names with the same stemmed canonical form, for
+	// example names with different gender of the epithet.
+	MatchStem NameMatch = "stem"
+
+	// MatchFuzzy finds names of the same genus with canonical forms that
+	// differ by no more than MaxEditDistance edits.
+	MatchFuzzy NameMatch = "fuzzy"
+)
+
+// MaxEditDistanceLimit is the largest edit distance allowed for fuzzy
+// matching. Larger distances return too many false positives.
+const MaxEditDistanceLimit = 3
+
+// IsValid returns true if the name match is empty or known.
+func (nm NameMatch) IsValid() bool {
+	switch nm {
+	case "", MatchExact, MatchStem, MatchFuzzy:
+		return true
+	}
+	return false
+}
+
+// EditDistance returns the maximum edit distance for fuzzy matching.
+// The default is 1, and it cannot exceed MaxEditDistanceLimit.
+func (p Params) EditDistance() int {
+	switch {
+	case p.MaxEditDistance <= 0:
+		return 1
+	case p.MaxEditDistance > MaxEditDistanceLimit:
+		return MaxEditDistanceLimit
+	default:
+		return p.MaxEditDistance
+	}
+}
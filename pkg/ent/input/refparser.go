@@ -83,23 +83,44 @@ func parsePages(ref string) []int {
 	return []int{0, 0}
 }
 
-func parseRefString(inp *Input) {
-	if inp.Reference == nil {
-		return
+// ParseRefString extracts years, volume and pages from a reference string.
+// Fields that are not found are set to zero.
+func ParseRefString(ref string) Reference {
+	years := parseYears(ref)
+	pages := parsePages(ref)
+	return Reference{
+		RefString:    ref,
+		RefYearStart: years[0],
+		RefYearEnd:   years[1],
+		Volume:       parseVolume(ref),
+		PageStart:    pages[0],
+		PageEnd:      pages[1],
 	}
-	if inp.PageStart == 0 {
-		pages := parsePages(inp.RefString)
-		inp.PageStart = pages[0]
-		inp.PageEnd = pages[1]
+}
+
+// ParseMissing fills empty pages, years and volume of the reference with
+// data found in its RefString.
+func (r *Reference) ParseMissing() {
+	if r.PageStart == 0 {
+		pages := parsePages(r.RefString)
+		r.PageStart = pages[0]
+		r.PageEnd = pages[1]
 	}
 
-	if inp.RefYearStart == 0 {
-		years := parseYears(inp.RefString)
-		inp.RefYearStart = years[0]
-		inp.RefYearEnd = years[1]
+	if r.RefYearStart == 0 {
+		years := parseYears(r.RefString)
+		r.RefYearStart = years[0]
+		r.RefYearEnd = years[1]
 	}
 
-	if inp.Volume == 0 {
-		inp.Volume = parseVolume(inp.RefString)
+	if r.Volume == 0 {
+		r.Volume = parseVolume(r.RefString)
+	}
+}
+
+func parseRefString(inp *Input) {
+	if inp.Reference == nil {
+		return
 	}
+	inp.Reference.ParseMissing()
 }
@@ -98,3 +98,31 @@ func TestParseYears(t *testing.T) {
 		assert.Equal(years, v.years, v.msg)
 	}
 }
+
+func TestParseRefString(t *testing.T) {
+	assert := assert.New(t)
+	ref := "Courtec. & P. Roux. In: Docums Mycol. 34:50-51. (2008-2009)."
+	res := ParseRefString(ref)
+	assert.Equal(ref, res.RefString)
+	assert.Equal(2008, res.RefYearStart)
+	assert.Equal(2009, res.RefYearEnd)
+	assert.Equal(34, res.Volume)
+	assert.Equal(50, res.PageStart)
+	assert.Equal(51, res.PageEnd)
+
+	res = ParseRefString("Docums Mycol.")
+	assert.Equal(Reference{RefString: "Docums Mycol."}, res)
+}
+
+func TestParseMissing(t *testing.T) {
+	assert := assert.New(t)
+	ref := Reference{
+		RefString: "Ann. Mag. Nat. Hist. (6) 12: 188-189 (1893)",
+		Volume:    11,
+	}
+	ref.ParseMissing()
+	assert.Equal(11, ref.Volume)
+	assert.Equal(1893, ref.RefYearStart)
+	assert.Equal(188, ref.PageStart)
+	assert.Equal(189, ref.PageEnd)
+}
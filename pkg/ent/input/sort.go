@@ -0,0 +1,48 @@
+package input
+
+// SortOrder determines the order of references in the output.
+type SortOrder string
+
+const (
+	// SortYear sorts references by year from earliest to latest.
+	SortYear SortOrder = "year"
+
+	// SortYearDesc sorts references by year from latest to earliest.
+	SortYearDesc SortOrder = "year_desc"
+
+	// SortOdds sorts references by the odds of the match, the best
+	// matches go first. It is used only for scored references.
+	SortOdds SortOrder = "odds"
+
+	// SortTitle sorts references alphabetically by the title name.
+	SortTitle SortOrder = "title"
+
+	// SortPage sorts references by items and by the order of pages
+	// within an item.
+	SortPage SortOrder = "page"
+)
+
+// IsValid returns true if the sort order is empty or known.
+func (so SortOrder) IsValid() bool {
+	switch so {
+	case "", SortYear, SortYearDesc, SortOdds, SortTitle, SortPage:
+		return true
+	}
+	return false
+}
+
+// Order returns the sort order that should be used for the results. If
+// SortBy is not set, scored results are sorted by odds, other results
+// are sorted by year according to SortDesc.
+func (p Params) Order(isScored bool) SortOrder {
+	if p.SortBy != "" {
+		return p.SortBy
+	}
+	if isScored {
+		return SortOdds
+	}
+	if p.SortDesc {
+		return SortYearDesc
+	}
+	return SortYear
+}
@@ -0,0 +1,52 @@
+package reffnd
+
+import "errors"
+
+// Kinds of errors returned by RefFinder. Use errors.Is to check the kind
+// of an error.
+var (
+	// ErrNotFound means that requested data do not exist.
+	ErrNotFound = errors.New("not found")
+
+	// ErrInvalidInput means that the request cannot be processed because of
+	// malformed or missing data.
+	ErrInvalidInput = errors.New("invalid input")
+
+	// ErrUnavailable means that the storage backend cannot be reached.
+	ErrUnavailable = errors.New("backend unavailable")
+)
+
+// Error is an error returned by RefFinder. It contains the kind of the
+// error, a message that can be shown to users, and the underlying error.
+type Error struct {
+	// Kind is one of ErrNotFound, ErrInvalidInput, ErrUnavailable.
+	Kind error
+
+	// Message describes the problem to users.
+	Message string
+
+	// Err is the underlying error, can be nil.
+	Err error
+}
+
+// NewError creates a new Error of a given kind.
+func NewError(kind error, msg string, err error) error {
+	return &Error{Kind: kind, Message: msg, Err: err}
+}
+
+// Error implements error interface.
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	return e.Message + ": " + e.Err.Error()
+}
+
+// Unwrap allows errors.Is and errors.As to reach both the kind and the
+// underlying error.
+func (e *Error) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Err}
+}
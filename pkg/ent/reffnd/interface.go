@@ -0,0 +1,102 @@
+package reffnd
+
+import (
+	"context"
+
+	"github.com/gnames/bhlnames/pkg/config"
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/input"
+)
+
+// RefFinder interface contains methods to find BHL references according to
+// input. The context allows to cancel database queries, for example when
+// a client disconnects.
+type RefFinder interface {
+	// ReferencesByName takes input with name and a reference
+	// and returns back back BHL references that match the input.
+	ReferencesByName(
+		ctx context.Context,
+		inp input.Input,
+		cfg config.Config,
+	) (*bhl.RefsByName, error)
+
+	// EmptyNameRefs returns empty non-nil result.
+	EmptyNameRefs(inp input.Input) *bhl.RefsByName
+
+	// RefByPageID returns a reference for a given pageID.
+	RefByPageID(ctx context.Context, pageID int) (*bhl.Reference, error)
+
+	// RefsByExtID returns references for a given external ID and data-source ID.
+	// If allRefs is true, it returns all cached references for the external ID.
+	// Otherwise it returns only the best match.
+	RefsByExtID(
+		ctx context.Context,
+		extID string,
+		data_source_id int,
+	) (*bhl.RefsByName, error)
+
+	// ItemStats returns metadata for a given itemID as well as the
+	// taxonomic statistics for the item. If taxaLimit is positive, the
+	// taxonomic distribution contains only this number of the largest taxa
+	// for each rank, otherwise it is complete.
+	ItemStats(ctx context.Context, itemID, taxaLimit int) (*bhl.Item, error)
+
+	// ItemsByTaxon returns a collection of BHL items where the taxon of
+	// the query is the most prevalent one at the query's rank.
+	ItemsByTaxon(ctx context.Context, iq input.ItemsQuery) ([]*bhl.Item, error)
+
+	// Close cleans up all the database, key-value store, files locks and blocks,
+	// releasing resources for the next usage of the program.
+	Close()
+}
+
+// Optional lookups are kept out of RefFinder, so implementations outside
+// of BHLnames do not break when new lookups are added. BHLnames checks if
+// its RefFinder implements them and returns an ErrUnavailable error if it
+// does not.
+
+// NameLister finds names detected in pages, items and parts.
+type NameLister interface {
+	// PageNames returns all names detected on a page with their offsets
+	// and matching data.
+	PageNames(ctx context.Context, pageID int) (*bhl.PageNames, error)
+
+	// ItemNames returns unique names detected in an item.
+	ItemNames(ctx context.Context, itemID int) (*bhl.NameList, error)
+
+	// PartNames returns unique names detected on pages of a part.
+	PartNames(ctx context.Context, partID int) (*bhl.NameList, error)
+}
+
+// TitleFinder finds metadata and items of titles.
+type TitleFinder interface {
+	// Title returns metadata of a title and the taxonomic profile
+	// aggregated from statistics of its items.
+	Title(ctx context.Context, titleID int) (*bhl.Title, error)
+
+	// TitleItems returns items of a title that satisfy the filter.
+	TitleItems(
+		ctx context.Context,
+		titleID int,
+		f input.ItemsFilter,
+	) ([]*bhl.Item, error)
+}
+
+// CitationFinder finds pages for references without names.
+type CitationFinder interface {
+	// ReferencesByCitation returns candidate pages for a reference without
+	// a name. The pages are searched in items of the given titles and
+	// are selected by volume, year and pages of the reference.
+	ReferencesByCitation(
+		ctx context.Context,
+		ref input.Reference,
+		titleIDs []int,
+	) ([]*bhl.ReferenceName, error)
+}
+
+// DataInformer provides the provenance of the data.
+type DataInformer interface {
+	// DataInfo returns datasets the data were built from and the latest
+	// builds of the data.
+	DataInfo(ctx context.Context) (*bhl.DataInfo, error)
+}
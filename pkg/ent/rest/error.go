@@ -0,0 +1,18 @@
+package rest
+
+// @Description ErrorResponse is returned by the API when a request fails.
+type ErrorResponse struct {
+	// Status is the HTTP status code of the response.
+	Status int `json:"status" example:"404"`
+
+	// Code is a machine-readable code of the error, derived from the HTTP
+	// status.
+	Code string `json:"code" example:"not_found"`
+
+	// Message describes the error.
+	Message string `json:"message" example:"page 123 is not found"`
+
+	// RequestID is the ID of the request, it helps to find the request in
+	// the server logs.
+	RequestID string `json:"requestId,omitempty" example:"Hq3Xcc1bwsRZQVVvDnJmkgbgjEkZaaYL"`
+}
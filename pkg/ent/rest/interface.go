@@ -0,0 +1,13 @@
+package rest
+
+import "net/http"
+
+// REST interface provides methods to run API server of BHLnames.
+type REST interface {
+	// Run starts the API server.
+	Run()
+
+	// Handler returns the HTTP handler of the API. It allows to serve the
+	// API by other HTTP servers, for example in tests.
+	Handler() http.Handler
+}
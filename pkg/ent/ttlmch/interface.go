@@ -0,0 +1,22 @@
+package ttlmch
+
+import (
+	"context"
+
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
+)
+
+// TitleMatcher allows to make a match of a journal/book title with a
+// biodiversity reference.
+type TitleMatcher interface {
+	// TitlesBHL takes a reference-string and returns back IDs of matched
+	// BHL titles.
+	TitlesBHL(ctx context.Context, refString string) (map[int][]string, error)
+
+	// RefTitles takes a reference-string and returns its abbreviation,
+	// abbreviations of BHL titles found in it and the matched titles.
+	RefTitles(ctx context.Context, refString string) (*bhl.RefTitles, error)
+
+	// Close cleans database connection.
+	Close()
+}
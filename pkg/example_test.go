@@ -0,0 +1,85 @@
+package bhlnames_test
+
+import (
+	"context"
+	"fmt"
+
+	bhlnames "github.com/gnames/bhlnames/pkg"
+	"github.com/gnames/bhlnames/pkg/config"
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/input"
+)
+
+// memFinder is a RefFinder that keeps references in memory.
+type memFinder struct {
+	refs map[string][]*bhl.ReferenceName
+}
+
+func (m memFinder) ReferencesByName(
+	_ context.Context,
+	inp input.Input,
+	_ config.Config,
+) (*bhl.RefsByName, error) {
+	res := m.EmptyNameRefs(inp)
+	res.Canonical = inp.CanonicalSimple
+	res.References = append(res.References, m.refs[inp.CanonicalSimple]...)
+	return res, nil
+}
+
+func (m memFinder) EmptyNameRefs(inp input.Input) *bhl.RefsByName {
+	return &bhl.RefsByName{Meta: bhl.Meta{Input: inp}}
+}
+
+func (m memFinder) RefByPageID(context.Context, int) (*bhl.Reference, error) {
+	return nil, nil
+}
+
+func (m memFinder) RefsByExtID(
+	context.Context,
+	string,
+	int,
+) (*bhl.RefsByName, error) {
+	return nil, nil
+}
+
+func (m memFinder) ItemStats(context.Context, int, int) (*bhl.Item, error) {
+	return nil, nil
+}
+
+func (m memFinder) ItemsByTaxon(
+	context.Context,
+	input.ItemsQuery,
+) ([]*bhl.Item, error) {
+	return nil, nil
+}
+
+func (m memFinder) Close() {}
+
+func Example() {
+	rf := memFinder{refs: map[string][]*bhl.ReferenceName{
+		"Pardosa moesta": {
+			{Reference: bhl.Reference{PageID: 2, YearAggr: 1910}},
+			{Reference: bhl.Reference{PageID: 1, YearAggr: 1892}},
+		},
+	}}
+
+	cfg := config.New()
+	bn := bhlnames.New(cfg, bhlnames.OptRefFinder(rf))
+	defer bn.Close()
+
+	inp := input.New(bn.ParserPool(),
+		input.OptNameString("Pardosa moesta Banks, 1892"),
+		input.OptSortBy(input.SortYear),
+	)
+	res, err := bn.NameRefs(context.Background(), inp)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, v := range res.References {
+		fmt.Println(v.YearAggr, v.PageID)
+	}
+	// Output:
+	// 1892 1
+	// 1910 2
+}
@@ -3,21 +3,32 @@ package bhlnames
 import (
 	"context"
 
-	"github.com/gnames/bhlnames/internal/ent/bhl"
-	"github.com/gnames/bhlnames/internal/ent/builder"
-	"github.com/gnames/bhlnames/internal/ent/col"
-	"github.com/gnames/bhlnames/internal/ent/input"
 	"github.com/gnames/bhlnames/pkg/config"
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/builder"
+	"github.com/gnames/bhlnames/pkg/ent/cache"
+	"github.com/gnames/bhlnames/pkg/ent/col"
+	"github.com/gnames/bhlnames/pkg/ent/input"
 	"github.com/gnames/gnparser"
 )
 
 // BHLnames provides methods for finding references for scientific names
-// in the Biodiversity Heritage Library (BHL).
+// in the Biodiversity Heritage Library (BHL). Methods that query the
+// database take a context, cancelling it stops the running queries.
 type BHLnames interface {
 	// Initialize downloads of essential BHL data (corpus metadata + names) and
-	// prepares the internal storage for efficient querying.
+	// prepares the internal storage for efficient querying. The new data
+	// replaces the old one only after the build is complete and passes data
+	// quality checks.
 	Initialize(builder.Builder) error
 
+	// Update applies changes of the newest BHL and bhlindex dumps to the
+	// current data without a full rebuild.
+	Update(builder.Builder) (builder.Report, error)
+
+	// Rollback replaces the current data with the previous build.
+	Rollback(builder.Builder) error
+
 	// InitCoLNomenEvents fetches Catalogue of Life (CoL) data. It finds
 	// nomenclatural events in BHL, cross-referencing them with names and
 	// references from CoL.
@@ -25,12 +36,14 @@ type BHLnames interface {
 
 	// NameRefs accepts a scientific name and optional reference. It returns a
 	// collection of matching references found within the BHL corpus.
-	NameRefs(input.Input) (*bhl.RefsByName, error)
+	NameRefs(context.Context, input.Input) (*bhl.RefsByName, error)
 
 	// NameRefsStream processes a stream of inputs (scientific names + optional
 	// references). It returns a stream of corresponding reference collections
 	// found in BHL. Designed for asynchronous processing and large-scale
-	// requests.
+	// requests. If an input fails, its result has the Error field set and
+	// the processing of other inputs continues. The stream stops with an
+	// error only if the context is cancelled.
 	NameRefsStream(
 		ctx context.Context,
 		chIn <-chan input.Input,
@@ -38,25 +51,88 @@ type BHLnames interface {
 	) error
 
 	// RefByPageID returns  BHL metadata for a given pageID.
-	RefByPageID(pageID int) (*bhl.Reference, error)
+	RefByPageID(ctx context.Context, pageID int) (*bhl.Reference, error)
+
+	// PageNames returns all scientific names detected on a BHL page,
+	// together with their offsets in the page text, matched and current
+	// canonical forms, classification and nomenclatural annotations.
+	PageNames(ctx context.Context, pageID int) (*bhl.PageNames, error)
 
 	// RefsByExtID returns BHL metadata for a given external ID and data-source
 	// ID. If allRefs is true, it returns all cached references for
-	// the external ID. Otherwise it returns only the best match.
+	// the external ID. Otherwise it returns only the best match. An unknown
+	// external ID returns an ErrNotFound error.
 	RefsByExtID(
+		ctx context.Context,
 		extID string,
 		dataSourceID int,
 		allRefs bool,
 	) (*bhl.RefsByName, error)
 
 	// ItemStats returns metadata for a given itemID as well as the
-	// statisics about taxonomic groups mentioned in the item.
-	ItemStats(itemID int) (*bhl.Item, error)
+	// statisics about taxonomic groups mentioned in the item. The statistics
+	// include the distribution of names among taxa from kingdom to genus.
+	// If taxaLimit is positive, the distribution contains only this number
+	// of the largest taxa for each rank.
+	ItemStats(ctx context.Context, itemID, taxaLimit int) (*bhl.Item, error)
+
+	// ItemNames returns unique scientific names detected in a BHL item with
+	// the number of their occurrences, first and last pages, classification
+	// and nomenclatural annotations.
+	ItemNames(ctx context.Context, itemID int) (*bhl.NameList, error)
+
+	// PartNames returns unique scientific names detected in a BHL part
+	// (usually a scientific paper) in the same format as ItemNames.
+	PartNames(ctx context.Context, partID int) (*bhl.NameList, error)
+
+	// Title returns metadata of a BHL title (a journal or a book) and its
+	// taxonomic profile, aggregated from statistics of the title's items.
+	Title(ctx context.Context, titleID int) (*bhl.Title, error)
+
+	// TitleItems returns items (volumes) of a BHL title with their
+	// taxonomic statistics. The filter limits items by years and volume.
+	TitleItems(
+		ctx context.Context,
+		titleID int,
+		f input.ItemsFilter,
+	) ([]*bhl.Item, error)
 
 	// ItemsByTaxon returns a collection of BHL items that have provided
-	// taxon as the main taxon mentioned in the item. The taxon is a main
-	// taxon if its species make more than 50% of all species in the item.
-	ItemsByTaxon(taxon string) ([]*bhl.Item, error)
+	// taxon as the main taxon mentioned in the item. Without a rank the
+	// taxon is a main taxon if its species make more than 50% of all
+	// species in the item. With a rank (phylum, class, order etc.) the taxon
+	// is the most prevalent one at this rank. The query also sets the
+	// minimal percentage of the taxon, the minimal number of names, years,
+	// the order and the page of results.
+	ItemsByTaxon(ctx context.Context, iq input.ItemsQuery) ([]*bhl.Item, error)
+
+	// ResolveReference finds BHL pages for a bibliographic citation without
+	// a name. Candidate titles are found by abbreviations of the citation,
+	// their items are selected by volume and year, and pages by page
+	// numbers. Candidates are ranked by the same year, volume, page and
+	// title scores that are used for references of names, the best match
+	// goes first. It needs a TitleMatcher.
+	ResolveReference(
+		ctx context.Context,
+		ref input.Reference,
+	) (*bhl.RefsByName, error)
+
+	// ParseRef extracts years, volume and pages from a reference string and
+	// finds BHL titles that match it by abbreviations. The result shows
+	// the abbreviation patterns and matched titles, it helps to find out
+	// why a reference was not resolved. Titles are not matched if
+	// BHLnames was created without a TitleMatcher.
+	ParseRef(ctx context.Context, refString string) (*bhl.ParsedRef, error)
+
+	// DataInfo returns datasets the data were built from (source URLs, file
+	// sizes, checksums and dates of dumps) and the latest builds with the
+	// bhlnames version, duration and numbers of rows in tables. It allows
+	// to cite the exact version of the data behind a result.
+	DataInfo(ctx context.Context) (*bhl.DataInfo, error)
+
+	// CacheStats returns the number of hits and misses of the NameRefs
+	// cache.
+	CacheStats() cache.Stats
 
 	// Config returns the current configuration used by the BHLnames instance.
 	Config() config.Config
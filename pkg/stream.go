@@ -5,8 +5,8 @@ import (
 	"log/slog"
 	"sync"
 
-	"github.com/gnames/bhlnames/internal/ent/bhl"
-	"github.com/gnames/bhlnames/internal/ent/input"
+	"github.com/gnames/bhlnames/pkg/ent/bhl"
+	"github.com/gnames/bhlnames/pkg/ent/input"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -57,10 +57,18 @@ func (bn bhlnames) workerNameRefs(
 			if !ok {
 				return nil
 			}
-			res, err := bn.NameRefs(in)
+			res, err := bn.NameRefs(ctx, in)
 			if err != nil {
-				slog.Error("Cannot get references", "error", err)
-				return err
+				// the whole stream stops only if it is cancelled, errors of
+				// one input are reported in the Error field of its result.
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				slog.Warn("Cannot get references", "id", in.ID, "error", err)
+				if res == nil {
+					res = bn.rf.EmptyNameRefs(in)
+				}
+				res.Error = err.Error()
 			}
 			select {
 			case <-ctx.Done():